@@ -0,0 +1,115 @@
+package rpcdiff
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+)
+
+// Attestation is a signed diff json
+type Attestation struct {
+	Payload   []byte `json:"payload"`   // diff json
+	Signature []byte `json:"signature"` // ed25519 signature of payload
+}
+
+// signDiff signs diff json with PKCS#8 PEM ed25519 private key
+func signDiff(diff *Diff, keyPEM []byte) (*Attestation, error) {
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, errors.New("private key is not PEM encoded")
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse private key error: %w", err)
+	}
+
+	privateKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, errors.New("private key is not ed25519")
+	}
+
+	payload, err := json.Marshal(diff)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Attestation{Payload: payload, Signature: ed25519.Sign(privateKey, payload)}, nil
+}
+
+// verifyAttestation checks signature with PKIX PEM ed25519 public key and returns signed diff
+func verifyAttestation(att *Attestation, keyPEM []byte) (*Diff, error) {
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, errors.New("public key is not PEM encoded")
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse public key error: %w", err)
+	}
+
+	publicKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, errors.New("public key is not ed25519")
+	}
+
+	if !ed25519.Verify(publicKey, att.Payload, att.Signature) {
+		return nil, errors.New("invalid signature")
+	}
+
+	var diff Diff
+	if err := json.Unmarshal(att.Payload, &diff); err != nil {
+		return nil, fmt.Errorf("parse diff error: %w", err)
+	}
+
+	return &diff, nil
+}
+
+// WriteAttestation signs diff with key file and writes attestation json
+func WriteAttestation(path, keyPath string, diff *Diff) error {
+	if keyPath == "" {
+		return errors.New("sign key is not set")
+	}
+
+	key, err := ioutil.ReadFile(keyPath)
+	if err != nil {
+		return err
+	}
+
+	att, err := signDiff(diff, key)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(att)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+// ReadAttestation reads attestation json and verifies it with key file
+func ReadAttestation(path, keyPath string) (*Diff, error) {
+	key, err := ioutil.ReadFile(keyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var att Attestation
+	if err := json.Unmarshal(data, &att); err != nil {
+		return nil, fmt.Errorf("parse attestation error: %w", err)
+	}
+
+	return verifyAttestation(&att, key)
+}
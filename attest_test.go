@@ -0,0 +1,53 @@
+package rpcdiff
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+)
+
+func TestAttestation(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	publicDER, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	privatePEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER})
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})
+
+	diff, err := NewDiff("testdata/openrpc_old.json", "testdata/openrpc_new.json", Options{})
+	if err != nil {
+		t.Fatalf("new diff error: %s", err)
+	}
+
+	att, err := signDiff(diff, privatePEM)
+	if err != nil {
+		t.Fatalf("sign error: %s", err)
+	}
+
+	verified, err := verifyAttestation(att, publicPEM)
+	if err != nil {
+		t.Fatalf("verify error: %s", err)
+	}
+
+	if verified.Criticality != diff.Criticality || verified.Score != diff.Score || len(verified.Changes) != len(diff.Changes) {
+		t.Errorf("verified diff = %v %v, want %v %v", verified.Criticality, verified.Score, diff.Criticality, diff.Score)
+	}
+
+	att.Payload = []byte(`{"criticality":"NON_BREAKING"}`)
+	if _, err := verifyAttestation(att, publicPEM); err == nil {
+		t.Errorf("tampered attestation must fail")
+	}
+}
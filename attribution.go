@@ -0,0 +1,125 @@
+package rpcdiff
+
+import (
+	"sort"
+	"strings"
+
+	openrpc "github.com/vmkteam/meta-schema/v2"
+)
+
+// attributeResults returns MethodResultType change for every method which result references changed components schema
+// directly or through other schemas. Criticality is the most critical change of schema compared as output.
+func attributeResults(options Options, changes []Change, oldJSON, newJSON []byte, oldDoc, newDoc *openrpc.OpenrpcDocument) []Change {
+	changed := map[string]bool{}
+	for _, c := range changes {
+		if len(c.Path) > 3 && c.Path[0] == "components" && c.Path[1] == "schemas" {
+			changed[c.Path[2]] = true
+		}
+	}
+
+	if len(changed) == 0 {
+		return nil
+	}
+
+	deps := map[string]map[string]bool{}
+	collectDependencies(newJSON, deps)
+
+	doc, err := decodeObject(newJSON)
+	if err != nil {
+		return nil
+	}
+
+	components, _ := doc["components"].(map[string]interface{})
+	descriptors, _ := components["contentDescriptors"].(map[string]interface{})
+
+	taxonomy := options.taxonomy()
+	levels := map[string]CriticalityLevel{}
+
+	var result []Change
+	methods, _ := doc["methods"].([]interface{})
+	for _, v := range methods {
+		method, _ := v.(map[string]interface{})
+		name, _ := method["name"].(string)
+		if name == "" || findMethod(oldDoc, name) == nil {
+			continue
+		}
+
+		for _, schema := range reachableSchemas(method["result"], descriptors, deps) {
+			if !changed[schema] {
+				continue
+			}
+
+			level, ok := levels[schema]
+			if !ok {
+				level = outputLevel(options, taxonomy, schema, oldDoc, newDoc)
+				levels[schema] = level
+			}
+
+			if level == "" {
+				continue
+			}
+
+			result = append(result, Change{
+				Path:        []string{"methods", name, "result", "schemas", schema},
+				Type:        Changed,
+				Object:      MethodResultType,
+				Criticality: level,
+				Old:         schemaRefPrefix + schema,
+				New:         schemaRefPrefix + schema,
+			})
+		}
+	}
+
+	return result
+}
+
+// reachableSchemas returns sorted names of components schemas referenced by value directly,
+// through content descriptors or through other schemas
+func reachableSchemas(value interface{}, descriptors map[string]interface{}, deps map[string]map[string]bool) []string {
+	seen := map[string]bool{}
+	queue := schemaDependencies(value, descriptors)
+
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		for dep := range deps[id] {
+			queue = append(queue, dep)
+		}
+	}
+
+	result := make([]string, 0, len(seen))
+	for id := range seen {
+		result = append(result, strings.TrimPrefix(id, "components.schemas."))
+	}
+	sort.Strings(result)
+
+	return result
+}
+
+// outputLevel compares components schema as output and returns the most critical level of its contract changes,
+// empty for schemas with meta changes only or missing in one of documents
+func outputLevel(options Options, taxonomy Taxonomy, name string, oldDoc, newDoc *openrpc.OpenrpcDocument) CriticalityLevel {
+	oldSchema := resolveSchema(schemaRefPrefix+name, oldDoc)
+	newSchema := resolveSchema(schemaRefPrefix+name, newDoc)
+	if oldSchema == nil || newSchema == nil {
+		return ""
+	}
+
+	var level CriticalityLevel
+	for _, c := range compareJSONSchema(options, oldSchema, newSchema, []string{"components", "schemas", name}, directionOutput, oldDoc, newDoc) {
+		if isInfoChange(c) {
+			continue
+		}
+
+		if level == "" || taxonomy.rank(c.Criticality) < taxonomy.rank(level) {
+			level = c.Criticality
+		}
+	}
+
+	return level
+}
@@ -0,0 +1,42 @@
+package rpcdiff
+
+import (
+	"testing"
+)
+
+func Test_attributeResults(t *testing.T) {
+	old := []byte(`{"openrpc":"1.2.6","info":{"title":"api","version":"1.0.0"},"methods":[
+		{"name":"user.Get","params":[],"result":{"name":"r","schema":{"$ref":"#/components/schemas/User"}}},
+		{"name":"user.Update","params":[{"name":"u","schema":{"$ref":"#/components/schemas/Address"}}],"result":{"name":"r","schema":{"type":"boolean"}}},
+		{"name":"user.Find","params":[],"result":{"$ref":"#/components/contentDescriptors/User"}}],
+		"components":{"contentDescriptors":{"User":{"name":"User","schema":{"$ref":"#/components/schemas/User"}}},"schemas":{
+			"User":{"type":"object","properties":{"address":{"$ref":"#/components/schemas/Address"}}},
+			"Address":{"type":"object","properties":{"city":{"type":"string"},"zip":{"type":"string"}}}}}}`)
+	new := []byte(`{"openrpc":"1.2.6","info":{"title":"api","version":"1.0.1"},"methods":[
+		{"name":"user.Get","params":[],"result":{"name":"r","schema":{"$ref":"#/components/schemas/User"}}},
+		{"name":"user.Update","params":[{"name":"u","schema":{"$ref":"#/components/schemas/Address"}}],"result":{"name":"r","schema":{"type":"boolean"}}},
+		{"name":"user.Find","params":[],"result":{"$ref":"#/components/contentDescriptors/User"}}],
+		"components":{"contentDescriptors":{"User":{"name":"User","schema":{"$ref":"#/components/schemas/User"}}},"schemas":{
+			"User":{"type":"object","properties":{"address":{"$ref":"#/components/schemas/Address"}}},
+			"Address":{"type":"object","properties":{"city":{"type":"string"}}}}}}`)
+
+	diff, err := NewDiffBytes(old, new, Options{Objects: []string{string(MethodResultType)}})
+	if err != nil {
+		t.Fatalf("NewDiffBytes() error: %s", err)
+	}
+
+	if len(diff.Changes) != 2 {
+		t.Fatalf("changes = %v, want attributed changes of user.Get and user.Find", diff.Changes)
+	}
+
+	for i, method := range []string{"user.Get", "user.Find"} {
+		c := diff.Changes[i]
+		if want := `Changed result type of method "` + method + `" by changes of schema "Address"`; c.String() != want {
+			t.Errorf("change = %v, want %v", c.String(), want)
+		}
+
+		if c.Criticality != Dangerous {
+			t.Errorf("change criticality = %v, want %v", c.Criticality, Dangerous)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package rpcdiff
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"text/tabwriter"
+)
+
+// exit codes of batch modes, failed pairs take precedence over breaking ones
+const (
+	ExitBreaking = 1 // some pair has breaking changes, greater score than allowed or policy violations
+	ExitFailed   = 2 // some pair failed to read, parse or compare
+)
+
+// statuses of pairs in batch
+const (
+	statusPassed   = "passed"
+	statusBreaking = "breaking"
+	statusFailed   = "failed"
+	statusSkipped  = "skipped"
+)
+
+// Batch controls comparison of several schema pairs
+type Batch struct {
+	MaxScore int  // pair with greater score is breaking even without breaking changes
+	FailFast bool // stop after the first failed or breaking pair, pairs not started yet are skipped
+	Parallel int  // max number of pairs compared at once, values less than 1 mean sequential comparison
+}
+
+// status returns status of compared pair
+func (b Batch) status(sd ServiceDiff) string {
+	switch {
+	case sd.Error != "":
+		return statusFailed
+	case sd.Diff == nil:
+		return statusSkipped
+	case sd.Diff.Incomplete:
+		return statusFailed
+	case sd.Diff.IsBreaking(b.MaxScore):
+		return statusBreaking
+	}
+
+	return statusPassed
+}
+
+// IsBreaking reports whether diff has Breaking changes, policy violations, is incomplete or has score above maxScore.
+// Levels of custom taxonomy more critical than Breaking count as Breaking.
+func (d *Diff) IsBreaking(maxScore int) bool {
+	return d.hasBreaking() || len(d.Violations) > 0 || d.Incomplete || d.Score > maxScore
+}
+
+// hasBreaking checks that diff criticality is Breaking or more critical level of taxonomy
+func (d *Diff) hasBreaking() bool {
+	taxonomy := d.Options.taxonomy()
+	return taxonomy.rank(d.Criticality) <= taxonomy.rank(Breaking)
+}
+
+// run compares pairs of services concurrently, compare returns diff of one service.
+// Results are in order of services, panic of compare fails only its pair.
+func (b Batch) run(services []ServiceConfig, compare func(ServiceConfig) (*Diff, error)) []ServiceDiff {
+	parallel := b.Parallel
+	if parallel < 1 {
+		parallel = 1
+	}
+
+	var (
+		result  = make([]ServiceDiff, len(services))
+		sem     = make(chan struct{}, parallel)
+		stopped int32
+		wg      sync.WaitGroup
+	)
+
+	for i, service := range services {
+		result[i] = ServiceDiff{Service: service.Name, Status: statusSkipped}
+
+		sem <- struct{}{}
+		if atomic.LoadInt32(&stopped) == 1 {
+			<-sem
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, service ServiceConfig) {
+			defer func() { <-sem; wg.Done() }()
+
+			sd := ServiceDiff{Service: service.Name}
+			diff, err := recoverDiff(service, compare)
+			if err != nil {
+				sd.Error = err.Error()
+			}
+			sd.Diff = diff
+			sd.Status = b.status(sd)
+			result[i] = sd
+
+			if b.FailFast && sd.Status != statusPassed {
+				atomic.StoreInt32(&stopped, 1)
+			}
+		}(i, service)
+	}
+	wg.Wait()
+
+	return result
+}
+
+// recoverDiff compares pair of service and returns panic as error
+func recoverDiff(service ServiceConfig, compare func(ServiceConfig) (*Diff, error)) (diff *Diff, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			diff, err = nil, fmt.Errorf("comparison failed: %v", r)
+		}
+	}()
+
+	return compare(service)
+}
+
+// BatchExitCode returns exit code of batch, 0 if every pair passed
+func BatchExitCode(diffs []ServiceDiff) int {
+	code := 0
+	for _, sd := range diffs {
+		switch sd.Status {
+		case statusFailed:
+			return ExitFailed
+		case statusBreaking:
+			code = ExitBreaking
+		}
+	}
+
+	return code
+}
+
+// batchSummary renders table of pairs and aggregate verdict
+func batchSummary(diffs []ServiceDiff) string {
+	buf := strings.Builder{}
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SERVICE\tSTATUS\tCRITICALITY\tSCORE\tCHANGES")
+
+	counts := map[string]int{}
+	for _, sd := range diffs {
+		counts[sd.Status]++
+
+		criticality, score, changes := "-", "-", "-"
+		if sd.Diff != nil {
+			criticality, score, changes = sd.Diff.Criticality.String(), fmt.Sprint(sd.Diff.Score), fmt.Sprint(len(sd.Diff.Changes))
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", sd.Service, sd.Status, criticality, score, changes)
+	}
+	w.Flush()
+
+	verdict := "passed"
+	switch BatchExitCode(diffs) {
+	case ExitFailed:
+		verdict = "failed"
+	case ExitBreaking:
+		verdict = "breaking"
+	}
+
+	fmt.Fprintf(&buf, "Verdict: %s (%d passed, %d breaking, %d failed, %d skipped)\n",
+		verdict, counts[statusPassed], counts[statusBreaking], counts[statusFailed], counts[statusSkipped])
+
+	return buf.String()
+}
@@ -0,0 +1,131 @@
+package rpcdiff
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBatch_run(t *testing.T) {
+	services := []ServiceConfig{{Name: "a"}, {Name: "b"}, {Name: "c"}}
+	diffs := map[string]*Diff{
+		"a": {Criticality: NonBreaking, Score: 10},
+		"b": {Criticality: Dangerous, Score: 60},
+	}
+	compare := func(service ServiceConfig) (*Diff, error) {
+		if diff, ok := diffs[service.Name]; ok {
+			return diff, nil
+		}
+
+		return nil, fmt.Errorf("parse error")
+	}
+
+	tests := []struct {
+		name     string
+		batch    Batch
+		statuses []string
+		code     int
+	}{
+		{name: "full run", batch: Batch{MaxScore: 50}, statuses: []string{statusPassed, statusBreaking, statusFailed}, code: ExitFailed},
+		{name: "fail fast", batch: Batch{MaxScore: 50, FailFast: true}, statuses: []string{statusPassed, statusBreaking, statusSkipped}, code: ExitBreaking},
+		{name: "allowed score", batch: Batch{MaxScore: 100, FailFast: true}, statuses: []string{statusPassed, statusPassed, statusFailed}, code: ExitFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := tt.batch.run(services, compare)
+
+			var statuses []string
+			for _, sd := range result {
+				statuses = append(statuses, sd.Status)
+			}
+
+			if strings.Join(statuses, ",") != strings.Join(tt.statuses, ",") {
+				t.Errorf("run() statuses = %v, want %v", statuses, tt.statuses)
+			}
+
+			if code := BatchExitCode(result); code != tt.code {
+				t.Errorf("BatchExitCode() = %v, want %v", code, tt.code)
+			}
+		})
+	}
+}
+
+func TestBatch_status(t *testing.T) {
+	tests := []struct {
+		name string
+		diff *Diff
+		want string
+	}{
+		{name: "incomplete", diff: &Diff{Criticality: NonBreaking, Incomplete: true}, want: statusFailed},
+		{name: "breaking below max score", diff: &Diff{Criticality: Breaking, Score: 70}, want: statusBreaking},
+		{name: "dangerous above max score", diff: &Diff{Criticality: Dangerous, Score: 90}, want: statusBreaking},
+		{name: "violations", diff: &Diff{Criticality: NonBreaking, Violations: make([]Violation, 1)}, want: statusBreaking},
+		{name: "dangerous", diff: &Diff{Criticality: Dangerous, Score: 40}, want: statusPassed},
+	}
+	for _, tt := range tests {
+		if status := (Batch{MaxScore: 80}).status(ServiceDiff{Service: "users", Diff: tt.diff}); status != tt.want {
+			t.Errorf("status() of %s diff = %v, want %v", tt.name, status, tt.want)
+		}
+	}
+}
+
+func Test_batchSummary(t *testing.T) {
+	diffs := []ServiceDiff{
+		{Service: "users", Status: statusBreaking, Diff: &Diff{Criticality: Breaking, Score: 100, Changes: make([]Change, 2)}},
+		{Service: "billing", Status: statusSkipped},
+	}
+
+	want := `SERVICE  STATUS    CRITICALITY  SCORE  CHANGES
+users    breaking  breaking     100    2
+billing  skipped   -            -      -
+Verdict: breaking (0 passed, 1 breaking, 0 failed, 1 skipped)
+`
+	if got := batchSummary(diffs); got != want {
+		t.Errorf("batchSummary() = %v, want %v", got, want)
+	}
+}
+
+func TestBatch_run_parallel(t *testing.T) {
+	var services []ServiceConfig
+	for i := 0; i < 20; i++ {
+		services = append(services, ServiceConfig{Name: fmt.Sprint(i)})
+	}
+
+	var running, maxRunning int32
+	compare := func(service ServiceConfig) (*Diff, error) {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			m := atomic.LoadInt32(&maxRunning)
+			if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+
+		if service.Name == "7" {
+			panic("unexpected schema")
+		}
+
+		return &Diff{Criticality: NonBreaking}, nil
+	}
+
+	result := Batch{Parallel: 4}.run(services, compare)
+	if maxRunning > 4 {
+		t.Errorf("max running = %v, want at most %v", maxRunning, 4)
+	}
+
+	for i, sd := range result {
+		want := statusPassed
+		if i == 7 {
+			want = statusFailed
+		}
+
+		if sd.Service != services[i].Name || sd.Status != want {
+			t.Errorf("result[%d] = %v %v, want %v %v", i, sd.Service, sd.Status, services[i].Name, want)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package rpcdiff
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+)
+
+// object storage schemes of upload destination
+const (
+	s3Scheme  = "s3://"
+	gcsScheme = "gs://"
+)
+
+// splitBucket splits bucket/prefix into bucket and prefix ending with slash, prefix is empty for bucket root
+func splitBucket(path string) (bucket, prefix string) {
+	parts := strings.SplitN(path, "/", 2)
+	if len(parts) == 1 || strings.Trim(parts[1], "/") == "" {
+		return parts[0], ""
+	}
+
+	return parts[0], strings.Trim(parts[1], "/") + "/"
+}
+
+// s3Put uploads object to s3 bucket with credentials from AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and
+// AWS_SESSION_TOKEN environment variables, AWS_ENDPOINT_URL sets s3 compatible endpoint, e.g. minio
+func s3Put(bucket, key string, data []byte, contentType string) (string, error) {
+	creds := awsCredentials{
+		AccessKey:    os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretKey:    os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken: os.Getenv("AWS_SESSION_TOKEN"),
+		Region:       awsRegion(),
+	}
+	if creds.AccessKey == "" || creds.SecretKey == "" {
+		return "", fmt.Errorf("upload to s3 error: AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY are required")
+	}
+
+	// path-style urls for custom endpoints, virtual-hosted for aws
+	location := fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", bucket, creds.Region, escapePath(key))
+	if endpoint := os.Getenv("AWS_ENDPOINT_URL"); endpoint != "" {
+		location = strings.TrimSuffix(endpoint, "/") + "/" + bucket + "/" + escapePath(key)
+	}
+
+	payloadHash := digest(data)
+	_, err := httpDo(func() (*http.Request, error) {
+		req, err := http.NewRequest(http.MethodPut, location, bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", contentType)
+		creds.sign(req, payloadHash, time.Now())
+
+		return req, nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("upload to s3 error: %w", err)
+	}
+
+	return location, nil
+}
+
+// awsRegion returns region of AWS_REGION or AWS_DEFAULT_REGION, us-east-1 if both are empty
+func awsRegion() string {
+	for _, name := range []string{"AWS_REGION", "AWS_DEFAULT_REGION"} {
+		if region := os.Getenv(name); region != "" {
+			return region
+		}
+	}
+
+	return "us-east-1"
+}
+
+// awsCredentials signs s3 requests with signature version 4
+type awsCredentials struct {
+	AccessKey    string
+	SecretKey    string
+	SessionToken string
+	Region       string
+}
+
+// sign sets authorization of request signed with headers of request and host
+func (c awsCredentials) sign(req *http.Request, payloadHash string, t time.Time) {
+	amzDate := t.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if c.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", c.SessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		escapePath(req.URL.Path),
+		req.URL.Query().Encode(),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + c.Region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, digest([]byte(canonicalRequest))}, "\n")
+
+	key := []byte("AWS4" + c.SecretKey)
+	for _, part := range []string{date, c.Region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		c.AccessKey, scope, signedHeaders, hex.EncodeToString(hmacSHA256(key, stringToSign))))
+}
+
+// gcsPut uploads object to gcs bucket with access token of GOOGLE_OAUTH_ACCESS_TOKEN or gcloud,
+// STORAGE_EMULATOR_HOST sets endpoint of emulator
+func gcsPut(bucket, name string, data []byte, contentType string) (string, error) {
+	token, err := gcsToken()
+	if err != nil {
+		return "", fmt.Errorf("upload to gcs error: %w", err)
+	}
+
+	endpoint := "https://storage.googleapis.com"
+	if host := os.Getenv("STORAGE_EMULATOR_HOST"); host != "" {
+		endpoint = strings.TrimSuffix(host, "/")
+	}
+
+	upload := fmt.Sprintf("%s/upload/storage/v1/b/%s/o?uploadType=media&name=%s", endpoint, url.PathEscape(bucket), url.QueryEscape(name))
+	_, err = httpDo(func() (*http.Request, error) {
+		req, err := http.NewRequest(http.MethodPost, upload, bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set("Authorization", "Bearer "+token)
+
+		return req, nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("upload to gcs error: %w", err)
+	}
+
+	return "https://storage.googleapis.com/" + bucket + "/" + escapePath(name), nil
+}
+
+// gcsToken returns access token of GOOGLE_OAUTH_ACCESS_TOKEN or printed by gcloud
+func gcsToken() (string, error) {
+	if token := os.Getenv("GOOGLE_OAUTH_ACCESS_TOKEN"); token != "" {
+		return token, nil
+	}
+
+	out, err := exec.Command("gcloud", "auth", "print-access-token").Output()
+	if err != nil {
+		return "", fmt.Errorf("set GOOGLE_OAUTH_ACCESS_TOKEN or login with gcloud: %w", err)
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
+
+// escapePath escapes every byte of path except unreserved characters and slashes
+func escapePath(path string) string {
+	var buf strings.Builder
+	for _, b := range []byte(path) {
+		switch {
+		case 'a' <= b && b <= 'z', 'A' <= b && b <= 'Z', '0' <= b && b <= '9', strings.IndexByte("-_.~/", b) >= 0:
+			buf.WriteByte(b)
+		default:
+			fmt.Fprintf(&buf, "%%%02X", b)
+		}
+	}
+
+	return buf.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
@@ -0,0 +1,85 @@
+package rpcdiff
+
+import (
+	"fmt"
+	"sort"
+
+	openrpc "github.com/vmkteam/meta-schema/v2"
+)
+
+// Budget is a set of complexity limits evaluated over new schema and diff, zero limits are not checked.
+// Exceeded limits are reported as warnings and never fail comparison.
+type Budget struct {
+	MaxParams        int `json:"maxParams,omitempty"`        // params of every method in new schema
+	MaxNewProperties int `json:"maxNewProperties,omitempty"` // properties added to every components schema in one release
+	MaxNewMethods    int `json:"maxNewMethods,omitempty"`    // methods added in one release
+}
+
+// Budget rules
+const (
+	RuleBudgetParams        = "budget-params"
+	RuleBudgetNewProperties = "budget-new-properties"
+	RuleBudgetNewMethods    = "budget-new-methods"
+)
+
+// Evaluate checks new schema and its changes against budget
+func (b *Budget) Evaluate(changes []Change, newDoc *openrpc.OpenrpcDocument) []Violation {
+	if b == nil {
+		return nil
+	}
+
+	var warnings []Violation
+	if b.MaxParams > 0 {
+		for _, method := range newDoc.Methods {
+			if method.MethodObject == nil || len(method.Params) <= b.MaxParams {
+				continue
+			}
+
+			warnings = append(warnings, Violation{
+				Rule:    RuleBudgetParams,
+				Path:    []string{"methods", method.Name, "params"},
+				Message: fmt.Sprintf(`method "%s" has %d params, budget is %d`, method.Name, len(method.Params), b.MaxParams),
+			})
+		}
+	}
+
+	newProperties, newMethods := map[string]int{}, 0
+	for _, c := range changes {
+		switch {
+		case c.Type == Added && c.Object == Method && len(c.Path) == 2:
+			newMethods++
+		case c.Type == Added && c.Object == ComponentsSchemaProperty && len(c.Path) == 5 && c.Path[3] == "properties":
+			newProperties[c.Path[2]]++
+		}
+	}
+
+	if b.MaxNewProperties > 0 {
+		names := make([]string, 0, len(newProperties))
+		for name := range newProperties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			if newProperties[name] <= b.MaxNewProperties {
+				continue
+			}
+
+			warnings = append(warnings, Violation{
+				Rule:    RuleBudgetNewProperties,
+				Path:    []string{"components", "schemas", name, "properties"},
+				Message: fmt.Sprintf(`schema "%s" gained %d properties, budget is %d`, name, newProperties[name], b.MaxNewProperties),
+			})
+		}
+	}
+
+	if b.MaxNewMethods > 0 && newMethods > b.MaxNewMethods {
+		warnings = append(warnings, Violation{
+			Rule:    RuleBudgetNewMethods,
+			Path:    []string{"methods"},
+			Message: fmt.Sprintf("schema gained %d methods, budget is %d", newMethods, b.MaxNewMethods),
+		})
+	}
+
+	return warnings
+}
@@ -0,0 +1,50 @@
+package rpcdiff
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBudget_Evaluate(t *testing.T) {
+	old := []byte(`{"openrpc":"1.2.6","info":{"title":"api","version":"1.0.0"},"methods":[],
+		"components":{"schemas":{"User":{"type":"object","properties":{"id":{"type":"integer"}}}}}}`)
+	new := []byte(`{"openrpc":"1.2.6","info":{"title":"api","version":"1.1.0"},"methods":[
+		{"name":"a","params":[{"name":"x","schema":{"type":"string"}},{"name":"y","schema":{"type":"string"}}],"result":{"name":"r","schema":{"type":"string"}}},
+		{"name":"b","params":[],"result":{"name":"r","schema":{"type":"string"}}}],
+		"components":{"schemas":{"User":{"type":"object","properties":{"id":{"type":"integer"},"name":{"type":"string"},"email":{"type":"string"}}}}}}`)
+
+	tests := []struct {
+		name   string
+		budget *Budget
+		want   []string
+	}{
+		{name: "no budget"},
+		{name: "within budget", budget: &Budget{MaxParams: 2, MaxNewProperties: 2, MaxNewMethods: 2}},
+		{
+			name:   "exceeded",
+			budget: &Budget{MaxParams: 1, MaxNewProperties: 1, MaxNewMethods: 1},
+			want:   []string{RuleBudgetParams, RuleBudgetNewProperties, RuleBudgetNewMethods},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff, err := NewDiffBytes(old, new, Options{Budget: tt.budget})
+			if err != nil {
+				t.Fatalf("NewDiffBytes() error: %s", err)
+			}
+
+			var rules []string
+			for _, w := range diff.Budget {
+				rules = append(rules, w.Rule)
+			}
+
+			if !reflect.DeepEqual(rules, tt.want) {
+				t.Errorf("Evaluate() = %v, want %v", diff.Budget, tt.want)
+			}
+
+			if len(diff.Violations) != 0 || diff.Criticality != NonBreaking {
+				t.Errorf("diff = %v with violations %v, want non breaking", diff.Criticality, diff.Violations)
+			}
+		})
+	}
+}
@@ -0,0 +1,357 @@
+package rpcdiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// bundler inlines external $refs into components of root document
+type bundler struct {
+	root       string
+	components map[string]interface{}
+	docs       map[string]interface{} // parsed external documents by location
+	refs       map[string]string      // inlined location#pointer to component ref
+}
+
+// BundleSchema resolves external $refs of root schema recursively and inlines them into its components.
+// Referenced components keep their names, colliding names get numeric suffix.
+func BundleSchema(root string) ([]byte, error) {
+	data, err := ReadFileOrURL(root)
+	if err != nil {
+		return nil, fmt.Errorf("read schema error: %w", err)
+	}
+
+	doc, err := decodeJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s error: %w", root, err)
+	}
+
+	rootMap, ok := doc.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s is not a json object", root)
+	}
+
+	components, _ := rootMap["components"].(map[string]interface{})
+	if components == nil {
+		components = map[string]interface{}{}
+	}
+
+	b := &bundler{
+		root:       root,
+		components: components,
+		docs:       map[string]interface{}{root: doc},
+		refs:       map[string]string{},
+	}
+
+	if err := b.inlineComponents(); err != nil {
+		return nil, err
+	}
+
+	result, err := b.walk(doc, root, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(components) > 0 {
+		result.(map[string]interface{})["components"] = components
+	}
+
+	out, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(out, '\n'), nil
+}
+
+// inlineComponents replaces components which are external refs, e.g. {"$ref": "schemas/User.json"}, with their targets
+func (b *bundler) inlineComponents() error {
+	type external struct {
+		kind, name, location string
+		pointer              string
+	}
+
+	escape := strings.NewReplacer("~", "~0", "/", "~1")
+
+	// register names first to resolve refs between inlined components to them
+	var externals []external
+	for _, kind := range sortedKeys(b.components) {
+		items, _ := b.components[kind].(map[string]interface{})
+		for _, name := range sortedKeys(items) {
+			item, _ := items[name].(map[string]interface{})
+			ref, _ := item["$ref"].(string)
+			if len(item) != 1 || ref == "" || strings.HasPrefix(ref, "#") {
+				continue
+			}
+
+			e := external{kind: kind, name: name, location: ref}
+			if i := strings.Index(ref, "#"); i != -1 {
+				e.location, e.pointer = ref[:i], ref[i+1:]
+			}
+			location, err := resolveLocation(b.root, e.location)
+			if err != nil {
+				return err
+			}
+			e.location = location
+
+			b.refs[e.location+"#"+e.pointer] = "#/components/" + kind + "/" + escape.Replace(name)
+			externals = append(externals, e)
+		}
+	}
+
+	for _, e := range externals {
+		doc, err := b.document(e.location)
+		if err != nil {
+			return err
+		}
+
+		target, err := resolvePointer(doc, e.pointer)
+		if err != nil {
+			return fmt.Errorf("resolve %s#%s error: %w", e.location, e.pointer, err)
+		}
+
+		items := b.components[e.kind].(map[string]interface{})
+		if items[e.name], err = b.walk(target, e.location, []string{"components", e.kind, e.name}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// sortedKeys returns sorted keys of map
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// walk rewrites refs of value located in document base, keys is path of value used to choose component kind
+func (b *bundler) walk(v interface{}, base string, keys []string) (interface{}, error) {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		if ref, ok := val["$ref"].(string); ok {
+			resolved, err := b.ref(ref, base, keys)
+			if err != nil {
+				return nil, err
+			}
+
+			val["$ref"] = resolved
+			return val, nil
+		}
+
+		for key, item := range val {
+			walked, err := b.walk(item, base, append(keys, key))
+			if err != nil {
+				return nil, err
+			}
+			val[key] = walked
+		}
+	case []interface{}:
+		for i, item := range val {
+			walked, err := b.walk(item, base, append(keys, strconv.Itoa(i)))
+			if err != nil {
+				return nil, err
+			}
+			val[i] = walked
+		}
+	}
+
+	return v, nil
+}
+
+// ref returns local ref to component with inlined target of ref
+func (b *bundler) ref(ref, base string, keys []string) (string, error) {
+	location, pointer := ref, ""
+	if i := strings.Index(ref, "#"); i != -1 {
+		location, pointer = ref[:i], ref[i+1:]
+	}
+
+	if location == "" {
+		if base == b.root {
+			return ref, nil
+		}
+		location = base
+	} else {
+		resolved, err := resolveLocation(base, location)
+		if err != nil {
+			return "", err
+		}
+		location = resolved
+	}
+
+	key := location + "#" + pointer
+	if resolved, ok := b.refs[key]; ok {
+		return resolved, nil
+	}
+
+	doc, err := b.document(location)
+	if err != nil {
+		return "", err
+	}
+
+	target, err := resolvePointer(doc, pointer)
+	if err != nil {
+		return "", fmt.Errorf("resolve %s error: %w", ref, err)
+	}
+
+	kind, name := componentName(location, pointer, keys)
+	items, _ := b.components[kind].(map[string]interface{})
+	if items == nil {
+		items = map[string]interface{}{}
+		b.components[kind] = items
+	}
+
+	// reuse equal component, suffix colliding one
+	unique := name
+	for i := 2; items[unique] != nil && !reflect.DeepEqual(items[unique], target); i++ {
+		unique = name + "_" + strconv.Itoa(i)
+	}
+
+	escape := strings.NewReplacer("~", "~0", "/", "~1")
+	resolved := "#/components/" + kind + "/" + escape.Replace(unique)
+	b.refs[key] = resolved
+
+	if items[unique] != nil {
+		return resolved, nil
+	}
+
+	// reserve name before walking to resolve cyclic refs
+	items[unique] = target
+	if items[unique], err = b.walk(target, location, []string{"components", kind, unique}); err != nil {
+		return "", err
+	}
+
+	return resolved, nil
+}
+
+// document returns parsed external document
+func (b *bundler) document(location string) (interface{}, error) {
+	if doc, ok := b.docs[location]; ok {
+		return doc, nil
+	}
+
+	data, err := readRefLocation(location)
+	if err != nil {
+		return nil, fmt.Errorf("read %s error: %w", location, err)
+	}
+
+	doc, err := decodeJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s error: %w", location, err)
+	}
+
+	b.docs[location] = doc
+
+	return doc, nil
+}
+
+// readRefLocation reads external document of ref from file or http(s) url, unlike ReadFileOrURL it never runs commands
+func readRefLocation(location string) ([]byte, error) {
+	if !isHTTPLocation(location) {
+		return ioutil.ReadFile(location)
+	}
+
+	return httpDo(func() (*http.Request, error) {
+		return http.NewRequest(http.MethodGet, location, nil)
+	})
+}
+
+// resolveLocation resolves location relative to base path or url, refs of documents may point only to files and http(s) urls
+func resolveLocation(base, location string) (string, error) {
+	if strings.HasPrefix(location, execScheme) || strings.HasPrefix(location, rpcScheme) {
+		return "", fmt.Errorf("ref %s is not allowed, use file path or http(s) url", location)
+	}
+
+	ref, err := url.Parse(location)
+	if err != nil {
+		return "", fmt.Errorf("parse ref %s error: %w", location, err)
+	}
+
+	// single letter scheme is windows volume
+	if len(ref.Scheme) > 1 && !isHTTPLocation(location) {
+		return "", fmt.Errorf("ref %s is not allowed, use file path or http(s) url", location)
+	}
+
+	if u, err := url.ParseRequestURI(base); err == nil && u.Host != "" {
+		return u.ResolveReference(ref).String(), nil
+	}
+
+	if filepath.IsAbs(location) || ref.Scheme != "" {
+		return location, nil
+	}
+
+	return filepath.Join(filepath.Dir(base), filepath.FromSlash(location)), nil
+}
+
+// isHTTPLocation checks that location is http(s) url
+func isHTTPLocation(location string) bool {
+	u, err := url.Parse(location)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// resolvePointer returns value of json pointer in document, empty pointer is the whole document
+func resolvePointer(doc interface{}, pointer string) (interface{}, error) {
+	if pointer == "" || pointer == "/" {
+		return doc, nil
+	}
+
+	unescape := strings.NewReplacer("~1", "/", "~0", "~")
+	v := doc
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		token = unescape.Replace(token)
+		switch val := v.(type) {
+		case map[string]interface{}:
+			item, ok := val[token]
+			if !ok {
+				return nil, fmt.Errorf("%s not found", pointer)
+			}
+			v = item
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(val) {
+				return nil, fmt.Errorf("%s not found", pointer)
+			}
+			v = val[i]
+		default:
+			return nil, fmt.Errorf("%s not found", pointer)
+		}
+	}
+
+	return v, nil
+}
+
+// componentName returns kind and name of component for ref target.
+// Components keep kind and name, other targets are content descriptors in params or result and schemas elsewhere,
+// named by last pointer token or file name.
+func componentName(location, pointer string, keys []string) (kind, name string) {
+	if k, n, ok := splitComponentRef("#" + pointer); ok && !strings.Contains(n, "/") {
+		return k, n
+	}
+
+	kind = "schemas"
+	if len(keys) == 4 && keys[0] == "methods" && keys[2] == "params" || len(keys) == 3 && keys[0] == "methods" && keys[2] == "result" {
+		kind = "contentDescriptors"
+	}
+
+	if pointer != "" && pointer != "/" {
+		return kind, strings.NewReplacer("~1", "/", "~0", "~").Replace(path.Base(pointer))
+	}
+
+	base := path.Base(filepath.ToSlash(location))
+
+	return kind, strings.TrimSuffix(base, path.Ext(base))
+}
@@ -0,0 +1,114 @@
+package rpcdiff
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func TestBundleSchema(t *testing.T) {
+	data, err := BundleSchema("testdata/bundle/root.json")
+	if err != nil {
+		t.Fatalf("BundleSchema() error: %s", err)
+	}
+
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("unmarshal error: %s", err)
+	}
+
+	tests := []struct {
+		pointer string
+		want    interface{}
+	}{
+		{"/methods/0/params/0/$ref", "#/components/contentDescriptors/id"},
+		{"/methods/0/result/schema/$ref", "#/components/schemas/user"},
+		{"/methods/1/result/schema/items/$ref", "#/components/schemas/User"},
+		{"/components/schemas/user/properties/role/$ref", "#/components/schemas/Role"},
+		{"/components/schemas/Role/type", "string"},
+		{"/components/schemas/Node/properties/next/$ref", "#/components/schemas/Node_2"},
+		{"/components/schemas/Node_2/properties/next/$ref", "#/components/schemas/Node_2"},
+		{"/components/contentDescriptors/id/name", "id"},
+	}
+
+	for _, tt := range tests {
+		got, err := resolvePointer(doc, tt.pointer)
+		if err != nil {
+			t.Errorf("resolvePointer(%v) error: %s", tt.pointer, err)
+			continue
+		}
+
+		if got != tt.want {
+			t.Errorf("%v = %v, want %v", tt.pointer, got, tt.want)
+		}
+	}
+
+	if refs := collectRefs(doc, nil); len(refs) != 7 {
+		t.Errorf("bundled schema refs = %v, wanted 7 local refs", refs)
+	} else {
+		for _, ref := range refs {
+			if _, _, ok := splitComponentRef(ref); !ok {
+				t.Errorf("bundled schema has external ref %v", ref)
+			}
+		}
+	}
+
+	if _, err := checkDocument(data); err != nil {
+		t.Errorf("bundled schema is invalid: %s", err)
+	}
+}
+
+func TestBundleSchema_missingRef(t *testing.T) {
+	if _, err := BundleSchema("exec:echo {\"methods\":[{\"$ref\":\"missing.json\"}]}"); err == nil {
+		t.Errorf("BundleSchema() with missing ref wanted error")
+	}
+}
+
+func TestBundleSchema_execRef(t *testing.T) {
+	marker := filepath.Join(t.TempDir(), "executed")
+	for _, ref := range []string{"exec:touch " + marker, "rpc+http://localhost:1/rpc"} {
+		root := filepath.Join(t.TempDir(), "root.json")
+		data := `{"openrpc": "1.2.6", "methods": [{"$ref": ` + strconv.Quote(ref) + `}]}`
+		if err := ioutil.WriteFile(root, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := BundleSchema(root); err == nil {
+			t.Errorf("BundleSchema() with %s ref wanted error", ref)
+		}
+	}
+
+	if _, err := os.Stat(marker); err == nil {
+		t.Errorf("exec ref was run")
+	}
+}
+
+func Test_resolveLocation(t *testing.T) {
+	tests := []struct {
+		base, location string
+		want           string
+		wantErr        bool
+	}{
+		{base: "api/root.json", location: "schemas/user.json", want: filepath.Join("api", "schemas", "user.json")},
+		{base: "https://example.com/api/root.json", location: "user.json", want: "https://example.com/api/user.json"},
+		{base: "https://example.com/api/root.json", location: "exec:id", wantErr: true},
+		{base: "api/root.json", location: "exec:id", wantErr: true},
+		{base: "api/root.json", location: "rpc+https://example.com/rpc", wantErr: true},
+		{base: "api/root.json", location: "ftp://example.com/user.json", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := resolveLocation(tt.base, tt.location)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("resolveLocation(%v, %v) error = %v, wantErr %v", tt.base, tt.location, err, tt.wantErr)
+			continue
+		}
+
+		if got != tt.want {
+			t.Errorf("resolveLocation(%v, %v) = %v, want %v", tt.base, tt.location, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package rpcdiff
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Canonicalize toggles rewrite both schemas before comparison
+type Canonicalize struct {
+	IgnoreKeyOrder bool `json:"ignoreKeyOrder"` // sort object keys, so order of changes doesn't depend on key order
+	NullAsAbsent   bool `json:"nullAsAbsent"`   // drop fields with null values
+	TrimSpace      bool `json:"trimSpace"`      // trim trailing whitespace in strings
+	SortExamples   bool `json:"sortExamples"`   // ignore order of examples
+}
+
+// canonicalizeToggles maps flag values to toggles
+var canonicalizeToggles = map[string]func(c *Canonicalize){
+	"ignore-key-order": func(c *Canonicalize) { c.IgnoreKeyOrder = true },
+	"null-as-absent":   func(c *Canonicalize) { c.NullAsAbsent = true },
+	"trim-space":       func(c *Canonicalize) { c.TrimSpace = true },
+	"sort-examples":    func(c *Canonicalize) { c.SortExamples = true },
+	"all": func(c *Canonicalize) {
+		*c = Canonicalize{IgnoreKeyOrder: true, NullAsAbsent: true, TrimSpace: true, SortExamples: true}
+	},
+}
+
+// Set enables comma separated toggles, implements pflag.Value
+func (c *Canonicalize) Set(value string) error {
+	for _, name := range strings.Split(value, ",") {
+		toggle, ok := canonicalizeToggles[strings.TrimSpace(name)]
+		if !ok {
+			return fmt.Errorf("unknown canonicalize toggle %s", name)
+		}
+
+		toggle(c)
+	}
+
+	return nil
+}
+
+func (c *Canonicalize) String() string {
+	var names []string
+	for _, t := range []struct {
+		name string
+		on   bool
+	}{{"ignore-key-order", c.IgnoreKeyOrder}, {"null-as-absent", c.NullAsAbsent}, {"trim-space", c.TrimSpace}, {"sort-examples", c.SortExamples}} {
+		if t.on {
+			names = append(names, t.name)
+		}
+	}
+
+	return strings.Join(names, ",")
+}
+
+func (c *Canonicalize) Type() string {
+	return "toggles"
+}
+
+// merge enables toggles of other
+func (c Canonicalize) merge(other Canonicalize) Canonicalize {
+	return Canonicalize{
+		IgnoreKeyOrder: c.IgnoreKeyOrder || other.IgnoreKeyOrder,
+		NullAsAbsent:   c.NullAsAbsent || other.NullAsAbsent,
+		TrimSpace:      c.TrimSpace || other.TrimSpace,
+		SortExamples:   c.SortExamples || other.SortExamples,
+	}
+}
+
+// enabled checks that any toggle is set
+func (c Canonicalize) enabled() bool {
+	return c.IgnoreKeyOrder || c.NullAsAbsent || c.TrimSpace || c.SortExamples
+}
+
+// apply rewrites schema json by enabled toggles, key order is kept unless it is ignored
+func (c Canonicalize) apply(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	doc, err := decodeOrdered(dec)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(c.walk(doc))
+}
+
+func (c Canonicalize) walk(v interface{}) interface{} {
+	switch val := v.(type) {
+	case *orderedObject:
+		if c.IgnoreKeyOrder {
+			sort.Strings(val.keys)
+		}
+
+		keys := val.keys[:0]
+		for _, k := range val.keys {
+			item := val.values[k]
+
+			// user data is kept as is, e.g. default: null
+			if verbatimKeys[k] {
+				if k == "examples" && c.SortExamples {
+					if items, ok := item.([]interface{}); ok {
+						sort.SliceStable(items, func(i, j int) bool { return toJSON(items[i]) < toJSON(items[j]) })
+					}
+				}
+				keys = append(keys, k)
+				continue
+			}
+
+			if c.NullAsAbsent && item == nil {
+				delete(val.values, k)
+				continue
+			}
+
+			val.values[k] = c.walk(item)
+			keys = append(keys, k)
+		}
+		val.keys = keys
+	case []interface{}:
+		for i := range val {
+			val[i] = c.walk(val[i])
+		}
+	case string:
+		if c.TrimSpace {
+			return strings.TrimRight(val, " \t\r\n")
+		}
+	}
+
+	return v
+}
+
+// orderedObject is a json object which keeps order of keys
+type orderedObject struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func (o *orderedObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, k := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		key, err := json.Marshal(k)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := json.Marshal(o.values[k])
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// decodeOrdered decodes json value with objects as orderedObject, the last of duplicate keys wins like in encoding/json
+func decodeOrdered(dec *json.Decoder) (interface{}, error) {
+	token, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch token {
+	case json.Delim('{'):
+		obj := &orderedObject{values: map[string]interface{}{}}
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+
+			value, err := decodeOrdered(dec)
+			if err != nil {
+				return nil, err
+			}
+
+			k := key.(string)
+			if _, ok := obj.values[k]; !ok {
+				obj.keys = append(obj.keys, k)
+			}
+			obj.values[k] = value
+		}
+
+		_, err = dec.Token()
+		return obj, err
+	case json.Delim('['):
+		items := []interface{}{}
+		for dec.More() {
+			item, err := decodeOrdered(dec)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+
+		_, err = dec.Token()
+		return items, err
+	}
+
+	return token, nil
+}
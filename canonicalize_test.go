@@ -0,0 +1,74 @@
+package rpcdiff
+
+import "testing"
+
+func TestNewDiffBytes_canonicalize(t *testing.T) {
+	oldJSON := []byte(`{"openrpc": "1.2.6", "info": {"title": "test", "version": "1.0.0"}, "methods": [
+		{"name": "user.Get", "description": "Returns user", "params": [], "result": {"name": "r", "schema": {"type": "string", "examples": ["a", "b"]}}}
+	]}`)
+	newJSON := []byte(`{"openrpc": "1.2.6", "info": {"title": "test", "version": "1.0.0"}, "methods": [
+		{"name": "user.Get", "description": "Returns user  \n", "summary": null, "params": [], "result": {"name": "r", "schema": {"type": "string", "examples": ["b", "a"]}}}
+	]}`)
+
+	diff, err := NewDiffBytes(oldJSON, newJSON, Options{})
+	if err != nil {
+		t.Fatalf("new diff error: %s", err)
+	}
+
+	if len(diff.Changes) == 0 {
+		t.Fatalf("wanted changes without canonicalization")
+	}
+
+	var c Canonicalize
+	if err := c.Set("trim-space,sort-examples"); err != nil {
+		t.Fatalf("set error: %s", err)
+	}
+
+	if c.String() != "trim-space,sort-examples" {
+		t.Errorf("String() = %v, want %v", c.String(), "trim-space,sort-examples")
+	}
+
+	diff, err = NewDiffBytes(oldJSON, newJSON, Options{Canonicalize: c})
+	if err != nil {
+		t.Fatalf("new diff error: %s", err)
+	}
+
+	if len(diff.Changes) != 0 {
+		t.Errorf("changes = %v, wanted none", diff.String())
+	}
+
+	if err := c.Set("unknown"); err == nil {
+		t.Errorf("unknown toggle must fail")
+	}
+}
+
+func TestCanonicalize_apply(t *testing.T) {
+	data := []byte(`{"b": {"summary": null, "default": null, "const": null, "name": "x  "}, "a": [{"z": 1, "y": 2}], "examples": [2, 1]}`)
+
+	tests := []struct {
+		toggles string
+		want    string
+	}{
+		{toggles: "null-as-absent", want: `{"b":{"default":null,"const":null,"name":"x  "},"a":[{"z":1,"y":2}],"examples":[2,1]}`},
+		{toggles: "trim-space,sort-examples", want: `{"b":{"summary":null,"default":null,"const":null,"name":"x"},"a":[{"z":1,"y":2}],"examples":[1,2]}`},
+		{toggles: "ignore-key-order", want: `{"a":[{"y":2,"z":1}],"b":{"const":null,"default":null,"name":"x  ","summary":null},"examples":[2,1]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.toggles, func(t *testing.T) {
+			var c Canonicalize
+			if err := c.Set(tt.toggles); err != nil {
+				t.Fatalf("set error: %s", err)
+			}
+
+			got, err := c.apply(data)
+			if err != nil {
+				t.Fatalf("apply error: %s", err)
+			}
+
+			if string(got) != tt.want {
+				t.Errorf("apply() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package rpcdiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// catalogPrefix is a prefix of annotations of service catalog entities
+const catalogPrefix = "rpcdiff/"
+
+// catalogAnnotations returns backstage annotations of compatibility status and changelog of latest record
+func catalogAnnotations(r Record) map[string]string {
+	result := map[string]string{
+		catalogPrefix + "version":    r.Version,
+		catalogPrefix + "digest":     r.Digest,
+		catalogPrefix + "updated-at": r.CreatedAt.UTC().Format(time.RFC3339),
+		catalogPrefix + "status":     "initial",
+	}
+
+	if r.Diff != nil {
+		result[catalogPrefix+"status"] = "compatible"
+		if len(brokenSubjects(r.Diff.Changes)) > 0 {
+			result[catalogPrefix+"status"] = "breaking"
+		}
+
+		result[catalogPrefix+"score"] = fmt.Sprint(r.Diff.Score)
+		result[catalogPrefix+"changelog"] = strings.Join(changelogEntries(r.Diff), "\n")
+	}
+
+	return result
+}
+
+// CatalogReport renders metadata of catalog-info.yaml with annotations of the latest version of every service
+func CatalogReport(records []Record) string {
+	latest := map[string]Record{}
+	for _, r := range records {
+		if l, ok := latest[r.Service]; !ok || !r.CreatedAt.Before(l.CreatedAt) {
+			latest[r.Service] = r
+		}
+	}
+
+	services := make([]string, 0, len(latest))
+	for service := range latest {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+
+	buf := strings.Builder{}
+	for i, service := range services {
+		if i > 0 {
+			buf.WriteString("---\n")
+		}
+
+		annotations := catalogAnnotations(latest[service])
+		fmt.Fprintf(&buf, "metadata:\n  name: %s\n  annotations:\n", yamlString(service))
+		for _, key := range sortedStrings(annotations) {
+			fmt.Fprintf(&buf, "    %s: %s\n", key, yamlString(annotations[key]))
+		}
+	}
+
+	return buf.String()
+}
+
+// yamlString quotes s as yaml double-quoted scalar, json strings are valid ones
+func yamlString(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
+
+// sortedStrings returns sorted keys of map
+func sortedStrings(m map[string]string) []string {
+	result := make([]string, 0, len(m))
+	for k := range m {
+		result = append(result, k)
+	}
+	sort.Strings(result)
+
+	return result
+}
@@ -0,0 +1,39 @@
+package rpcdiff
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_catalogReport(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+	removed := Change{Path: []string{"methods", "user.Get"}, Type: Removed, Object: Method, Criticality: Breaking}
+
+	records := []Record{
+		{Service: "users", Version: "v2", Digest: "d2", CreatedAt: day(2), Diff: &Diff{Score: 100, Changes: []Change{removed}}},
+		{Service: "billing", Version: "v1", Digest: "d1", CreatedAt: day(1)},
+		{Service: "users", Version: "v1", Digest: "d0", CreatedAt: day(1)},
+	}
+
+	want := `metadata:
+  name: "billing"
+  annotations:
+    rpcdiff/digest: "d1"
+    rpcdiff/status: "initial"
+    rpcdiff/updated-at: "2024-01-01T00:00:00Z"
+    rpcdiff/version: "v1"
+---
+metadata:
+  name: "users"
+  annotations:
+    rpcdiff/changelog: "- [breaking] Removed method \"user.Get\""
+    rpcdiff/digest: "d2"
+    rpcdiff/score: "100"
+    rpcdiff/status: "breaking"
+    rpcdiff/updated-at: "2024-01-02T00:00:00Z"
+    rpcdiff/version: "v2"
+`
+	if got := CatalogReport(records); got != want {
+		t.Errorf("CatalogReport() = %v, want %v", got, want)
+	}
+}
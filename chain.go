@@ -0,0 +1,73 @@
+package rpcdiff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PairDiff is a diff of two schemas from the chain
+type PairDiff struct {
+	Old  string `json:"old"`
+	New  string `json:"new"`
+	Diff *Diff  `json:"diff"`
+}
+
+// NewChainDiffs compares every adjacent pair of schemas and the first schema with the last one,
+// each schema is read and parsed once
+func NewChainDiffs(sources []string, options Options) ([]PairDiff, error) {
+	if len(sources) < 2 {
+		return nil, fmt.Errorf("at least two schemas are required")
+	}
+
+	schemas := make([]*parsedSchema, len(sources))
+	for i, source := range sources {
+		b, err := ReadFileOrURL(source)
+		if err != nil {
+			return nil, fmt.Errorf("read schema %s error: %w", source, err)
+		}
+
+		if schemas[i], err = parseSchema(source, b, options); err != nil {
+			return nil, fmt.Errorf("parse schema %s error: %w", source, err)
+		}
+	}
+
+	pairs := make([][2]int, 0, len(sources))
+	for i := 1; i < len(sources); i++ {
+		pairs = append(pairs, [2]int{i - 1, i})
+	}
+
+	if len(sources) > 2 {
+		pairs = append(pairs, [2]int{0, len(sources) - 1})
+	}
+
+	result := make([]PairDiff, 0, len(pairs))
+	for _, p := range pairs {
+		diff, err := compareSchemas(schemas[p[0]], schemas[p[1]], options)
+		if err != nil {
+			return nil, fmt.Errorf("compare %s with %s error: %w", sources[p[0]], sources[p[1]], err)
+		}
+
+		result = append(result, PairDiff{Old: sources[p[0]], New: sources[p[1]], Diff: diff})
+	}
+
+	return result, nil
+}
+
+// ChainReport renders diffs of pairs as sections, rollup is the last one
+func ChainReport(diffs []PairDiff) string {
+	buf := strings.Builder{}
+	for i, pd := range diffs {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+
+		title := fmt.Sprintf("%s -> %s", pd.Old, pd.New)
+		if i == len(diffs)-1 && i > 0 {
+			title += " (overall)"
+		}
+
+		fmt.Fprintf(&buf, "=== %s\n%s\n", title, strings.TrimSuffix(pd.Diff.String(), "\n"))
+	}
+
+	return buf.String()
+}
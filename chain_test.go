@@ -0,0 +1,42 @@
+package rpcdiff
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewChainDiffs(t *testing.T) {
+	diffs, err := NewChainDiffs([]string{"testdata/openrpc_old.json", "testdata/openrpc_new.json", "testdata/openrpc_new.json"}, Options{})
+	if err != nil {
+		t.Fatalf("new chain diffs error: %s", err)
+	}
+
+	if len(diffs) != 3 {
+		t.Fatalf("len(diffs) = %v, wanted %v", len(diffs), 3)
+	}
+
+	if diffs[1].Diff.Criticality != NonBreaking || len(diffs[1].Diff.Changes) != 0 {
+		t.Errorf("diffs[1] = %v, wanted no changes", diffs[1].Diff.String())
+	}
+
+	if len(diffs[2].Diff.Changes) != len(diffs[0].Diff.Changes) {
+		t.Errorf("rollup changes = %v, want %v", len(diffs[2].Diff.Changes), len(diffs[0].Diff.Changes))
+	}
+
+	if report := ChainReport(diffs); !strings.Contains(report, "=== testdata/openrpc_old.json -> testdata/openrpc_new.json (overall)\n") {
+		t.Errorf("unexpected report: %s", report)
+	}
+
+	checked, err := NewChainDiffs([]string{"testdata/openrpc_old.json", "testdata/openrpc_new.json"}, Options{CheckDeterminism: true})
+	if err != nil {
+		t.Fatalf("new chain diffs with determinism check error: %s", err)
+	}
+
+	if !checked[0].Diff.Options.CheckDeterminism || len(checked[0].Diff.Changes) != len(diffs[0].Diff.Changes) {
+		t.Errorf("checked changes = %v, want %v", len(checked[0].Diff.Changes), len(diffs[0].Diff.Changes))
+	}
+
+	if _, err := NewChainDiffs([]string{"testdata/openrpc_old.json"}, Options{}); err == nil {
+		t.Errorf("single schema must fail")
+	}
+}
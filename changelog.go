@@ -0,0 +1,240 @@
+package rpcdiff
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+const unreleasedHeading = "## [Unreleased]"
+
+// changelogEntries returns markdown list items of changes, the most critical first
+func changelogEntries(diff *Diff) []string {
+	taxonomy := diff.Options.taxonomy()
+
+	var result []string
+	for _, l := range taxonomy {
+		for _, c := range diff.Changes {
+			if c.Criticality == l.Level {
+				result = append(result, fmt.Sprintf("- [%s] %s", taxonomy.title(c.Criticality), c.String()))
+			}
+		}
+	}
+
+	return result
+}
+
+// updateChangelog adds entries missing in unreleased section of changelog to subsections of their groups,
+// the section is created before the first release and missing subsections are created in order of changelogSections
+func updateChangelog(content string, groups []changelogGroup) string {
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+
+	start := -1
+	for i, line := range lines {
+		if isUnreleasedHeading(line) {
+			start = i
+			break
+		}
+	}
+
+	if start == -1 {
+		start = len(lines)
+		for i, line := range lines {
+			if strings.HasPrefix(line, "## ") {
+				start = i
+				break
+			}
+		}
+
+		lines = append(lines[:start], append([]string{unreleasedHeading}, lines[start:]...)...)
+	}
+
+	end := start + 1
+	for end < len(lines) && !strings.HasPrefix(lines[end], "## ") {
+		end++
+	}
+
+	// section is free text before the first subsection and subsections of groups
+	var (
+		preamble    []string
+		subsections []*changelogGroup
+	)
+	existing := map[string]bool{}
+	for _, line := range lines[start+1 : end] {
+		existing[strings.TrimSpace(line)] = true
+		switch {
+		case strings.HasPrefix(line, "### "):
+			subsections = append(subsections, &changelogGroup{Title: strings.TrimSpace(strings.TrimPrefix(line, "### "))})
+		case len(subsections) == 0:
+			preamble = append(preamble, line)
+		default:
+			last := subsections[len(subsections)-1]
+			last.Entries = append(last.Entries, line)
+		}
+	}
+
+	for _, g := range groups {
+		var missing []string
+		for _, e := range g.Entries {
+			if !existing[e] {
+				missing = append(missing, e)
+				existing[e] = true
+			}
+		}
+
+		if len(missing) == 0 {
+			continue
+		}
+
+		var sub *changelogGroup
+		for _, s := range subsections {
+			if strings.EqualFold(s.Title, g.Title) {
+				sub = s
+				break
+			}
+		}
+
+		if sub == nil {
+			sub = &changelogGroup{Title: g.Title}
+			at := len(subsections)
+			for i, s := range subsections {
+				if changelogSectionRank(s.Title) > changelogSectionRank(g.Title) {
+					at = i
+					break
+				}
+			}
+			subsections = append(subsections[:at], append([]*changelogGroup{sub}, subsections[at:]...)...)
+		}
+
+		sub.Entries = append(trimBlankLines(sub.Entries), missing...)
+	}
+
+	section := []string{lines[start], ""}
+	if body := trimBlankLines(preamble); len(body) > 0 {
+		section = append(append(section, body...), "")
+	}
+	for _, s := range subsections {
+		section = append(section, "### "+s.Title, "")
+		if body := trimBlankLines(s.Entries); len(body) > 0 {
+			section = append(append(section, body...), "")
+		}
+	}
+
+	before := lines[:start]
+	if len(before) > 0 && strings.TrimSpace(before[len(before)-1]) != "" {
+		section = append([]string{""}, section...)
+	}
+
+	result := append(append(append([]string{}, before...), section...), lines[end:]...)
+
+	return strings.TrimRight(strings.Join(result, "\n"), "\n") + "\n"
+}
+
+// trimBlankLines removes leading and trailing blank lines
+func trimBlankLines(lines []string) []string {
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+// isUnreleasedHeading checks for "## [Unreleased]" or "## Unreleased"
+func isUnreleasedHeading(line string) bool {
+	line = strings.ToLower(strings.TrimSpace(line))
+	return line == "## [unreleased]" || line == "## unreleased"
+}
+
+// WriteChangelog updates changelog file with diff changes, missing file is created
+func WriteChangelog(path string, diff *Diff) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	content := string(data)
+	if content == "" {
+		content = "# Changelog\n"
+	}
+
+	return ioutil.WriteFile(path, []byte(updateChangelog(content, changelogGroups(diff))), 0644)
+}
+
+// changelogSections are sections of changelog fragment by type of change
+var changelogSections = []struct {
+	Title string
+	Type  ChangeType
+}{{"Added", Added}, {"Changed", Changed}, {"Removed", Removed}}
+
+// changelogGroup is a subsection of changelog section with entries of one type of changes
+type changelogGroup struct {
+	Title   string
+	Entries []string
+}
+
+// changelogSectionRank returns position of subsection title in changelogSections, unknown titles are the last
+func changelogSectionRank(title string) int {
+	for i, section := range changelogSections {
+		if strings.EqualFold(section.Title, title) {
+			return i
+		}
+	}
+
+	return len(changelogSections)
+}
+
+// changelogGroups returns entries of changes grouped by type, the most critical first, breaking and dangerous ones
+// are marked with level, types without changes are skipped
+func changelogGroups(diff *Diff) []changelogGroup {
+	taxonomy := diff.Options.taxonomy()
+
+	var result []changelogGroup
+	for _, section := range changelogSections {
+		g := changelogGroup{Title: section.Title}
+		for _, l := range taxonomy {
+			for _, c := range diff.Changes {
+				if c.Type != section.Type || c.Criticality != l.Level {
+					continue
+				}
+
+				entry := "- " + c.String()
+				if taxonomy.rank(c.Criticality) <= taxonomy.rank(Dangerous) {
+					entry = fmt.Sprintf("- [%s] %s", taxonomy.title(c.Criticality), c.String())
+				}
+				g.Entries = append(g.Entries, entry)
+			}
+		}
+
+		if len(g.Entries) > 0 {
+			result = append(result, g)
+		}
+	}
+
+	return result
+}
+
+// ChangelogFragment renders changes as changelog section headed by version of new schema, Unreleased if empty.
+// Changes are grouped by type, see changelogGroups.
+func ChangelogFragment(diff *Diff, date string) string {
+	version := "Unreleased"
+	if diff.New.Version != "" {
+		version = diff.New.Version
+	}
+
+	buf := strings.Builder{}
+	fmt.Fprintf(&buf, "## [%s]", version)
+	if date != "" {
+		fmt.Fprintf(&buf, " - %s", date)
+	}
+	buf.WriteString("\n")
+
+	for _, g := range changelogGroups(diff) {
+		fmt.Fprintf(&buf, "\n### %s\n\n%s\n", g.Title, strings.Join(g.Entries, "\n"))
+	}
+
+	return buf.String()
+}
@@ -0,0 +1,71 @@
+package rpcdiff
+
+import "testing"
+
+func Test_updateChangelog(t *testing.T) {
+	groups := []changelogGroup{
+		{Title: "Added", Entries: []string{`- Added method "user.Find"`}},
+		{Title: "Removed", Entries: []string{`- [breaking] Removed method "user.Delete"`}},
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "new section",
+			content: "# Changelog\n\n## [1.0.0] - 2021-01-01\n\n- Initial release\n",
+			want: "# Changelog\n\n## [Unreleased]\n\n### Added\n\n- Added method \"user.Find\"\n\n### Removed\n\n- [breaking] Removed method \"user.Delete\"\n\n" +
+				"## [1.0.0] - 2021-01-01\n\n- Initial release\n",
+		},
+		{
+			name:    "dedupe",
+			content: "# Changelog\n\n## Unreleased\n\n- Fixed docs\n\n### Removed\n\n- [breaking] Removed method \"user.Delete\"\n\n### Fixed\n\n- Typo\n\n## [1.0.0]\n",
+			want: "# Changelog\n\n## Unreleased\n\n- Fixed docs\n\n### Added\n\n- Added method \"user.Find\"\n\n### Removed\n\n- [breaking] Removed method \"user.Delete\"\n\n" +
+				"### Fixed\n\n- Typo\n\n## [1.0.0]\n",
+		},
+		{
+			name:    "append to subsection",
+			content: "# Changelog\n\n## [Unreleased]\n\n### Added\n\n- Added method \"user.Get\"\n",
+			want:    "# Changelog\n\n## [Unreleased]\n\n### Added\n\n- Added method \"user.Get\"\n- Added method \"user.Find\"\n\n### Removed\n\n- [breaking] Removed method \"user.Delete\"\n",
+		},
+		{
+			name:    "empty",
+			content: "# Changelog\n",
+			want:    "# Changelog\n\n## [Unreleased]\n\n### Added\n\n- Added method \"user.Find\"\n\n### Removed\n\n- [breaking] Removed method \"user.Delete\"\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := updateChangelog(tt.content, groups)
+			if got != tt.want {
+				t.Errorf("updateChangelog() = %q, want %q", got, tt.want)
+			}
+
+			if again := updateChangelog(got, groups); again != got {
+				t.Errorf("updateChangelog() is not idempotent: %q", again)
+			}
+		})
+	}
+}
+
+func Test_changelogFragment(t *testing.T) {
+	diff := &Diff{
+		New: Document{Version: "1.2.0"},
+		Changes: []Change{
+			{Path: []string{"methods", "user.Find"}, Type: Added, Object: Method, Criticality: NonBreaking},
+			{Path: []string{"methods", "user.Delete"}, Type: Removed, Object: Method, Criticality: Breaking},
+			{Path: []string{"methods", "user.Get"}, Type: Added, Object: Method, Criticality: NonBreaking},
+		},
+	}
+
+	want := "## [1.2.0] - 2021-02-01\n\n### Added\n\n- Added method \"user.Find\"\n- Added method \"user.Get\"\n\n### Removed\n\n- [breaking] Removed method \"user.Delete\"\n"
+	if got := ChangelogFragment(diff, "2021-02-01"); got != want {
+		t.Errorf("ChangelogFragment() = %q, want %q", got, want)
+	}
+
+	if got, want := ChangelogFragment(&Diff{}, ""), "## [Unreleased]\n"; got != want {
+		t.Errorf("ChangelogFragment() = %q, want %q", got, want)
+	}
+}
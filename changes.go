@@ -1,14 +1,18 @@
-package main
+package rpcdiff
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"github.com/thoas/go-funk"
 	"io/ioutil"
+	"math"
 	"net/http"
 	"net/url"
 	"reflect"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/fatih/structs"
 	openrpc "github.com/vmkteam/meta-schema/v2"
@@ -20,6 +24,12 @@ const (
 	Breaking    CriticalityLevel = "BREAKING"
 	NonBreaking CriticalityLevel = "NON_BREAKING"
 	Dangerous   CriticalityLevel = "DANGEROUS"
+
+	// PossiblyBreaking is a level of findings engine can't prove, e.g. changed default value or format
+	PossiblyBreaking CriticalityLevel = "POSSIBLY_BREAKING"
+
+	// Info is a level of meta changes, e.g. descriptions, which never affects criticality of diff
+	Info CriticalityLevel = "INFO"
 )
 
 func (c CriticalityLevel) String() string {
@@ -28,8 +38,12 @@ func (c CriticalityLevel) String() string {
 		return "breaking"
 	case Dangerous:
 		return "dangerous"
+	case PossiblyBreaking:
+		return "possibly breaking"
 	case NonBreaking:
 		return "non breaking"
+	case Info:
+		return "info"
 	}
 
 	return ""
@@ -59,11 +73,18 @@ const (
 	MethodParamType            ChangeObject = "METHOD_PARAM_TYPE" // type + ref + items type + items ref
 	MethodParamTypeDescription ChangeObject = "METHOD_PARAM_TYPE_DESC"
 
-	MethodResult     ChangeObject = "METHOD_RESULT"
-	MethodResultType ChangeObject = "METHOD_RESULT_TYPE" // schema type + ref
+	MethodResult         ChangeObject = "METHOD_RESULT"
+	MethodResultType     ChangeObject = "METHOD_RESULT_TYPE" // schema type + ref
+	MethodResultName     ChangeObject = "METHOD_RESULT_NAME"
+	MethodResultLoosened ChangeObject = "METHOD_RESULT_LOOSENED" // schema -> any
 
 	MethodError ChangeObject = "METHOD_ERROR"
 
+	MethodServers ChangeObject = "METHOD_SERVERS" // servers overriding document servers for method
+
+	Example         ChangeObject = "EXAMPLE"
+	ExampleMismatch ChangeObject = "EXAMPLE_MISMATCH" // new example doesn't match its schema
+
 	ComponentsSchema             ChangeObject = "COMPONENTS_SCHEMA"
 	ComponentsSchemaType         ChangeObject = "COMPONENTS_SCHEMA_TYPE"
 	ComponentsSchemaProperty     ChangeObject = "COMPONENTS_SCHEMA_PROPERTY"
@@ -80,8 +101,18 @@ type Change struct {
 	Type        ChangeType       `json:"type"`
 	Object      ChangeObject     `json:"object"`
 	Criticality CriticalityLevel `json:"criticality"`
-	Old         interface{}
-	New         interface{}
+	Score       int              `json:"score"`                // 0-100
+	Confidence  float64          `json:"confidence,omitempty"` // 0-1 for heuristic findings, empty for facts
+	Reference   string           `json:"reference,omitempty"`  // url of spec section
+	Fingerprint string           `json:"fingerprint"`          // stable id of change by path, type and object
+	Reason      string           `json:"reason,omitempty"`     // why criticality is assigned, e.g. "new required input parameter"
+	Evidence    *Evidence        `json:"evidence,omitempty"`   // payload accepted by one schema and rejected by the other
+	Owner       string           `json:"owner,omitempty"`      // team responsible for method or component
+	Old         interface{}      `json:"old,omitempty"`
+	New         interface{}      `json:"new,omitempty"`
+
+	fullValues    bool // render old and new values without truncation
+	requiredInput bool // change of required param or schema it references, scored higher
 
 	// TODO Add related paths to definitions/schemas diffs
 	//Related     []string `json:"related"`
@@ -94,8 +125,8 @@ func (c *Change) String() string {
 	propName := after(c.Path, "properties")
 	descrName := after(c.Path, "contentDescriptors")
 
-	oldJSON := toJSON(c.Old)
-	newJSON := toJSON(c.New)
+	oldJSON := valueJSON(c.Old, c.fullValues)
+	newJSON := valueJSON(c.New, c.fullValues)
 
 	switch c.Object {
 	// method
@@ -137,14 +168,27 @@ func (c *Change) String() string {
 		return fmt.Sprintf(`Changed type of arg "%s" at method "%s" from %v to %v`, paramName, methodName, oldJSON, newJSON)
 	case MethodResult:
 		if last(c.Path) == "result" {
+			switch c.Type {
+			case Added:
+				return fmt.Sprintf(`Added result to method "%s"`, methodName)
+			case Removed:
+				return fmt.Sprintf(`Removed result of method "%s"`, methodName)
+			}
 			return fmt.Sprintf(`Changed result of method "%s" from %v to %v`, methodName, oldJSON, newJSON)
 		}
 		return fmt.Sprintf(`Changed "%s" at result of method "%s" from %v to %v`, last(c.Path), methodName, oldJSON, newJSON)
 	case MethodResultType:
+		if schemaName != "" {
+			return fmt.Sprintf(`Changed result type of method "%s" by changes of schema "%s"`, methodName, schemaName)
+		}
 		if l := last(c.Path); l != "type" && l != "schema" && l != "$ref" && l != "items" {
 			return fmt.Sprintf(`Changed "%s" of type of result of method "%s" from %v to %v`, l, methodName, oldJSON, newJSON)
 		}
 		return fmt.Sprintf(`Changed result type of method "%s" from %v to %v`, methodName, oldJSON, newJSON)
+	case MethodResultName:
+		return fmt.Sprintf(`Renamed result of method "%s" from %v to %v`, methodName, oldJSON, newJSON)
+	case MethodResultLoosened:
+		return fmt.Sprintf(`Loosened result of method "%s" from %v to any`, methodName, oldJSON)
 	case MethodError:
 		switch c.Type {
 		case Added:
@@ -154,6 +198,20 @@ func (c *Change) String() string {
 		case Changed:
 			return fmt.Sprintf(`Changed "%s" at error "%s" of method "%s" from %v to %v`, last(c.Path), after(c.Path, "errors"), methodName, oldJSON, newJSON)
 		}
+	case Example:
+		exampleName := after(c.Path, "examples")
+		switch c.Type {
+		case Added:
+			return fmt.Sprintf(`Added example "%s" to %s`, exampleName, exampleOwner(c.Path))
+		case Removed:
+			return fmt.Sprintf(`Removed example "%s" from %s`, exampleName, exampleOwner(c.Path))
+		case Changed:
+			return fmt.Sprintf(`Changed "%s" at example "%s" of %s from %v to %v`, last(c.Path), exampleName, exampleOwner(c.Path), oldJSON, newJSON)
+		}
+	case SchemaServers, MethodServers:
+		return serverMessage(*c, oldJSON, newJSON)
+	case ExampleMismatch:
+		return fmt.Sprintf(`Example "%s" of %s doesn't match schema: %s`, after(c.Path, "examples"), exampleOwner(c.Path), c.Reason)
 	case ComponentsSchema:
 		if contains(c.Path, "required") {
 			pName := newJSON
@@ -219,6 +277,41 @@ func (c *Change) String() string {
 	return ""
 }
 
+// changeFingerprint returns stable id of change, it doesn't depend on values and criticality
+func changeFingerprint(c Change) string {
+	sum := sha256.Sum256([]byte(strings.Join(c.Path, "\x00") + "\x00" + string(c.Type) + "\x00" + string(c.Object)))
+	return hex.EncodeToString(sum[:6])
+}
+
+// Anchor returns deterministic anchor id of change for html and markdown reports
+func (c *Change) Anchor() string {
+	if c.Fingerprint == "" {
+		return "change-" + changeFingerprint(*c)
+	}
+
+	return "change-" + c.Fingerprint
+}
+
+// IsHeuristic checks if change is a best guess rather than a fact
+func (c *Change) IsHeuristic() bool {
+	return c.Confidence > 0 && c.Confidence < 1
+}
+
+// exampleOwner returns human readable name of object containing example
+func exampleOwner(path []string) string {
+	methodName := after(path, "methods")
+	switch {
+	case contains(path, "schemas"):
+		return fmt.Sprintf(`schema "%s"`, after(path, "schemas"))
+	case contains(path, "params"):
+		return fmt.Sprintf(`arg "%s" of method "%s"`, after(path, "params"), methodName)
+	case contains(path, "result"):
+		return fmt.Sprintf(`result of method "%s"`, methodName)
+	}
+
+	return fmt.Sprintf(`method "%s"`, methodName)
+}
+
 func requiredString(typ ChangeType, from, to interface{}) string {
 	switch typ {
 	case Added:
@@ -239,100 +332,418 @@ func requiredString(typ ChangeType, from, to interface{}) string {
 
 type Diff struct {
 	Criticality CriticalityLevel `json:"criticality"`
+	Score       int              `json:"score"` // max score of changes
 	Changes     []Change         `json:"changes"`
+	Diagnostics []Diagnostic     `json:"diagnostics,omitempty"`
+	Incomplete  bool             `json:"incomplete,omitempty"` // comparison of some methods or schemas failed, their changes are missing
+	Violations  []Violation      `json:"violations,omitempty"`
+	Budget      []Violation      `json:"budget,omitempty"` // exceeded complexity budget, doesn't affect criticality
+	Risk        []Risk           `json:"risk,omitempty"`   // methods with breaking changes ranked by traffic, set with usage
+	Old         Document         `json:"old"`
+	New         Document         `json:"new"`
+	Metadata    Metadata         `json:"metadata"`
 	Options     Options          `json:"-"`
+
+	oldJSON, newJSON []byte // compared schemas for reports which need them, e.g. impact graph
 }
 
 type Options struct {
-	ShowMeta bool
+	ShowMeta            bool
+	HideExamples        bool
+	ShowLinks           bool // render spec references of changes
+	ShowReasons         bool // render reasons of criticality of changes
+	Policy              *Policy
+	Budget              *Budget      // complexity limits reported as warnings
+	Taxonomy            Taxonomy     // DefaultTaxonomy if empty
+	Filter              Filter       // compare only selected methods
+	IgnoreServers       []string     // glob patterns of urls or names of servers to skip
+	Experimental        []string     // glob patterns of experimental methods in addition to x-stability: experimental
+	IgnoreMethodServers bool         // don't compare servers overrides of methods
+	Scope               Scope        // part of schema to report changes of, ScopeAll if empty
+	Objects             []string     // report only changes of objects, e.g. METHOD_RESULT_TYPE, all if empty
+	Normalize           bool         // normalize both schemas before comparison
+	Canonicalize        Canonicalize // canonicalize both schemas before comparison
+	Rules               Rules        // criticality overrides
+	RuleHook            string       // external command which rewrites changes
+	Pin                 Pin          // expected digests of raw schemas
+	CheckDeterminism    bool         // compare twice and fail if changes differ
+	FullValues          bool         // don't truncate long values in change messages
+	Evidence            bool         // synthesize payloads showing why param and result changes are breaking
+	Owners              Owners       // owners of methods and components without x-owner or x-team extensions
+	Usage               Usage        // calls per day by method to rank breaking changes by traffic
+	Prometheus          Prometheus   // source of usage, queried by LoadUsage
+	History             []Record     // previous releases of service for policy, set by LoadHistory
 }
 
-func NewDiff(old, new string, options Options) (*Diff, error) {
-	oldBytes, err := readFileOrUrl(old)
-	if err != nil {
-		return nil, fmt.Errorf("read old schema error: %w", err)
+// Scope is a part of schema to report changes of
+type Scope string
+
+const (
+	ScopeAll        Scope = "all"
+	ScopeMethods    Scope = "methods"
+	ScopeComponents Scope = "components"
+)
+
+// filterScope returns changes within scope, meta changes are reported only for all scope
+func filterScope(changes []Change, scope Scope) ([]Change, error) {
+	switch scope {
+	case "", ScopeAll:
+		return changes, nil
+	case ScopeMethods, ScopeComponents:
+	default:
+		return nil, fmt.Errorf("unknown scope %s", scope)
 	}
 
-	newBytes, err := readFileOrUrl(new)
-	if err != nil {
-		return nil, fmt.Errorf("read new schema error: %w", err)
+	var result []Change
+	for _, c := range changes {
+		if len(c.Path) > 0 && c.Path[0] == string(scope) {
+			result = append(result, c)
+		}
 	}
 
-	return NewDiffBytes(oldBytes, newBytes, options)
+	return result, nil
 }
 
-func readFileOrUrl(path string) ([]byte, error) {
-	if _, err := url.ParseRequestURI(path); err != nil {
-		return ioutil.ReadFile(path)
+// filterObjects returns changes of objects, all changes if objects are empty
+func filterObjects(changes []Change, objects []string) []Change {
+	if len(objects) == 0 {
+		return changes
 	}
 
-	resp, err := http.Get(path)
+	var result []Change
+	for _, c := range changes {
+		if contains(objects, string(c.Object)) {
+			result = append(result, c)
+		}
+	}
+
+	return result
+}
+
+// taxonomy returns configured or default taxonomy
+func (o Options) taxonomy() Taxonomy {
+	if len(o.Taxonomy) == 0 {
+		return DefaultTaxonomy
+	}
+
+	return o.Taxonomy.complete()
+}
+
+func NewDiff(old, new string, options Options) (*Diff, error) {
+	return New(WithOptions(options)).Diff(old, new)
+}
+
+// newDiffSources compares schemas read from sources and records sources in documents of diff
+func newDiffSources(old, new string, oldBytes, newBytes []byte, options Options) (*Diff, error) {
+	diff, err := NewDiffBytes(oldBytes, newBytes, options)
 	if err != nil {
 		return nil, err
 	}
 
-	if resp.Body != nil {
-		defer resp.Body.Close()
-		return ioutil.ReadAll(resp.Body)
+	diff.Old.Source, diff.New.Source = old, new
+
+	return diff, nil
+}
+
+func ReadFileOrURL(path string) ([]byte, error) {
+	if strings.HasPrefix(path, execScheme) {
+		return readExec(strings.TrimPrefix(path, execScheme))
+	}
+
+	if strings.HasPrefix(path, rpcScheme) {
+		return discoverSchema(strings.TrimPrefix(path, rpcScheme))
 	}
 
-	return []byte{}, nil
+	if u, err := url.ParseRequestURI(path); err != nil || u.Host == "" {
+		return ioutil.ReadFile(path)
+	}
+
+	return httpDo(func() (*http.Request, error) {
+		return http.NewRequest(http.MethodGet, path, nil)
+	})
 }
 
 func NewDiffBytes(oldJSON, newJSON []byte, options Options) (*Diff, error) {
-	var oldSchema openrpc.OpenrpcDocument
-	if err := json.Unmarshal(oldJSON, &oldSchema); err != nil {
+	if options.CheckDeterminism {
+		return newDiffChecked(func(options Options) (*Diff, error) {
+			return NewDiffBytes(oldJSON, newJSON, options)
+		}, options)
+	}
+
+	if err := options.Pin.verify(oldJSON, newJSON); err != nil {
 		return nil, err
 	}
 
-	var newSchema openrpc.OpenrpcDocument
-	if err := json.Unmarshal(newJSON, &newSchema); err != nil {
+	oldSchema, err := parseSchema("old", oldJSON, options)
+	if err != nil {
 		return nil, err
 	}
 
+	newSchema, err := parseSchema("new", newJSON, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return compareSchemas(oldSchema, newSchema, options)
+}
+
+// parsedSchema is a raw schema with its json rewritten by options and parsed document
+type parsedSchema struct {
+	raw  []byte // schema as given, used for digests
+	data []byte // schema rewritten by filter, normalization and canonicalization
+	doc  *openrpc.OpenrpcDocument
+}
+
+// parseSchema rewrites raw schema by options and parses it once, so it can be compared with many schemas
+func parseSchema(name string, raw []byte, options Options) (*parsedSchema, error) {
+	data := raw
+
+	if options.Filter.enabled() {
+		var err error
+		if data, err = options.Filter.Apply(data); err != nil {
+			return nil, fmt.Errorf("filter %s schema error: %w", name, err)
+		}
+	}
+
+	if options.Normalize {
+		var err error
+		if data, err = NormalizeSchema(data); err != nil {
+			return nil, fmt.Errorf("normalize %s schema error: %w", name, err)
+		}
+	}
+
+	if options.Canonicalize.enabled() {
+		var err error
+		if data, err = options.Canonicalize.apply(data); err != nil {
+			return nil, fmt.Errorf("canonicalize %s schema error: %w", name, err)
+		}
+	}
+
+	doc, err := parseDocument(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &parsedSchema{raw: raw, data: data, doc: doc}, nil
+}
+
+// compareSchemas compares parsed schemas, pins of options are ignored
+func compareSchemas(old, new *parsedSchema, options Options) (*Diff, error) {
+	if options.CheckDeterminism {
+		return newDiffChecked(func(options Options) (*Diff, error) {
+			return compareSchemas(old, new, options)
+		}, options)
+	}
+
+	return compareDocuments(old.doc, new.doc, old.data, new.data, old.raw, new.raw, options)
+}
+
+// CompareDocuments compares already parsed documents, e.g. generated in memory, without parsing them again.
+// Documents are marshaled once for checks and reports of raw schemas but never unmarshaled, so options
+// rewriting raw schemas (Pin, Filter, Normalize, Canonicalize and CheckDeterminism) are ignored.
+func CompareDocuments(old, new *openrpc.OpenrpcDocument, opts Options) (*Diff, error) {
+	opts.Pin, opts.Filter, opts.Normalize, opts.Canonicalize, opts.CheckDeterminism = Pin{}, Filter{}, false, Canonicalize{}, false
+
+	oldJSON, err := json.Marshal(old)
+	if err != nil {
+		return nil, fmt.Errorf("marshal old document error: %w", err)
+	}
+
+	newJSON, err := json.Marshal(new)
+	if err != nil {
+		return nil, fmt.Errorf("marshal new document error: %w", err)
+	}
+
+	return compareDocuments(old, new, oldJSON, newJSON, oldJSON, newJSON, opts)
+}
+
+// compareDocuments compares parsed documents, raw schemas are used for digests and reports of schema contents
+func compareDocuments(oldSchema, newSchema *openrpc.OpenrpcDocument, oldJSON, newJSON, rawOld, rawNew []byte, options Options) (*Diff, error) {
+	var err error
 	diff := &Diff{
 		Criticality: NonBreaking,
+		Old:         newDocument(rawOld, oldSchema),
+		New:         newDocument(rawNew, newSchema),
+		Metadata:    newMetadata(options),
 		Options:     options,
+		oldJSON:     oldJSON,
+		newJSON:     newJSON,
+	}
+
+	diff.Diagnostics = append(diagnoseDocument("old", oldJSON, oldSchema), diagnoseDocument("new", newJSON, newSchema)...)
+
+	// semantically identical documents, e.g. with reordered methods or inlined refs, have no changes
+	var changes []Change
+	if !semanticallyEqual(oldJSON, newJSON) {
+		var diagnostics []Diagnostic
+		changes, diagnostics = compareDocument(options, oldSchema, newSchema)
+		changes = append(changes, attributeResults(options, changes, oldJSON, newJSON, oldSchema, newSchema)...)
+		changes = append(changes, validateParamExamples(options, oldJSON, newJSON, oldSchema, newSchema)...)
+		diff.Diagnostics = append(diff.Diagnostics, diagnostics...)
+		diff.Incomplete = len(diagnostics) > 0
+	}
+
+	if diff.Changes, err = filterScope(changes, options.Scope); err != nil {
+		return nil, err
+	}
+	diff.Changes = filterObjects(diff.Changes, options.Objects)
+
+	markInfo(diff.Changes)
+	for i := range diff.Changes {
+		diff.Changes[i].Reason = engineReason(diff.Changes[i])
 	}
+	assignOwners(diff.Changes, oldJSON, newJSON, options.Owners)
 
-	diff.Changes = compareDocument(options, &oldSchema, &newSchema)
+	options.Rules.apply(diff.Changes)
+	downgradeExperimental(diff.Changes, options.taxonomy(), experimentalMethods(oldJSON), options.Experimental)
+	diff.Violations = options.Policy.applyGracePeriod(diff.Changes, deprecatedSince(oldJSON), schemaVersion(newJSON))
+	diff.Violations = append(diff.Violations, options.Policy.Evaluate(diff.Changes, oldSchema, newSchema, options.History, diff.Old.Digest)...)
+	diff.Budget = options.Budget.Evaluate(diff.Changes, newSchema)
 
-	for _, c := range diff.Changes {
-		if c.Criticality == Dangerous {
-			diff.Criticality = Dangerous
+	if options.RuleHook != "" {
+		if diff.Changes, err = runRuleHook(options.RuleHook, diff.Changes); err != nil {
+			return nil, err
 		}
-		if c.Criticality == Breaking {
-			diff.Criticality = Breaking
-			break
+	}
+
+	taxonomy := options.taxonomy()
+	taxonomy.apply(diff.Changes)
+
+	if options.Evidence {
+		addEvidence(diff.Changes, oldSchema, newSchema)
+	}
+
+	for i := range diff.Changes {
+		diff.Changes[i].requiredInput = isRequiredInput(diff.Changes[i], oldSchema, newSchema)
+		diff.Changes[i].Score = scoreChange(diff.Changes[i], taxonomy)
+		diff.Changes[i].Reference = specReference(diff.Changes[i].Object)
+		diff.Changes[i].Fingerprint = changeFingerprint(diff.Changes[i])
+		diff.Changes[i].fullValues = options.FullValues
+		if diff.Changes[i].Score > diff.Score {
+			diff.Score = diff.Changes[i].Score
 		}
 	}
 
+	diff.Criticality = taxonomy.criticality(diff.Changes)
+	if len(options.Usage) > 0 {
+		diff.Risk = riskSummary(diff.Changes, taxonomy, options.Usage)
+	}
+
 	return diff, nil
 }
 
-func (d *Diff) String() string {
-	if len(d.Changes) == 0 {
-		return "There is no difference between schemas"
+// parseDocument unmarshals openrpc document and restores references of params and results.
+// Content descriptor unmarshalling always succeeds, so {"$ref": ...} is decoded as an empty descriptor otherwise.
+func parseDocument(data []byte) (*openrpc.OpenrpcDocument, error) {
+	var doc openrpc.OpenrpcDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	var refs struct {
+		Methods []struct {
+			Params []openrpc.ReferenceObject `json:"params"`
+			Result *openrpc.ReferenceObject  `json:"result"`
+		} `json:"methods"`
+	}
+	if err := json.Unmarshal(data, &refs); err != nil {
+		return nil, err
 	}
 
+	for i, method := range refs.Methods {
+		if i >= len(doc.Methods) || doc.Methods[i].MethodObject == nil {
+			continue
+		}
+
+		m := doc.Methods[i].MethodObject
+		for j, param := range method.Params {
+			if param.Ref != "" && j < len(m.Params) {
+				ref := param
+				m.Params[j] = openrpc.ContentDescriptorOrReference{ReferenceObject: &ref}
+			}
+		}
+
+		if method.Result != nil && method.Result.Ref != "" && m.Result != nil {
+			m.Result = &openrpc.MethodObjectResult{ReferenceObject: method.Result}
+		}
+	}
+
+	return &doc, nil
+}
+
+func (d *Diff) String() string {
 	buf := strings.Builder{}
-	fmt.Fprintf(&buf, "New schema has %s change(s)\n", d.Criticality.String())
+	if len(d.Diagnostics) > 0 {
+		fmt.Fprintf(&buf, "Diagnostics (%d):\n", len(d.Diagnostics))
+		for _, diagnostic := range d.Diagnostics {
+			fmt.Fprintf(&buf, "- %s\n", diagnostic.String())
+		}
+	}
 
-	changesMap := map[CriticalityLevel][]Change{
-		Breaking:    {},
-		Dangerous:   {},
-		NonBreaking: {},
+	if len(d.Violations) > 0 {
+		fmt.Fprintf(&buf, "Policy violations (%d):\n", len(d.Violations))
+		for _, violation := range d.Violations {
+			fmt.Fprintf(&buf, "- %s\n", violation.String())
+		}
 	}
 
+	if len(d.Budget) > 0 {
+		fmt.Fprintf(&buf, "Budget warnings (%d):\n", len(d.Budget))
+		for _, warning := range d.Budget {
+			fmt.Fprintf(&buf, "- %s\n", warning.String())
+		}
+	}
+
+	if len(d.Risk) > 0 {
+		fmt.Fprintf(&buf, "Risk by traffic (%d):\n", len(d.Risk))
+		for _, risk := range d.Risk {
+			fmt.Fprintf(&buf, "- %s\n", risk.String())
+		}
+	}
+
+	if d.Incomplete {
+		buf.WriteString("Comparison is incomplete, changes of failed paths are missing\n")
+	}
+
+	if len(d.Changes) == 0 {
+		buf.WriteString("There is no difference between schemas")
+		return buf.String()
+	}
+
+	taxonomy := d.Options.taxonomy()
+	fmt.Fprintf(&buf, "New schema has %s change(s), score %d\n", taxonomy.title(d.Criticality), d.Score)
+
+	changesMap := map[CriticalityLevel][]Change{}
 	for _, change := range d.Changes {
 		changesMap[change.Criticality] = append(changesMap[change.Criticality], change)
 	}
 
-	for _, level := range []CriticalityLevel{Breaking, Dangerous, NonBreaking} {
+	for _, l := range taxonomy {
+		level := l.Level
 		if len(changesMap[level]) > 0 {
-			fmt.Fprintf(&buf, "%s changes (%d):\n", strings.Title(level.String()), len(changesMap[level]))
+			fmt.Fprintf(&buf, "%s changes (%d):\n", strings.Title(taxonomy.title(level)), len(changesMap[level]))
 			for _, change := range changesMap[level] {
-				fmt.Fprintf(&buf, "- %s\n", change.String())
+				if change.IsHeuristic() {
+					fmt.Fprintf(&buf, "~ %s (confidence %.0f%%)\n", change.String(), change.Confidence*100)
+				} else {
+					fmt.Fprintf(&buf, "- %s\n", change.String())
+				}
+
+				if d.Options.ShowReasons && change.Reason != "" {
+					fmt.Fprintf(&buf, "  why: %s\n", change.Reason)
+				}
+
+				if change.Evidence != nil {
+					fmt.Fprintf(&buf, "  example: %s\n", change.Evidence)
+				}
+
+				if change.Owner != "" {
+					fmt.Fprintf(&buf, "  owner: %s\n", change.Owner)
+				}
+
+				if d.Options.ShowLinks && change.Reference != "" {
+					fmt.Fprintf(&buf, "  see %s\n", change.Reference)
+				}
 			}
 		}
 	}
@@ -340,8 +751,92 @@ func (d *Diff) String() string {
 	return buf.String()
 }
 
+// objectScores are additional scores of changes more critical than non breaking by object
+var objectScores = map[ChangeObject]int{
+	OpenRPCVersion:               10,
+	Method:                       30,
+	MethodParamStructure:         10,
+	MethodParam:                  20,
+	MethodParamType:              20,
+	MethodResult:                 25,
+	MethodResultType:             20,
+	MethodResultLoosened:         10,
+	MethodError:                  5,
+	MethodServers:                5,
+	SchemaServers:                5,
+	ComponentsSchema:             15,
+	ComponentsSchemaType:         15,
+	ComponentsSchemaProperty:     10,
+	ComponentsSchemaPropertyType: 15,
+	ComponentsDescriptor:         15,
+	ComponentsDescriptorType:     15,
+}
+
+// requiredInputScore is additional score of changes of required params and schemas they reference
+const requiredInputScore = 10
+
+// scoreChange rates change from 0 to 100 by its criticality score in taxonomy, object and context.
+// Changes of required input are scored higher, heuristic findings are weighted by confidence.
+// Score stays below base score of more critical level, meta changes are scored as 0 as they never affect clients.
+func scoreChange(c Change, taxonomy Taxonomy) int {
+	switch c.Object {
+	case SchemaInfo, SchemaVersion:
+		return 0
+	}
+
+	score := taxonomy.Score(c.Criticality)
+	if rank := taxonomy.rank(c.Criticality); rank < taxonomy.rank(NonBreaking) {
+		score += objectScores[c.Object]
+		if c.requiredInput {
+			score += requiredInputScore
+		}
+
+		if c.Confidence > 0 {
+			score = int(math.Round(float64(score) * c.Confidence))
+		}
+
+		if rank > 0 && rank < len(taxonomy) && score >= taxonomy[rank-1].Score {
+			score = taxonomy[rank-1].Score - 1
+		}
+	}
+
+	if score > 100 {
+		return 100
+	}
+
+	return score
+}
+
+// isRequiredInput checks that change is of required param of method or of schema referenced by required params
+func isRequiredInput(c Change, oldDoc, newDoc *openrpc.OpenrpcDocument) bool {
+	if len(c.Path) > 2 && c.Path[0] == "components" && c.Path[1] == "schemas" {
+		return detectRequiredInput(c.Path[2], newDoc, []string{}, 0)
+	}
+
+	method, param := after(c.Path, "methods"), after(c.Path, "params")
+	if method == "" || param == "" {
+		return false
+	}
+
+	for _, doc := range []*openrpc.OpenrpcDocument{newDoc, oldDoc} {
+		for _, m := range doc.Methods {
+			if m.Name != method {
+				continue
+			}
+
+			for _, p := range m.Params {
+				if paramName(p, doc) == param && isRequiredParam(p, doc) {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
 // compareDocument compares two openrpc documents recursively
-func compareDocument(options Options, old, new *openrpc.OpenrpcDocument) []Change {
+func compareDocument(options Options, old, new *openrpc.OpenrpcDocument) ([]Change, []Diagnostic) {
 	var changes []Change
 
 	// openrpc version
@@ -353,15 +848,17 @@ func compareDocument(options Options, old, new *openrpc.OpenrpcDocument) []Chang
 	changes = append(changes, compareInfo(options, old.Info, new.Info)...)
 
 	// servers object
-	changes = append(changes, compareServers(options, old.Servers, new.Servers)...)
+	changes = append(changes, compareServers(options, old.Servers, new.Servers, []string{"servers"})...)
 
 	// methods
-	changes = append(changes, compareMethods(options, old.Methods, new.Methods)...)
+	methodChanges, diagnostics := compareMethods(options, old.Methods, new.Methods, old, new)
+	changes = append(changes, methodChanges...)
 
 	// components
-	changes = append(changes, compareComponents(options, old, new)...)
+	componentChanges, componentDiagnostics := compareComponents(options, old, new)
+	changes = append(changes, componentChanges...)
 
-	return changes
+	return changes, append(diagnostics, componentDiagnostics...)
 }
 
 // compareInfo compares info sections recursively
@@ -374,33 +871,38 @@ func compareInfo(options Options, old, new *openrpc.InfoObject) []Change {
 	return compareRecursive(old, new, []string{"info"}, nil)
 }
 
-// compareServers compares servers sections recursively
-func compareServers(options Options, old, new []openrpc.ServerObject) []Change {
-	if !options.ShowMeta {
-		return nil
-	}
-
-	// basic compare
-	return compareRecursive(old, new, []string{"servers"}, nil)
-}
-
-// compareMethods compares each method with counterpart recursively
-func compareMethods(options Options, old, new []openrpc.MethodOrReference) []Change {
-	var changes []Change
+// compareMethods compares each method with counterpart recursively, failed comparisons are reported as diagnostics
+func compareMethods(options Options, old, new []openrpc.MethodOrReference, oldDoc, newDoc *openrpc.OpenrpcDocument) ([]Change, []Diagnostic) {
+	var (
+		changes     []Change
+		diagnostics []Diagnostic
+	)
 
-	oldMap := map[string]openrpc.MethodOrReference{}
+	// first definition wins, duplicates are reported as diagnostics
+	oldMap, oldNames := map[string]openrpc.MethodOrReference{}, []string{}
 	for _, method := range old {
-		oldMap[method.Name] = method
+		if _, ok := oldMap[method.Name]; !ok {
+			oldMap[method.Name] = method
+			oldNames = append(oldNames, method.Name)
+		}
 	}
 
-	newMap := map[string]openrpc.MethodOrReference{}
+	newMap, newNames := map[string]openrpc.MethodOrReference{}, []string{}
 	for _, method := range new {
-		newMap[method.Name] = method
+		if _, ok := newMap[method.Name]; !ok {
+			newMap[method.Name] = method
+			newNames = append(newNames, method.Name)
+		}
 	}
 
-	for oldMethodName, oldMethod := range oldMap {
+	for _, oldMethodName := range oldNames {
+		oldMethod := oldMap[oldMethodName]
 		if newMethod, ok := newMap[oldMethodName]; ok {
-			changes = append(changes, compareMethod(options, oldMethod, newMethod, []string{"methods", oldMethodName})...)
+			path := []string{"methods", oldMethodName}
+			methodChanges, methodDiagnostics := recoverChanges(path, func() []Change {
+				return compareMethod(options, oldMethod, newMethod, path, oldDoc, newDoc)
+			})
+			changes, diagnostics = append(changes, methodChanges...), append(diagnostics, methodDiagnostics...)
 
 			delete(newMap, oldMethodName)
 		} else {
@@ -409,16 +911,18 @@ func compareMethods(options Options, old, new []openrpc.MethodOrReference) []Cha
 		}
 	}
 
-	for newMethodName, newMethod := range newMap {
-		// non-breaking on method add
-		changes = append(changes, *compare(nil, newMethod, []string{"methods", newMethodName}, NonBreaking))
+	for _, newMethodName := range newNames {
+		if newMethod, ok := newMap[newMethodName]; ok {
+			// non-breaking on method add
+			changes = append(changes, *compare(nil, newMethod, []string{"methods", newMethodName}, NonBreaking))
+		}
 	}
 
-	return changes
+	return changes, diagnostics
 }
 
 // compareMethod compares two methods recursively
-func compareMethod(options Options, old, new openrpc.MethodOrReference, path []string) []Change {
+func compareMethod(options Options, old, new openrpc.MethodOrReference, path []string, oldDoc, newDoc *openrpc.OpenrpcDocument) []Change {
 	var changes []Change
 
 	// param structure
@@ -427,16 +931,24 @@ func compareMethod(options Options, old, new openrpc.MethodOrReference, path []s
 	}
 
 	// params thyself
-	changes = append(changes, compareMethodParams(options, old.Params, new.Params, append(path, "params"))...)
+	changes = append(changes, compareMethodParams(options, old.Params, new.Params, append(path, "params"), oldDoc, newDoc)...)
 
 	// results
-	changes = append(changes, compareMethodResults(options, old.Result, new.Result, append(path, "result"))...)
+	changes = append(changes, compareMethodResults(options, old.Result, new.Result, append(path, "result"), oldDoc, newDoc)...)
 
 	// errors
 	changes = append(changes, compareMethodErrors(options, old.Errors, new.Errors, append(path, "errors"))...)
 
+	// examples
+	changes = append(changes, compareMethodExamples(options, old, new, appendPath(path, "examples"), oldDoc, newDoc)...)
+
+	// servers overrides
+	if !options.IgnoreMethodServers {
+		changes = append(changes, compareServers(options, old.Servers, new.Servers, appendPath(path, "servers"))...)
+	}
+
 	// rest of the fields
-	changes = append(changes, compareRecursive(old, new, path, []string{"paramStructure", "params", "result", "errors"})...)
+	changes = append(changes, compareRecursive(old, new, path, []string{"paramStructure", "params", "result", "errors", "examples", "servers"})...)
 
 	return changes
 }
@@ -474,22 +986,32 @@ func compareParamStructure(old, new openrpc.MethodObjectParamStructure, path []s
 }
 
 // compareMethodParams compares params of two methods
-func compareMethodParams(options Options, old, new []openrpc.ContentDescriptorOrReference, path []string) []Change {
+func compareMethodParams(options Options, old, new []openrpc.ContentDescriptorOrReference, path []string, oldDoc, newDoc *openrpc.OpenrpcDocument) []Change {
 	var changes []Change
 
-	oldMap := map[string]openrpc.ContentDescriptorOrReference{}
+	// first definition wins, duplicates are reported as diagnostics
+	oldMap, oldNames := map[string]openrpc.ContentDescriptorOrReference{}, []string{}
 	for _, param := range old {
-		oldMap[param.Name] = param
+		name := paramName(param, oldDoc)
+		if _, ok := oldMap[name]; !ok {
+			oldMap[name] = param
+			oldNames = append(oldNames, name)
+		}
 	}
 
-	newMap := map[string]openrpc.ContentDescriptorOrReference{}
+	newMap, newNames := map[string]openrpc.ContentDescriptorOrReference{}, []string{}
 	for _, param := range new {
-		newMap[param.Name] = param
+		name := paramName(param, newDoc)
+		if _, ok := newMap[name]; !ok {
+			newMap[name] = param
+			newNames = append(newNames, name)
+		}
 	}
 
-	for oldParamName, oldParam := range oldMap {
+	for _, oldParamName := range oldNames {
+		oldParam := oldMap[oldParamName]
 		if newParam, ok := newMap[oldParamName]; ok {
-			changes = append(changes, compareContentDescriptor(options, oldParam, newParam, append(path, oldParamName), true)...)
+			changes = append(changes, compareContentDescriptor(options, oldParam, newParam, append(path, oldParamName), directionInput, oldDoc, newDoc)...)
 
 			delete(newMap, oldParamName)
 		} else {
@@ -498,9 +1020,14 @@ func compareMethodParams(options Options, old, new []openrpc.ContentDescriptorOr
 		}
 	}
 
-	for newParamName, newParam := range newMap {
+	for _, newParamName := range newNames {
+		newParam, ok := newMap[newParamName]
+		if !ok {
+			continue
+		}
+
 		level := NonBreaking
-		if newParam.Required {
+		if isRequiredParam(newParam, newDoc) {
 			level = Breaking
 		}
 
@@ -511,47 +1038,253 @@ func compareMethodParams(options Options, old, new []openrpc.ContentDescriptorOr
 	return changes
 }
 
+// paramName returns name of param, referenced params are named after their descriptor
+func paramName(param openrpc.ContentDescriptorOrReference, doc *openrpc.OpenrpcDocument) string {
+	if param.ContentDescriptorObject != nil {
+		return param.Name
+	}
+
+	if param.ReferenceObject == nil {
+		return ""
+	}
+
+	if cd := resolveContentDescriptor(param.ReferenceObject.Ref, doc); cd != nil {
+		return cd.Name
+	}
+
+	return last(strings.Split(param.ReferenceObject.Ref, "/"))
+}
+
+// isRequiredParam checks required flag of param or its referenced descriptor
+func isRequiredParam(param openrpc.ContentDescriptorOrReference, doc *openrpc.OpenrpcDocument) bool {
+	if param.ContentDescriptorObject != nil {
+		return param.Required
+	}
+
+	if param.ReferenceObject != nil {
+		if cd := resolveContentDescriptor(param.ReferenceObject.Ref, doc); cd != nil {
+			return cd.Required
+		}
+	}
+
+	return false
+}
+
+// resolveContentDescriptor finds descriptor in components by reference, descriptors are keyed by name as in DescriptorsMap
+func resolveContentDescriptor(ref string, doc *openrpc.OpenrpcDocument) *openrpc.ContentDescriptorObject {
+	if !strings.HasPrefix(ref, descriptorRefPrefix) || doc == nil || doc.Components == nil || doc.Components.ContentDescriptors == nil {
+		return nil
+	}
+
+	if cd, ok := doc.Components.ContentDescriptors.Get(strings.TrimPrefix(ref, descriptorRefPrefix)); ok {
+		return &cd
+	}
+
+	return nil
+}
+
+// schemaDirection is a direction of data described by schema
+type schemaDirection int
+
+const (
+	directionUnknown schemaDirection = iota // components not used by methods
+	directionInput                          // params
+	directionOutput                         // results
+	directionBoth                           // components used by params and results, compared conservatively
+)
+
+// typeChange is a kind of change of accepted values
+type typeChange int
+
+const (
+	typeIncompatible typeChange = iota
+	typeWidened                 // new schema accepts all old values
+	typeNarrowed                // old schema accepts all new values
+)
+
+// typeChangeLevel returns criticality of type change by data direction:
+// widening is safe for input and dangerous for output, narrowing is vice versa,
+// schemas used in both directions take the most critical of both
+func typeChangeLevel(change typeChange, dir schemaDirection) CriticalityLevel {
+	switch change {
+	case typeWidened:
+		if dir == directionOutput || dir == directionBoth {
+			return Dangerous
+		}
+		return NonBreaking
+	case typeNarrowed:
+		if dir == directionOutput {
+			return NonBreaking
+		}
+		return Breaking
+	}
+
+	return Breaking
+}
+
 // compareType compares type in JSON Schema
-func compareType(options Options, old, new *openrpc.Type, path []string) *Change {
+func compareType(options Options, old, new *openrpc.Type, path []string, dir schemaDirection) *Change {
 	if reflect.DeepEqual(old, new) {
 		return nil
 	}
 
-	if old == nil || new == nil {
-		return compare(old, new, path, Breaking)
+	oldTypes, newTypes := simpleTypes(old), simpleTypes(new)
+
+	var change typeChange
+	switch widens, narrows := acceptsTypes(newTypes, oldTypes), acceptsTypes(oldTypes, newTypes); {
+	case widens && narrows:
+		// same types in different notation
+		return nil
+	case widens:
+		change = typeWidened
+	case narrows:
+		change = typeNarrowed
+	default:
+		change = typeIncompatible
 	}
 
-	level := Breaking
-	if (old.SimpleType == "integer" || old.SimpleType == "int") && (new.SimpleType == "number" || new.SimpleType == "float") {
-		level = NonBreaking
+	return compare(typeValue(old), typeValue(new), path, typeChangeLevel(change, dir))
+}
+
+// simpleTypes returns list of types, empty list means any type
+func simpleTypes(t *openrpc.Type) []openrpc.SimpleType {
+	if t == nil {
+		return nil
 	}
 
-	oldSimpleType := old.SimpleType
-	if oldSimpleType == "" && old.ArrayOfSimpleTypes != nil {
-		oldSimpleType = (*old.ArrayOfSimpleTypes)[0]
+	if t.SimpleType != "" {
+		return []openrpc.SimpleType{t.SimpleType}
 	}
 
-	newSimpleType := new.SimpleType
-	if newSimpleType == "" && new.ArrayOfSimpleTypes != nil {
-		newSimpleType = (*new.ArrayOfSimpleTypes)[0]
+	if t.ArrayOfSimpleTypes != nil {
+		return *t.ArrayOfSimpleTypes
 	}
 
-	return compare(oldSimpleType, newSimpleType, path, level)
+	return nil
 }
 
-// compareMethodResults compares results of methods
-func compareMethodResults(options Options, old, new *openrpc.MethodObjectResult, path []string) []Change {
+// typeValue returns type as a single simple type or list of types
+func typeValue(t *openrpc.Type) interface{} {
+	types := simpleTypes(t)
+	switch len(types) {
+	case 0:
+		return nil
+	case 1:
+		return types[0]
+	}
+
+	return types
+}
+
+// acceptsTypes checks that values of types are accepted by types of schema
+func acceptsTypes(schema, types []openrpc.SimpleType) bool {
+	if len(schema) == 0 {
+		return true
+	}
+	if len(types) == 0 {
+		return false
+	}
+
+	for _, t := range types {
+		if !acceptsType(schema, t) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// acceptsType checks that value of type is accepted by schema types, numbers accept integers
+func acceptsType(schema []openrpc.SimpleType, t openrpc.SimpleType) bool {
+	t = normalizeType(t)
+	for _, st := range schema {
+		st = normalizeType(st)
+		if st == t || st == openrpc.SimpleTypeNumber && t == openrpc.SimpleTypeInteger {
+			return true
+		}
+	}
+
+	return false
+}
+
+// normalizeType replaces type aliases with json schema types
+func normalizeType(t openrpc.SimpleType) openrpc.SimpleType {
+	switch t {
+	case "int":
+		return openrpc.SimpleTypeInteger
+	case "float":
+		return openrpc.SimpleTypeNumber
+	}
+
+	return t
+}
+
+// compareEnum compares enum values as sets, no enum means any value
+func compareEnum(options Options, old, new []interface{}, path []string, dir schemaDirection) *Change {
 	if reflect.DeepEqual(old, new) {
 		return nil
 	}
 
+	var change typeChange
+	switch widens, narrows := acceptsValues(new, old), acceptsValues(old, new); {
+	case widens && narrows:
+		// reordered values
+		return nil
+	case widens:
+		change = typeWidened
+	case narrows:
+		change = typeNarrowed
+	default:
+		change = typeIncompatible
+	}
+
+	return compare(old, new, path, typeChangeLevel(change, dir))
+}
+
+// acceptsValues checks that all values are in enum
+func acceptsValues(enum, values []interface{}) bool {
+	if len(enum) == 0 {
+		return true
+	}
+	if len(values) == 0 {
+		return false
+	}
+
+	for _, v := range values {
+		found := false
+		for _, e := range enum {
+			if reflect.DeepEqual(v, e) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// compareMethodResults compares results of methods
+func compareMethodResults(options Options, old, new *openrpc.MethodObjectResult, path []string, oldDoc, newDoc *openrpc.OpenrpcDocument) []Change {
+	// same references are compared by referenced descriptors
+	if reflect.DeepEqual(old, new) && (old == nil || old.ReferenceObject == nil) {
+		return nil
+	}
+
+	// non-breaking on result add
 	if old == nil {
 		return []Change{*compare(old, new, path, NonBreaking)}
 	}
+	// breaking on result descriptor removal
 	if new == nil {
 		return []Change{*compare(old, new, path, Breaking)}
 	}
 
+	var changes []Change
+
 	oldCD := openrpc.ContentDescriptorOrReference{
 		ContentDescriptorObject: old.ContentDescriptorObject,
 		ReferenceObject:         old.ReferenceObject,
@@ -562,7 +1295,261 @@ func compareMethodResults(options Options, old, new *openrpc.MethodObjectResult,
 		ReferenceObject:         new.ReferenceObject,
 	}
 
-	return compareContentDescriptor(options, oldCD, newCD, append(path, "result"), false)
+	if old.ContentDescriptorObject != nil && new.ContentDescriptorObject != nil {
+		// name of result is not transferred over the wire
+		if old.Name != new.Name {
+			changes = append(changes, Change{
+				Path:        appendPath(path, "name"),
+				Type:        Changed,
+				Object:      MethodResultName,
+				Criticality: NonBreaking,
+				Old:         old.Name,
+				New:         new.Name,
+			})
+		}
+
+		// dangerous on schema -> any, clients can't rely on the result shape anymore
+		if isAnySchema(new.Schema) && !isAnySchema(old.Schema) {
+			changes = append(changes, Change{
+				Path:        appendPath(path, "schema"),
+				Type:        Changed,
+				Object:      MethodResultLoosened,
+				Criticality: Dangerous,
+				Old:         old.Schema,
+				New:         new.Schema,
+			})
+
+			// skip schema comparison for the rest of the descriptor
+			cd := *new.ContentDescriptorObject
+			cd.Schema = old.Schema
+			newCD.ContentDescriptorObject = &cd
+		}
+	}
+
+	return append(changes, compareContentDescriptor(options, oldCD, newCD, appendPath(path, "result"), directionOutput, oldDoc, newDoc)...)
+}
+
+// isAnySchema checks if schema accepts any value: {} or true
+func isAnySchema(schema *openrpc.JSONSchema) bool {
+	if schema == nil {
+		return false
+	}
+
+	if schema.JSONSchemaBoolean != nil {
+		return bool(*schema.JSONSchemaBoolean)
+	}
+
+	return schema.JSONSchemaObject != nil && reflect.DeepEqual(*schema.JSONSchemaObject, openrpc.JSONSchemaObject{})
+}
+
+// compareMethodExamples compares example pairings of methods and validates new result examples against method result.
+// Param examples are decoded as example pairings by openrpc package, so they are validated by validateParamExamples.
+func compareMethodExamples(options Options, old, new openrpc.MethodOrReference, path []string, oldDoc, newDoc *openrpc.OpenrpcDocument) []Change {
+	if options.HideExamples {
+		return nil
+	}
+
+	oldMap, newMap := examplePairingsMap(old.Examples), examplePairingsMap(new.Examples)
+	changes := asExamples(compareRecursive(oldMap, newMap, path, []string{}))
+
+	for _, example := range new.Examples {
+		if example.ExamplePairingObject == nil {
+			continue
+		}
+
+		// result
+		if example.Result == nil || example.Result.ExampleObject == nil || new.Result == nil {
+			continue
+		}
+
+		schema := descriptorSchema(new.Result.ContentDescriptorObject, new.Result.ReferenceObject, newDoc)
+		if schema == nil {
+			continue
+		}
+
+		err := validateValue(getSchemaObject(schema), example.Result.Value, newDoc)
+		if err == nil {
+			continue
+		}
+
+		// unchanged example which already mismatched unchanged or changed result schema is not a change
+		if oldValue, ok := resultExample(old, example.Name); ok && old.Result != nil &&
+			knownMismatch(oldValue, example.Result.Value, descriptorSchema(old.Result.ContentDescriptorObject, old.Result.ReferenceObject, oldDoc), oldDoc) {
+			continue
+		}
+
+		changes = append(changes, exampleMismatch(appendPath(path, example.Name, "result"), err, example.Result.Value))
+	}
+
+	return changes
+}
+
+// resultExample returns result value of method example by name
+func resultExample(method openrpc.MethodOrReference, name string) (interface{}, bool) {
+	for _, example := range method.Examples {
+		if example.ExamplePairingObject != nil && example.Name == name && example.Result != nil && example.Result.ExampleObject != nil {
+			return example.Result.Value, true
+		}
+	}
+
+	return nil, false
+}
+
+// knownMismatch checks that example value is unchanged and it didn't match old schema either
+func knownMismatch(oldValue, newValue interface{}, oldSchema *openrpc.JSONSchema, oldDoc *openrpc.OpenrpcDocument) bool {
+	return oldSchema != nil && reflect.DeepEqual(oldValue, newValue) && validateValue(getSchemaObject(oldSchema), oldValue, oldDoc) != nil
+}
+
+// compareSchemaExamples compares examples of json schemas and validates new examples against schema
+func compareSchemaExamples(options Options, old []interface{}, newSchema *openrpc.JSONSchemaObject, path []string, newDoc *openrpc.OpenrpcDocument) []Change {
+	if options.HideExamples {
+		return nil
+	}
+
+	changes := asExamples(compareRecursive(old, newSchema.Examples, path, []string{}))
+
+	for i, example := range newSchema.Examples {
+		if err := validateValue(newSchema, example, newDoc); err != nil {
+			changes = append(changes, exampleMismatch(appendPath(path, fmt.Sprintf("%d", i)), err, example))
+		}
+	}
+
+	return changes
+}
+
+// examplePairingsMap indexes example pairings by name or reference
+func examplePairingsMap(examples []openrpc.ExamplePairingOrReference) map[string]interface{} {
+	result := map[string]interface{}{}
+	for _, example := range examples {
+		if example.ExamplePairingObject != nil {
+			result[example.Name] = example.ExamplePairingObject
+		} else if example.ReferenceObject != nil {
+			result[example.ReferenceObject.Ref] = example.ReferenceObject
+		}
+	}
+
+	return result
+}
+
+// asExamples marks changes as non-breaking example changes, values changed to other type are reported once
+func asExamples(changes []Change) []Change {
+	result := changes[:0]
+	for i, c := range changes {
+		if i > 0 && reflect.DeepEqual(c.Path, changes[i-1].Path) {
+			continue
+		}
+
+		c.Object = Example
+		c.Criticality = NonBreaking
+		result = append(result, c)
+	}
+
+	return result
+}
+
+// exampleMismatch returns dangerous change for example not matching its schema, validation error is the reason
+func exampleMismatch(path []string, err error, value interface{}) Change {
+	return Change{
+		Path:        path,
+		Type:        Changed,
+		Object:      ExampleMismatch,
+		Criticality: Dangerous,
+		Reason:      err.Error(),
+		New:         value,
+	}
+}
+
+// paramExampleKey identifies param value of method example
+type paramExampleKey struct {
+	method, example, param string
+}
+
+// paramExample is a param value of method example
+type paramExample struct {
+	paramExampleKey
+	value interface{}
+}
+
+// paramExamples returns param values of method examples of raw schema in document order
+func paramExamples(data []byte) []paramExample {
+	var raw struct {
+		Methods []struct {
+			Name     string `json:"name"`
+			Examples []struct {
+				Name   string `json:"name"`
+				Params []struct {
+					Name  string      `json:"name"`
+					Value interface{} `json:"value"`
+				} `json:"params"`
+			} `json:"examples"`
+		} `json:"methods"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil
+	}
+
+	var result []paramExample
+	for _, method := range raw.Methods {
+		for _, example := range method.Examples {
+			for _, param := range example.Params {
+				key := paramExampleKey{method: method.Name, example: example.Name, param: param.Name}
+				result = append(result, paramExample{paramExampleKey: key, value: param.Value})
+			}
+		}
+	}
+
+	return result
+}
+
+// paramSchema returns schema of method param by name
+func paramSchema(method *openrpc.MethodObject, name string, doc *openrpc.OpenrpcDocument) *openrpc.JSONSchema {
+	for _, param := range method.Params {
+		if paramName(param, doc) == name {
+			return descriptorSchema(param.ContentDescriptorObject, param.ReferenceObject, doc)
+		}
+	}
+
+	return nil
+}
+
+// validateParamExamples validates param values of examples of methods existing in both documents against new params,
+// unchanged values which already mismatched old params are skipped
+func validateParamExamples(options Options, oldJSON, newJSON []byte, oldDoc, newDoc *openrpc.OpenrpcDocument) []Change {
+	if options.HideExamples {
+		return nil
+	}
+
+	oldExamples := map[paramExampleKey]interface{}{}
+	for _, example := range paramExamples(oldJSON) {
+		oldExamples[example.paramExampleKey] = example.value
+	}
+
+	var changes []Change
+	for _, example := range paramExamples(newJSON) {
+		key, value := example.paramExampleKey, example.value
+		newMethod, oldMethod := findMethod(newDoc, key.method), findMethod(oldDoc, key.method)
+		if newMethod == nil || oldMethod == nil {
+			continue
+		}
+
+		schema := paramSchema(newMethod, key.param, newDoc)
+		if schema == nil {
+			continue
+		}
+
+		err := validateValue(getSchemaObject(schema), value, newDoc)
+		if err == nil {
+			continue
+		}
+
+		if oldValue, ok := oldExamples[key]; ok && knownMismatch(oldValue, value, paramSchema(oldMethod, key.param, oldDoc), oldDoc) {
+			continue
+		}
+
+		changes = append(changes, exampleMismatch([]string{"methods", key.method, "examples", key.example, "params", key.param}, err, value))
+	}
+
+	return changes
 }
 
 // compareMethodErrors compares errors of methods
@@ -571,22 +1558,30 @@ func compareMethodErrors(options Options, old, new []openrpc.ErrorOrReference, p
 }
 
 // compareComponents compares each component
-func compareComponents(options Options, oldDoc, newDoc *openrpc.OpenrpcDocument) []Change {
-	var changes []Change
+func compareComponents(options Options, oldDoc, newDoc *openrpc.OpenrpcDocument) ([]Change, []Diagnostic) {
+	return compareComponentsSchemas(options, componentsSchemas(oldDoc), componentsSchemas(newDoc), oldDoc, newDoc)
+}
 
-	changes = append(changes, compareComponentsSchemas(options, oldDoc.Components.Schemas, newDoc.Components.Schemas, oldDoc, newDoc)...)
+// componentsSchemas returns schemas of document components, nil if document has no components
+func componentsSchemas(doc *openrpc.OpenrpcDocument) *openrpc.SchemaMap {
+	if doc.Components == nil {
+		return nil
+	}
 
-	return changes
+	return doc.Components.Schemas
 }
 
-// compareComponents compares each component
-func compareComponentsSchemas(options Options, old, new *openrpc.SchemaMap, oldDoc, newDoc *openrpc.OpenrpcDocument) []Change {
-	var changes []Change
+// compareComponentsSchemas compares each component schema, failed comparisons are reported as diagnostics
+func compareComponentsSchemas(options Options, old, new *openrpc.SchemaMap, oldDoc, newDoc *openrpc.OpenrpcDocument) ([]Change, []Diagnostic) {
+	var (
+		changes     []Change
+		diagnostics []Diagnostic
+	)
 
 	path := []string{"components", "schemas"}
 
 	if (old != nil) != (new != nil) {
-		return []Change{*compare(old, new, path, NonBreaking)}
+		return []Change{*compare(old, new, path, NonBreaking)}, nil
 	}
 
 	if old == nil {
@@ -597,12 +1592,15 @@ func compareComponentsSchemas(options Options, old, new *openrpc.SchemaMap, oldD
 		new = &openrpc.SchemaMap{}
 	}
 
+	dirs := schemaDirections(oldDoc, newDoc)
 	index := map[string]bool{}
 	for _, oldSchema := range *old {
 		if newSchema, ok := new.Get(oldSchema.Id); ok {
-			isInput := detectRequiredInput(newSchema.Id, newDoc, []string{}, 0)
-
-			changes = append(changes, compareJSONSchema(options, getSchemaObject(oldSchema), getSchemaObject(newSchema), append(path, oldSchema.Id), isInput)...)
+			schemaPath := appendPath(path, oldSchema.Id)
+			schemaChanges, schemaDiagnostics := recoverChanges(schemaPath, func() []Change {
+				return compareComponentsSchema(options, oldSchema, newSchema, schemaPath, dirs[newSchema.Id], oldDoc, newDoc)
+			})
+			changes, diagnostics = append(changes, schemaChanges...), append(diagnostics, schemaDiagnostics...)
 
 			index[newSchema.Id] = true
 		} else {
@@ -618,7 +1616,12 @@ func compareComponentsSchemas(options Options, old, new *openrpc.SchemaMap, oldD
 		changes = append(changes, *compare(nil, newSchema, append(path, newSchema.Id), NonBreaking))
 	}
 
-	return changes
+	return changes, diagnostics
+}
+
+// compareComponentsSchema compares component schema with counterpart in direction of methods which use it
+func compareComponentsSchema(options Options, old, new openrpc.JSONSchema, path []string, dir schemaDirection, oldDoc, newDoc *openrpc.OpenrpcDocument) []Change {
+	return compareJSONSchema(options, getSchemaObject(old), getSchemaObject(new), path, dir, oldDoc, newDoc)
 }
 
 func detectRequiredInput(name string, doc *openrpc.OpenrpcDocument, checked []string, level int) bool {
@@ -669,7 +1672,7 @@ func detectRequiredInput(name string, doc *openrpc.OpenrpcDocument, checked []st
 }
 
 // compareContentDescriptor compares any kind of content descriptor
-func compareContentDescriptor(options Options, old, new openrpc.ContentDescriptorOrReference, path []string, isInput bool) []Change {
+func compareContentDescriptor(options Options, old, new openrpc.ContentDescriptorOrReference, path []string, dir schemaDirection, oldDoc, newDoc *openrpc.OpenrpcDocument) []Change {
 	var changes []Change
 
 	// descriptor -> reference
@@ -686,6 +1689,21 @@ func compareContentDescriptor(options Options, old, new openrpc.ContentDescripto
 		return []Change{*change}
 	}
 
+	// same references to components descriptors are compared in context of method, e.g. direction of schema
+	if old.ReferenceObject != nil && new.ReferenceObject != nil {
+		oldCD, newCD := resolveContentDescriptor(old.ReferenceObject.Ref, oldDoc), resolveContentDescriptor(new.ReferenceObject.Ref, newDoc)
+		if oldCD == nil || newCD == nil {
+			return nil
+		}
+
+		return compareContentDescriptor(options, openrpc.ContentDescriptorOrReference{ContentDescriptorObject: oldCD}, openrpc.ContentDescriptorOrReference{ContentDescriptorObject: newCD}, path, dir, oldDoc, newDoc)
+	}
+
+	// reference of one side only is reported by compareRef, missing descriptors have no fields to compare
+	if old.ReferenceObject != nil || new.ReferenceObject != nil || old.ContentDescriptorObject == nil || new.ContentDescriptorObject == nil {
+		return nil
+	}
+
 	// required
 	if old.Required != new.Required {
 		level := NonBreaking
@@ -697,7 +1715,7 @@ func compareContentDescriptor(options Options, old, new openrpc.ContentDescripto
 	}
 
 	// schema
-	changes = append(changes, compareJSONSchema(options, getSchemaObject(old.Schema), getSchemaObject(new.Schema), append(path, "schema"), isInput)...)
+	changes = append(changes, compareJSONSchema(options, getSchemaObject(old.Schema), getSchemaObject(new.Schema), append(path, "schema"), dir, oldDoc, newDoc)...)
 
 	// summary
 	if old.Summary != new.Summary {
@@ -713,7 +1731,7 @@ func compareContentDescriptor(options Options, old, new openrpc.ContentDescripto
 }
 
 // compareJSONSchema compares any kind of json schema
-func compareJSONSchema(options Options, old, new *openrpc.JSONSchemaObject, path []string, isInput bool) []Change {
+func compareJSONSchema(options Options, old, new *openrpc.JSONSchemaObject, path []string, dir schemaDirection, oldDoc, newDoc *openrpc.OpenrpcDocument) []Change {
 	var changes []Change
 
 	if reflect.DeepEqual(old, new) {
@@ -734,7 +1752,12 @@ func compareJSONSchema(options Options, old, new *openrpc.JSONSchemaObject, path
 	}
 
 	// type
-	if change := compareType(options, old.Type, new.Type, append(path, "type")); change != nil {
+	if change := compareType(options, old.Type, new.Type, appendPath(path, "type"), dir); change != nil {
+		changes = append(changes, *change)
+	}
+
+	// enum
+	if change := compareEnum(options, old.Enum, new.Enum, appendPath(path, "enum"), dir); change != nil {
 		changes = append(changes, *change)
 	}
 
@@ -743,7 +1766,7 @@ func compareJSONSchema(options Options, old, new *openrpc.JSONSchemaObject, path
 		oldItems, newItems := getSchemaObject(old.Items), getSchemaObject(new.Items)
 
 		if oldItems != nil && newItems != nil {
-			changes = append(changes, compareJSONSchema(options, oldItems, newItems, append(path, "items"), isInput)...)
+			changes = append(changes, compareJSONSchema(options, oldItems, newItems, append(path, "items"), dir, oldDoc, newDoc)...)
 		} else if oldItems == nil {
 			changes = append(changes, *compare(nil, newItems, append(path, "items"), Breaking))
 		} else if newItems == nil {
@@ -752,27 +1775,28 @@ func compareJSONSchema(options Options, old, new *openrpc.JSONSchemaObject, path
 	}
 
 	// required
-	if c := compareRecursive(old.Required, new.Required, append(path, "required"), []string{}); len(c) > 0 {
-		for i := range c {
-			if c[i].Type == Added && isInput {
-				c[i].Criticality = Breaking
-			}
-		}
-
-		changes = append(changes, c...)
-	}
+	changes = append(changes, compareRequired(old.Required, new.Required, appendPath(path, "required"), dir)...)
 
 	// properties
-	changes = append(changes, compareJSONSchemaProperties(options, old.Properties, new.Properties, append(path, "properties"), isInput)...)
+	changes = append(changes, compareJSONSchemaProperties(options, old.Properties, new.Properties, appendPath(path, "properties"), dir, oldDoc, newDoc)...)
 
-	// rest of the fields
-	changes = append(changes, compareRecursive(old, new, path, []string{"required", "items", "type", "$ref", "properties"})...)
+	// examples
+	changes = append(changes, compareSchemaExamples(options, old.Examples, new, appendPath(path, "examples"), newDoc)...)
+
+	// rest of the fields, changed default value or format may break clients relying on them
+	rest := compareRecursive(old, new, path, []string{"required", "items", "type", "enum", "$ref", "properties", "examples"})
+	for i := range rest {
+		if field := rest[i].Path[len(path)]; field == "default" || field == "format" {
+			rest[i].Criticality = PossiblyBreaking
+		}
+	}
+	changes = append(changes, rest...)
 
 	return changes
 }
 
 // compareJSONSchemaProperties compares properties of json schemas
-func compareJSONSchemaProperties(options Options, old, new *openrpc.SchemaMap, path []string, isInput bool) []Change {
+func compareJSONSchemaProperties(options Options, old, new *openrpc.SchemaMap, path []string, dir schemaDirection, oldDoc, newDoc *openrpc.OpenrpcDocument) []Change {
 	var changes []Change
 
 	if reflect.DeepEqual(old, new) {
@@ -790,12 +1814,12 @@ func compareJSONSchemaProperties(options Options, old, new *openrpc.SchemaMap, p
 	index := map[string]bool{}
 	for _, oldSchema := range *old {
 		if newSchema, ok := new.Get(oldSchema.Id); ok {
-			changes = append(changes, compareJSONSchema(options, getSchemaObject(oldSchema), getSchemaObject(newSchema), append(path, oldSchema.Id), isInput)...)
+			changes = append(changes, compareJSONSchema(options, getSchemaObject(oldSchema), getSchemaObject(newSchema), appendPath(path, oldSchema.Id), dir, oldDoc, newDoc)...)
 
 			index[newSchema.Id] = true
 		} else {
 			// non-breaking on schema delete
-			changes = append(changes, *compare(oldSchema, nil, append(path, oldSchema.Id), Dangerous))
+			changes = append(changes, *compare(oldSchema, nil, appendPath(path, oldSchema.Id), Dangerous))
 		}
 	}
 
@@ -804,7 +1828,33 @@ func compareJSONSchemaProperties(options Options, old, new *openrpc.SchemaMap, p
 			continue
 		}
 		// non-breaking on method add
-		changes = append(changes, *compare(nil, newSchema, append(path, newSchema.Id), NonBreaking))
+		changes = append(changes, *compare(nil, newSchema, appendPath(path, newSchema.Id), NonBreaking))
+	}
+
+	return changes
+}
+
+// compareRequired compares required properties of json schemas as sets, new required properties of input are breaking
+func compareRequired(old, new openrpc.StringArray, path []string, dir schemaDirection) []Change {
+	var changes []Change
+
+	for _, name := range old {
+		if !funk.ContainsString(new, name) {
+			changes = append(changes, *compare(name, nil, appendPath(path, name), NonBreaking))
+		}
+	}
+
+	for _, name := range new {
+		if funk.ContainsString(old, name) {
+			continue
+		}
+
+		level := NonBreaking
+		if dir == directionInput || dir == directionBoth {
+			level = Breaking
+		}
+
+		changes = append(changes, *compare(nil, name, appendPath(path, name), level))
 	}
 
 	return changes
@@ -1048,6 +2098,7 @@ var objectPaths = []map[string]ChangeObject{
 	{"methods.*.result": MethodResult},
 
 	{"methods.*.errors": MethodError},
+	{"methods.*.servers": MethodServers},
 
 	{"methods": Method},
 
@@ -1153,6 +2204,14 @@ func pop(path []string) (string, []string) {
 	return path[len(path)-1], path[:len(path)-1]
 }
 
+// appendPath returns new path with elements appended, the underlying array of path is never shared
+func appendPath(path []string, elements ...string) []string {
+	result := make([]string, 0, len(path)+len(elements))
+	result = append(result, path...)
+
+	return append(result, elements...)
+}
+
 func after(path []string, el string) string {
 	for i, p := range path {
 		if p == el && i+1 < len(path) {
@@ -1196,3 +2255,16 @@ func toJSON(val interface{}) string {
 	b, _ := json.Marshal(val)
 	return string(b)
 }
+
+// maxValueLength is a max length of old and new values in change messages
+const maxValueLength = 200
+
+// valueJSON renders value as compact json, values longer than maxValueLength are truncated with ellipsis unless full
+func valueJSON(val interface{}, full bool) string {
+	s := toJSON(val)
+	if full || utf8.RuneCountInString(s) <= maxValueLength {
+		return s
+	}
+
+	return string([]rune(s)[:maxValueLength]) + "..."
+}
@@ -1,8 +1,12 @@
-package main
+package rpcdiff
 
 import (
+	"encoding/json"
 	"fmt"
 	openrpc "github.com/vmkteam/meta-schema/v2"
+	"io/ioutil"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -14,9 +18,11 @@ func TestNewDiff(t *testing.T) {
 	}
 
 	changesMap := map[CriticalityLevel][]Change{
-		Breaking:    {},
-		Dangerous:   {},
-		NonBreaking: {},
+		Breaking:         {},
+		Dangerous:        {},
+		PossiblyBreaking: {},
+		NonBreaking:      {},
+		Info:             {},
 	}
 
 	for _, change := range diff.Changes {
@@ -27,6 +33,10 @@ func TestNewDiff(t *testing.T) {
 		t.Fatalf("diff.Criticality = %v, wanted %v", diff.Criticality, Breaking)
 	}
 
+	if diff.Score != 100 {
+		t.Fatalf("diff.Score = %v, wanted %v", diff.Score, 100)
+	}
+
 	if len(diff.Changes) != 18 {
 		t.Fatalf("len(diff.Changes) = %v, wanted %v", len(diff.Changes), 17)
 	}
@@ -35,12 +45,31 @@ func TestNewDiff(t *testing.T) {
 		t.Fatalf("len %s changes = %v, wanted %v", Breaking, len(changesMap[Breaking]), 7)
 	}
 
+	if len(changesMap[PossiblyBreaking]) != 0 {
+		t.Fatalf("len %s changes = %v, wanted %v", PossiblyBreaking, len(changesMap[PossiblyBreaking]), 0)
+	}
+
 	if len(changesMap[Dangerous]) != 1 {
 		t.Fatalf("len %s changes = %v, wanted %v", Dangerous, len(changesMap[Dangerous]), 7)
 	}
 
-	if len(changesMap[NonBreaking]) != 10 {
-		t.Fatalf("len %s changes = %v, wanted %v", NonBreaking, len(changesMap[NonBreaking]), 7)
+	if len(changesMap[NonBreaking]) != 8 {
+		t.Fatalf("len %s changes = %v, wanted %v", NonBreaking, len(changesMap[NonBreaking]), 8)
+	}
+
+	if len(changesMap[Info]) != 2 {
+		t.Fatalf("len %s changes = %v, wanted %v", Info, len(changesMap[Info]), 2)
+	}
+
+	var heuristic int
+	for _, change := range diff.Changes {
+		if change.IsHeuristic() {
+			heuristic++
+		}
+	}
+
+	if heuristic != 0 {
+		t.Fatalf("heuristic changes = %v, wanted %v", heuristic, 0)
 	}
 
 	fmt.Println(diff.String())
@@ -171,3 +200,592 @@ func Test_matchPath(t *testing.T) {
 		})
 	}
 }
+
+func Test_compareMethodResults(t *testing.T) {
+	tests := []struct {
+		name        string
+		old         string
+		new         string
+		object      ChangeObject
+		criticality CriticalityLevel
+		message     string
+	}{
+		{
+			name:        "should break on result removal",
+			old:         `{"name": "result", "schema": {"type": "string"}}`,
+			new:         ``,
+			object:      MethodResult,
+			criticality: Breaking,
+			message:     `Removed result of method "check.Method"`,
+		},
+		{
+			name:        "should be dangerous on result loosened to any",
+			old:         `{"name": "result", "schema": {"type": "string"}}`,
+			new:         `{"name": "result", "schema": {}}`,
+			object:      MethodResultLoosened,
+			criticality: Dangerous,
+			message:     `Loosened result of method "check.Method" from {"type":"string"} to any`,
+		},
+		{
+			name:        "should not break on result rename",
+			old:         `{"name": "result", "schema": {"type": "string"}}`,
+			new:         `{"name": "renamed", "schema": {"type": "string"}}`,
+			object:      MethodResultName,
+			criticality: NonBreaking,
+			message:     `Renamed result of method "check.Method" from "result" to "renamed"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var old, new *openrpc.MethodObjectResult
+			if tt.old != "" {
+				if err := json.Unmarshal([]byte(tt.old), &old); err != nil {
+					t.Fatalf("unmarshal old error: %s", err)
+				}
+			}
+			if tt.new != "" {
+				if err := json.Unmarshal([]byte(tt.new), &new); err != nil {
+					t.Fatalf("unmarshal new error: %s", err)
+				}
+			}
+
+			changes := compareMethodResults(Options{}, old, new, []string{"methods", "check.Method", "result"}, nil, nil)
+			if len(changes) != 1 {
+				t.Fatalf("len(changes) = %v, wanted %v", len(changes), 1)
+			}
+
+			c := changes[0]
+			if c.Object != tt.object {
+				t.Errorf("change.Object = %v, wanted %v", c.Object, tt.object)
+			}
+			if c.Criticality != tt.criticality {
+				t.Errorf("change.Criticality = %v, wanted %v", c.Criticality, tt.criticality)
+			}
+			if got := c.String(); got != tt.message {
+				t.Errorf("change.String() = %v, wanted %v", got, tt.message)
+			}
+		})
+	}
+}
+
+func TestNewDiffBytes_refRequired(t *testing.T) {
+	schema := `{
+		"openrpc": "1.2.6",
+		"info": {"title": "test", "version": "v0.0.0"},
+		"methods": [
+			{"name": "user.Get", "params": [{"$ref": "#/components/contentDescriptors/id"}], "result": {"name": "result", "schema": {"type": "boolean"}}},
+			{"name": "user.Delete", "params": [{"$ref": "#/components/contentDescriptors/id"}], "result": {"name": "result", "schema": {"type": "boolean"}}}
+		],
+		"components": {
+			"contentDescriptors": {
+				"id": {"name": "id", "schema": {"type": "integer"}, "required": %v}
+			}
+		}
+	}`
+
+	diff, err := NewDiffBytes([]byte(fmt.Sprintf(schema, false)), []byte(fmt.Sprintf(schema, true)), Options{})
+	if err != nil {
+		t.Fatalf("new diff error: %s", err)
+	}
+
+	if diff.Criticality != Breaking {
+		t.Fatalf("diff.Criticality = %v, wanted %v", diff.Criticality, Breaking)
+	}
+
+	if len(diff.Changes) != 2 {
+		t.Fatalf("len(diff.Changes) = %v, wanted %v", len(diff.Changes), 2)
+	}
+
+	for _, c := range diff.Changes {
+		if c.Object != MethodParam || after(c.Path, "params") != "id" {
+			t.Errorf("unexpected change: %s", c.String())
+		}
+	}
+}
+
+func TestNewDiffBytes_refDescriptor(t *testing.T) {
+	schema := `{
+		"openrpc": "1.2.6",
+		"info": {"title": "test", "version": "v0.0.0"},
+		"methods": [
+			{"name": "user.Set", "params": [{"$ref": "#/components/contentDescriptors/status"}], "result": {"name": "result", "schema": {"type": "boolean"}}},
+			{"name": "user.Status", "params": [], "result": {"$ref": "#/components/contentDescriptors/status"}}
+		],
+		"components": {
+			"contentDescriptors": {
+				"status": {"name": "status", "schema": {"type": "string", "enum": %s}}
+			}
+		}
+	}`
+
+	diff, err := NewDiffBytes([]byte(fmt.Sprintf(schema, `["on", "off"]`)), []byte(fmt.Sprintf(schema, `["on"]`)), Options{})
+	if err != nil {
+		t.Fatalf("new diff error: %s", err)
+	}
+
+	want := map[string]CriticalityLevel{"user.Set": Breaking, "user.Status": NonBreaking}
+	if len(diff.Changes) != len(want) {
+		t.Fatalf("len(diff.Changes) = %v, wanted %v", len(diff.Changes), len(want))
+	}
+
+	for _, c := range diff.Changes {
+		if method := after(c.Path, "methods"); c.Criticality != want[method] || last(c.Path) != "enum" {
+			t.Errorf("change %s = %v, wanted %v", c.String(), c.Criticality, want[method])
+		}
+	}
+}
+
+func Test_scoreChange(t *testing.T) {
+	tests := []struct {
+		name   string
+		change Change
+		want   int
+	}{
+		{
+			name:   "removed method",
+			change: Change{Type: Removed, Object: Method, Criticality: Breaking},
+			want:   100,
+		},
+		{
+			name:   "removed prop",
+			change: Change{Type: Removed, Object: ComponentsSchemaProperty, Criticality: Dangerous},
+			want:   50,
+		},
+		{
+			name:   "added method",
+			change: Change{Type: Added, Object: Method, Criticality: NonBreaking},
+			want:   10,
+		},
+		{
+			name:   "changed info",
+			change: Change{Type: Changed, Object: SchemaVersion, Criticality: NonBreaking},
+			want:   0,
+		},
+		{
+			name:   "changed type of required param",
+			change: Change{Type: Changed, Object: MethodParamType, Criticality: Breaking, requiredInput: true},
+			want:   100,
+		},
+		{
+			name:   "dangerous change of required param stays below breaking",
+			change: Change{Type: Changed, Object: MethodParam, Criticality: Dangerous, requiredInput: true},
+			want:   69,
+		},
+		{
+			name:   "heuristic finding",
+			change: Change{Type: Changed, Object: ComponentsSchemaProperty, Criticality: PossiblyBreaking, Confidence: 0.8},
+			want:   24,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := scoreChange(tt.change, DefaultTaxonomy); got != tt.want {
+				t.Errorf("scoreChange() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_compareType(t *testing.T) {
+	tests := []struct {
+		name string
+		old  string
+		new  string
+		dir  schemaDirection
+		want CriticalityLevel
+	}{
+		{name: "integer to number on input", old: `"integer"`, new: `"number"`, dir: directionInput, want: NonBreaking},
+		{name: "integer to number on output", old: `"integer"`, new: `"number"`, dir: directionOutput, want: Dangerous},
+		{name: "nullable to non nullable on input", old: `["string", "null"]`, new: `"string"`, dir: directionInput, want: Breaking},
+		{name: "nullable to non nullable on output", old: `["string", "null"]`, new: `"string"`, dir: directionOutput, want: NonBreaking},
+		{name: "string to integer", old: `"string"`, new: `"integer"`, dir: directionOutput, want: Breaking},
+		{name: "same type in other notation", old: `"string"`, new: `["string"]`, dir: directionInput, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var old, new openrpc.Type
+			if err := json.Unmarshal([]byte(tt.old), &old); err != nil {
+				t.Fatalf("unmarshal old error: %s", err)
+			}
+			if err := json.Unmarshal([]byte(tt.new), &new); err != nil {
+				t.Fatalf("unmarshal new error: %s", err)
+			}
+
+			var got CriticalityLevel
+			if c := compareType(Options{}, &old, &new, []string{"type"}, tt.dir); c != nil {
+				got = c.Criticality
+			}
+
+			if got != tt.want {
+				t.Errorf("compareType() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_compareEnum(t *testing.T) {
+	tests := []struct {
+		name string
+		old  []interface{}
+		new  []interface{}
+		dir  schemaDirection
+		want CriticalityLevel
+	}{
+		{name: "enum to any string on input", old: []interface{}{"a", "b"}, new: nil, dir: directionInput, want: NonBreaking},
+		{name: "enum to any string on output", old: []interface{}{"a", "b"}, new: nil, dir: directionOutput, want: Dangerous},
+		{name: "removed value on input", old: []interface{}{"a", "b"}, new: []interface{}{"a"}, dir: directionInput, want: Breaking},
+		{name: "removed value on output", old: []interface{}{"a", "b"}, new: []interface{}{"a"}, dir: directionOutput, want: NonBreaking},
+		{name: "reordered values", old: []interface{}{"a", "b"}, new: []interface{}{"b", "a"}, dir: directionInput, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got CriticalityLevel
+			if c := compareEnum(Options{}, tt.old, tt.new, []string{"enum"}, tt.dir); c != nil {
+				got = c.Criticality
+			}
+
+			if got != tt.want {
+				t.Errorf("compareEnum() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewDiffBytes_examples(t *testing.T) {
+	schema := `{
+		"openrpc": "1.2.6",
+		"info": {"title": "test", "version": "v0.0.0"},
+		"methods": [
+			{
+				"name": "user.Count",
+				"params": [],
+				"result": {"name": "result", "schema": {"type": "integer"}},
+				"examples": [{"name": "count", "params": [], "result": {"name": "result", "value": %s}}]
+			}
+		],
+		"components": {}
+	}`
+
+	tests := []struct {
+		name    string
+		options Options
+		value   string
+		want    []ChangeObject
+	}{
+		{name: "should report changed example", value: `2`, want: []ChangeObject{Example}},
+		{name: "should report mismatched example", value: `"two"`, want: []ChangeObject{Example, ExampleMismatch}},
+		{name: "should hide examples", value: `"two"`, options: Options{HideExamples: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff, err := NewDiffBytes([]byte(fmt.Sprintf(schema, `1`)), []byte(fmt.Sprintf(schema, tt.value)), tt.options)
+			if err != nil {
+				t.Fatalf("new diff error: %s", err)
+			}
+
+			var got []ChangeObject
+			for _, c := range diff.Changes {
+				got = append(got, c.Object)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("changes = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewDiffBytes_paramExamples(t *testing.T) {
+	schema := `{
+		"openrpc": "1.2.6",
+		"info": {"title": "test", "version": "v0.0.0"},
+		"methods": [
+			{
+				"name": "user.Get",
+				"params": [{"name": "id", "schema": {"type": "integer"}}],
+				"result": {"name": "result", "schema": {"type": "boolean"}},
+				"examples": [{"name": "get", "params": [{"name": "id", "value": %s}], "result": {"name": "result", "value": true}}]
+			}
+		],
+		"components": {}
+	}`
+
+	diff, err := NewDiffBytes([]byte(fmt.Sprintf(schema, `1`)), []byte(fmt.Sprintf(schema, `"one"`)), Options{})
+	if err != nil {
+		t.Fatalf("new diff error: %s", err)
+	}
+
+	var mismatch *Change
+	for i := range diff.Changes {
+		if diff.Changes[i].Object == ExampleMismatch {
+			mismatch = &diff.Changes[i]
+		}
+	}
+
+	if mismatch == nil {
+		t.Fatalf("param example mismatch is not reported: %v", diff.Changes)
+	}
+
+	if want := "methods.user.Get.examples.get.params.id"; strings.Join(mismatch.Path, ".") != want {
+		t.Errorf("path = %v, want %v", strings.Join(mismatch.Path, "."), want)
+	}
+
+	if mismatch.Reason == "" || mismatch.Old != nil {
+		t.Errorf("reason = %q, old = %v, want validation error in reason", mismatch.Reason, mismatch.Old)
+	}
+}
+
+func TestNewDiffBytes_knownExampleMismatch(t *testing.T) {
+	schema := `{
+		"openrpc": "1.2.6",
+		"info": {"title": "test", "version": "v0.0.0"},
+		"methods": [
+			{
+				"name": "user.Get",
+				"summary": %q,
+				"params": [{"name": "id", "schema": {"type": %q}}],
+				"result": {"name": "result", "schema": {"type": "boolean"}},
+				"examples": [{"name": "get", "params": [{"name": "id", "value": %s}], "result": {"name": "result", "value": %s}}]
+			}
+		],
+		"components": {}
+	}`
+
+	tests := []struct {
+		name     string
+		old, new string
+		want     []string
+	}{
+		{
+			name: "should skip unchanged mismatched examples",
+			old:  fmt.Sprintf(schema, "get user", "integer", `"one"`, `"yes"`),
+			new:  fmt.Sprintf(schema, "get user by id", "integer", `"one"`, `"yes"`),
+		},
+		{
+			name: "should report unchanged example mismatching changed schema",
+			old:  fmt.Sprintf(schema, "get user", "string", `"one"`, `true`),
+			new:  fmt.Sprintf(schema, "get user", "integer", `"one"`, `true`),
+			want: []string{"methods.user.Get.examples.get.params.id"},
+		},
+		{
+			name: "should report changed mismatched examples",
+			old:  fmt.Sprintf(schema, "get user", "integer", `"one"`, `"yes"`),
+			new:  fmt.Sprintf(schema, "get user", "integer", `"two"`, `"no"`),
+			want: []string{"methods.user.Get.examples.get.result", "methods.user.Get.examples.get.params.id"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff, err := NewDiffBytes([]byte(tt.old), []byte(tt.new), Options{})
+			if err != nil {
+				t.Fatalf("new diff error: %s", err)
+			}
+
+			var got []string
+			for _, c := range diff.Changes {
+				if c.Object == ExampleMismatch {
+					got = append(got, strings.Join(c.Path, "."))
+				}
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mismatches = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewDiffBytes_duplicates(t *testing.T) {
+	schema := []byte(`{
+		"openrpc": "1.2.6",
+		"info": {"title": "test", "version": "v0.0.0"},
+		"methods": [
+			{"name": "user.Get", "params": [{"name": "id", "schema": {"type": "integer"}}, {"name": "id", "schema": {"type": "string"}}], "result": {"name": "result", "schema": {"type": "boolean"}}},
+			{"name": "user.Get", "params": [], "result": {"name": "result", "schema": {"type": "boolean"}}}
+		],
+		"components": {
+			"schemas": {
+				"User": {"type": "object"},
+				"User": {"type": "string"}
+			}
+		}
+	}`)
+
+	diff, err := NewDiffBytes(schema, schema, Options{})
+	if err != nil {
+		t.Fatalf("new diff error: %s", err)
+	}
+
+	if len(diff.Changes) != 0 {
+		t.Fatalf("len(diff.Changes) = %v, wanted %v", len(diff.Changes), 0)
+	}
+
+	// method, param and schema in both schemas
+	if len(diff.Diagnostics) != 6 {
+		t.Fatalf("len(diff.Diagnostics) = %v, wanted %v", len(diff.Diagnostics), 6)
+	}
+}
+
+func TestNewDiff_scope(t *testing.T) {
+	for _, scope := range []Scope{ScopeMethods, ScopeComponents} {
+		diff, err := NewDiff("testdata/openrpc_old.json", "testdata/openrpc_new.json", Options{Scope: scope})
+		if err != nil {
+			t.Fatalf("new diff error: %s", err)
+		}
+
+		if len(diff.Changes) == 0 {
+			t.Errorf("scope %s: wanted changes", scope)
+		}
+
+		for _, c := range diff.Changes {
+			if c.Path[0] != string(scope) {
+				t.Errorf("scope %s: unexpected change %s", scope, c.String())
+			}
+		}
+	}
+
+	if _, err := NewDiff("testdata/openrpc_old.json", "testdata/openrpc_new.json", Options{Scope: "info"}); err == nil {
+		t.Errorf("unknown scope must fail")
+	}
+}
+
+func TestChange_String_longValues(t *testing.T) {
+	long := strings.Repeat("a", 300)
+	c := Change{Path: []string{"methods", "user.Get", "summary"}, Type: Changed, Object: Method, Old: long, New: "short"}
+
+	want := `Changed "summary" at method "user.Get" from "` + long[:maxValueLength-1] + `... to "short"`
+	if got := c.String(); got != want {
+		t.Errorf("String() = %v, want %v", got, want)
+	}
+
+	c.fullValues = true
+	if got := c.String(); !strings.Contains(got, `"`+long+`"`) {
+		t.Errorf("String() with full values = %v, wanted full value", got)
+	}
+}
+
+func TestNewDiffBytes_possiblyBreaking(t *testing.T) {
+	schema := func(format, def string) []byte {
+		return []byte(`{"openrpc":"1.2.6","info":{"title":"t","version":"1"},"methods":[],"components":{"schemas":{"Event":{"type":"object","properties":{` +
+			`"at":{"type":"string","format":"` + format + `"},"limit":{"type":"integer","default":` + def + `}}}}}}`)
+	}
+
+	diff, err := NewDiffBytes(schema("date", "10"), schema("date-time", "20"), Options{})
+	if err != nil {
+		t.Fatalf("new diff error: %s", err)
+	}
+
+	if len(diff.Changes) != 2 {
+		t.Fatalf("len(diff.Changes) = %v, wanted 2: %v", len(diff.Changes), diff.String())
+	}
+
+	for _, c := range diff.Changes {
+		if c.Criticality != PossiblyBreaking {
+			t.Errorf("criticality of %v = %v, want %v", c.String(), c.Criticality, PossiblyBreaking)
+		}
+	}
+
+	if diff.Criticality != PossiblyBreaking || diff.Score >= DefaultTaxonomy.Score(Breaking) {
+		t.Errorf("diff = %v with score %v, want %v below breaking score", diff.Criticality, diff.Score, PossiblyBreaking)
+	}
+}
+
+func Test_recoverChanges(t *testing.T) {
+	path := []string{"methods", "broken"}
+	changes, diagnostics := recoverChanges(path, func() []Change {
+		var schema *openrpc.JSONSchemaObject
+		return []Change{{Path: path, Old: schema.Type}}
+	})
+
+	if len(changes) != 0 || len(diagnostics) != 1 {
+		t.Fatalf("recoverChanges() = %v, %v, want one diagnostic", changes, diagnostics)
+	}
+
+	if d := diagnostics[0]; strings.Join(d.Path, ".") != "methods.broken" || !strings.Contains(d.Message, `"methods.broken" failed`) {
+		t.Errorf("recoverChanges() diagnostic = %+v", d)
+	}
+
+	changes, diagnostics = recoverChanges(path, func() []Change { return []Change{{Path: path}} })
+	if len(changes) != 1 || len(diagnostics) != 0 {
+		t.Errorf("recoverChanges() = %v, %v, want one change", changes, diagnostics)
+	}
+}
+
+func Test_compareRequired(t *testing.T) {
+	path := []string{"components", "schemas", "User", "required"}
+	tests := []struct {
+		name     string
+		old, new openrpc.StringArray
+		dir      schemaDirection
+		want     []string
+	}{
+		{name: "reordered", old: openrpc.StringArray{"a", "b"}, new: openrpc.StringArray{"b", "a"}},
+		{name: "added to input", old: openrpc.StringArray{"a"}, new: openrpc.StringArray{"b", "a"}, dir: directionInput, want: []string{"ADDED b BREAKING"}},
+		{name: "added", old: openrpc.StringArray{"a"}, new: openrpc.StringArray{"b", "a"}, want: []string{"ADDED b NON_BREAKING"}},
+		{name: "replaced", old: openrpc.StringArray{"a", "b"}, new: openrpc.StringArray{"c", "a"}, want: []string{"REMOVED b NON_BREAKING", "ADDED c NON_BREAKING"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, c := range compareRequired(tt.old, tt.new, path, tt.dir) {
+				got = append(got, fmt.Sprintf("%s %s %s", c.Type, last(c.Path), string(c.Criticality)))
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("compareRequired() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareDocuments(t *testing.T) {
+	oldJSON, err := ioutil.ReadFile("testdata/openrpc_old.json")
+	if err != nil {
+		t.Fatalf("read old schema error: %s", err)
+	}
+
+	newJSON, err := ioutil.ReadFile("testdata/openrpc_new.json")
+	if err != nil {
+		t.Fatalf("read new schema error: %s", err)
+	}
+
+	want, err := NewDiffBytes(oldJSON, newJSON, Options{})
+	if err != nil {
+		t.Fatalf("NewDiffBytes() error: %s", err)
+	}
+
+	old, err := parseDocument(oldJSON)
+	if err != nil {
+		t.Fatalf("parseDocument() error: %s", err)
+	}
+
+	new, err := parseDocument(newJSON)
+	if err != nil {
+		t.Fatalf("parseDocument() error: %s", err)
+	}
+
+	diff, err := CompareDocuments(old, new, Options{})
+	if err != nil {
+		t.Fatalf("CompareDocuments() error: %s", err)
+	}
+
+	if diff.Criticality != want.Criticality || len(diff.Changes) != len(want.Changes) {
+		t.Fatalf("CompareDocuments() = %v %d changes, want %v %d changes", diff.Criticality, len(diff.Changes), want.Criticality, len(want.Changes))
+	}
+
+	for i := range diff.Changes {
+		if diff.Changes[i].Fingerprint != want.Changes[i].Fingerprint {
+			t.Errorf("change %d = %v, want %v", i, diff.Changes[i], want.Changes[i])
+		}
+	}
+
+	if _, err := CompareDocuments(old, new, Options{Scope: "unknown"}); err == nil {
+		t.Errorf("CompareDocuments() with invalid scope error = nil, want scope error")
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+const envPrefix = "RPCDIFF_"
+
+// envName returns environment variable name of flag, e.g. RPCDIFF_COMPARE_META for --compare-meta
+func envName(flag string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(flag, "-", "_"))
+}
+
+// applyEnvFlags sets flags not given in command line from RPCDIFF_* environment variables
+func applyEnvFlags(flags *pflag.FlagSet, lookup func(string) (string, bool)) error {
+	var err error
+	flags.VisitAll(func(f *pflag.Flag) {
+		if err != nil || f.Changed {
+			return
+		}
+
+		value, ok := lookup(envName(f.Name))
+		if !ok {
+			return
+		}
+
+		if e := flags.Set(f.Name, value); e != nil {
+			err = fmt.Errorf("invalid %s: %w", envName(f.Name), e)
+		}
+	})
+
+	return err
+}
+
+// expandArgFiles replaces @file arguments with arguments read from file.
+// Arguments are separated by whitespace, lines starting with # are skipped.
+func expandArgFiles(args []string) ([]string, error) {
+	result := make([]string, 0, len(args))
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "@") || len(arg) == 1 {
+			result = append(result, arg)
+			continue
+		}
+
+		data, err := ioutil.ReadFile(arg[1:])
+		if err != nil {
+			return nil, fmt.Errorf("read flag file error: %w", err)
+		}
+
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+
+			result = append(result, strings.Fields(line)...)
+		}
+	}
+
+	return result, nil
+}
+
+// osArgs returns command line arguments with expanded flag files
+func osArgs() []string {
+	args, err := expandArgFiles(os.Args[1:])
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	return args
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+func TestApplyEnvFlags(t *testing.T) {
+	var (
+		old, new string
+		meta     bool
+	)
+
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.StringVarP(&old, "old", "o", "", "")
+	flags.StringVarP(&new, "new", "n", "", "")
+	flags.BoolVar(&meta, "compare-meta", false, "")
+
+	if err := flags.Parse([]string{"-n", "cli.json"}); err != nil {
+		t.Fatalf("parse error: %s", err)
+	}
+
+	env := map[string]string{"RPCDIFF_OLD": "env.json", "RPCDIFF_NEW": "env.json", "RPCDIFF_COMPARE_META": "true"}
+	lookup := func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}
+
+	if err := applyEnvFlags(flags, lookup); err != nil {
+		t.Fatalf("applyEnvFlags() error: %s", err)
+	}
+
+	if old != "env.json" || new != "cli.json" || !meta {
+		t.Errorf("applyEnvFlags() = %v %v %v, want env.json cli.json true", old, new, meta)
+	}
+
+	env["RPCDIFF_COMPARE_META"] = "maybe"
+	flags.Lookup("compare-meta").Changed = false
+	if err := applyEnvFlags(flags, lookup); err == nil {
+		t.Errorf("applyEnvFlags() with invalid bool wanted error")
+	}
+}
+
+func TestExpandArgFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "args.txt")
+	if err := ioutil.WriteFile(path, []byte("# schemas\n--old old.json\n\n  --new new.json --compare-meta\n"), 0600); err != nil {
+		t.Fatalf("write error: %s", err)
+	}
+
+	got, err := expandArgFiles([]string{"repo", "@" + path, "--ref", "HEAD", "@"})
+	if err != nil {
+		t.Fatalf("expandArgFiles() error: %s", err)
+	}
+
+	want := []string{"repo", "--old", "old.json", "--new", "new.json", "--compare-meta", "--ref", "HEAD", "@"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expandArgFiles() = %v, want %v", got, want)
+	}
+
+	if _, err := expandArgFiles([]string{"@missing.txt"}); err == nil {
+		t.Errorf("expandArgFiles() of missing file wanted error")
+	}
+}
@@ -0,0 +1,1375 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/vmkteam/rpcdiff"
+)
+
+func main() {
+	var (
+		old       string
+		new       string
+		config    string
+		service   string
+		since     string
+		sinceVer  string
+		oldVer    string
+		upload    string
+		changelog string
+		summary   string
+		format    rpcdiff.Format
+		output    string
+		tmpl      string
+		groupBy   string
+		suggest   bool
+		signKey   string
+		attest    string
+		maxScore  int
+		waitFor   time.Duration
+		opts      rpcdiff.Options
+	)
+
+	command := &cobra.Command{
+		Use:   "rpcdiff",
+		Short: "use rpcdiff to compare two openrpc schemas",
+		Long: "use rpcdiff to compare two openrpc schemas.\n\n" +
+			"Flags not given in command line are read from RPCDIFF_<FLAG> environment variables, e.g. RPCDIFF_OLD, RPCDIFF_COMPARE_META.\n" +
+			"Arguments like @args.txt are replaced by whitespace separated arguments from file.\n" +
+			"Schema may be given as exec:<command> to read it from command stdout, e.g. exec:./fetch-schema.sh prod,\n" +
+			"or as rpc+<url> to take it by rpc.discover of json-rpc endpoint.\n\n" +
+			"Exit code is 1 on breaking changes, policy violations or errors and 2 if comparison of some paths failed.",
+		Version: rpcdiff.Version,
+		FParseErrWhitelist: cobra.FParseErrWhitelist{
+			UnknownFlags: true,
+		},
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := applyEnvFlags(cmd.Flags(), os.LookupEnv); err != nil {
+				return err
+			}
+
+			// ask for missing schemas if run by human, old schema isn't asked if it is taken from history store
+			flags := cmd.Flags()
+			hasOld := flags.Changed("old") || flags.Changed("since") || flags.Changed("since-version")
+			if cmd == cmd.Root() && (!hasOld || !flags.Changed("new")) && isTerminal(os.Stdin) {
+				names := promptedFlags
+				if hasOld {
+					names = promptedFlags[1:]
+				}
+				return promptFlags(flags, names, os.Stdin, os.Stdout)
+			}
+
+			return nil
+		},
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if old == "" && since == "" && sinceVer == "" {
+				return fmt.Errorf("required flag \"old\" not set, or set --since or --since-version to take old schema from history store")
+			}
+
+			if old != "" && (since != "" || sinceVer != "") {
+				return fmt.Errorf("--old can't be used with --since or --since-version")
+			}
+
+			if service == "" && (since != "" || sinceVer != "" || oldVer != "") {
+				return fmt.Errorf("--service is required to take old schema from history store")
+			}
+
+			return nil
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := format.Validate(); err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			var cfg *rpcdiff.Config
+			if config != "" {
+				var err error
+				if cfg, err = rpcdiff.LoadConfig(config); err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+
+				cfg.Apply(&opts)
+
+				if err := opts.LoadHistory(cfg, service); err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+			}
+
+			if oldVer != "" {
+				if err := rpcdiff.PinRecordedDigest(cfg, service, oldVer, &opts.Pin); err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+			}
+
+			if err := opts.LoadUsage(); err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			var (
+				diff *rpcdiff.Diff
+				err  error
+			)
+			switch {
+			case since != "" || sinceVer != "":
+				diff, err = rpcdiff.DiffSince(cfg, service, since, sinceVer, new, waitFor, opts)
+			case waitFor > 0:
+				diff, err = rpcdiff.NewDiffWait(old, new, waitFor, opts)
+			default:
+				diff, err = rpcdiff.NewDiff(old, new, opts)
+			}
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			if suggest {
+				fmt.Print(rpcdiff.CommitSuggestion(diff))
+			} else {
+				var out string
+				switch {
+				case tmpl != "":
+					out, err = rpcdiff.RenderTemplate(diff, tmpl)
+				case groupBy == "owner":
+					out = diff.Header() + "\n" + rpcdiff.OwnerReport(diff)
+				case groupBy != "":
+					err = fmt.Errorf("unknown group-by %q, supported: owner", groupBy)
+				default:
+					out, err = rpcdiff.RenderDiff(diff, format, new)
+				}
+				if err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+
+				if output == "" {
+					fmt.Print(out)
+				} else {
+					if err := ioutil.WriteFile(output, []byte(out), 0644); err != nil {
+						fmt.Println(err)
+						os.Exit(1)
+					}
+				}
+			}
+
+			if summary != "" {
+				if err := rpcdiff.WriteSummary(summary, diff); err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+			}
+
+			if changelog != "" {
+				if err := rpcdiff.WriteChangelog(changelog, diff); err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+			}
+
+			if attest != "" {
+				if err := rpcdiff.WriteAttestation(attest, signKey, diff); err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+			}
+
+			if upload != "" {
+				locations, err := rpcdiff.UploadDiff(upload, diff)
+				if err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+
+				for _, location := range locations {
+					fmt.Printf("Uploaded to: %s\n", location)
+				}
+			}
+
+			if diff.Incomplete {
+				os.Exit(rpcdiff.ExitFailed)
+			}
+
+			if diff.IsBreaking(maxScore) {
+				os.Exit(1)
+			}
+		},
+	}
+
+	flags := command.Flags()
+	flags.SortFlags = false
+
+	flags.StringVarP(&old, "old", "o", "", "path/url to old schema, required unless --since or --since-version is set")
+
+	flags.StringVarP(&new, "new", "n", "", "path/url to new schema")
+	cobra.MarkFlagRequired(flags, "new")
+
+	flags.StringVar(&opts.Pin.Old, "old-sha256", "", "fail if sha256 of old schema differs")
+	flags.StringVar(&opts.Pin.New, "new-sha256", "", "fail if sha256 of new schema differs")
+	flags.StringVar(&oldVer, "old-version", "", "fail if sha256 of old schema differs from digest of version recorded in history store of --service")
+	flags.StringVarP(&config, "config", "c", "", "path to config with policy, budget, taxonomy and rules")
+	flags.StringVar(&service, "service", "", "service name to read previous releases of from history store for --since and deprecatedReleases policy")
+	flags.StringVar(&since, "since", "", "take old schema from history store as of date, e.g. 2024-01-01")
+	flags.StringVar(&sinceVer, "since-version", "", "take old schema of version from history store, e.g. 1.4.0")
+	flags.DurationVar(&waitFor, "wait-for", 0, "time to wait until new schema is a valid document, e.g. 120s for freshly deployed service")
+	flags.BoolVar(&suggest, "suggest-commit", false, "true to print conventional commit message instead of diff")
+	flags.StringVarP((*string)(&format), "format", "f", string(rpcdiff.FormatText), "output format: text, json, markdown, html, warnings-ng, dot or mermaid")
+	flags.StringVar(&output, "output", "", "path to write report to instead of stdout, e.g. report.html")
+	flags.StringVar(&groupBy, "group-by", "", "owner to render text report with changes grouped by responsible team instead of --format")
+	flags.StringVar(&tmpl, "template", "", "path to go text/template rendering diff instead of --format, with byLevel, bySubject, counts and title helpers")
+	flags.StringVar(&summary, "summary-json", "", "path to write summary json with counts, recommended version bump and change fingerprints to")
+	flags.StringVar(&changelog, "changelog", "", "path to CHANGELOG.md to add changes grouped by added, changed and removed to unreleased section of")
+	flags.StringVar(&attest, "attestation", "", "path to write diff json signed with --sign-key to")
+	flags.StringVar(&signKey, "sign-key", "", "path to PEM ed25519 private key")
+	flags.StringVar(&upload, "upload", "", "directory, http(s) url prefix, s3://bucket/prefix or gs://bucket/prefix to upload diff json and html report to")
+
+	optionsFlags(flags, &opts, &maxScore)
+
+	command.AddCommand(
+		repoCommand(),
+		compatCommand(),
+		rulesCommand(),
+		snapshotCommand(),
+		consumersCommand(),
+		impactGoCommand(),
+		smokeCommand(),
+		mockCommand(),
+		replayCommand(),
+		normalizeCommand(),
+		mergeCommand(),
+		filterCommand(),
+		chainCommand(),
+		verifyCommand(),
+		driftCommand(),
+		fetchCommand(),
+		bundleCommand(),
+		splitCommand(),
+		mutateCommand(),
+		historyCommand(),
+		changelogCommand(),
+	)
+
+	command.SetArgs(osArgs())
+	if err := command.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+// defaultMaxScore is below base score of breaking level, scores of less critical levels never reach it
+var defaultMaxScore = rpcdiff.DefaultTaxonomy.Score(rpcdiff.Breaking) - 1
+
+// optionsFlags adds comparison flags shared by commands
+func optionsFlags(flags *pflag.FlagSet, opts *rpcdiff.Options, maxScore *int) {
+	flags.BoolVar(&opts.ShowMeta, "compare-meta", false, "true to compare schema meta info")
+	flags.BoolVar(&opts.HideExamples, "hide-examples", false, "true to skip examples comparison")
+	flags.StringSliceVar(&opts.Filter.Tags, "tag", nil, "compare only methods with any of tags")
+	flags.StringSliceVar(&opts.Filter.Methods, "method", nil, "compare only methods matching any of glob patterns, e.g. billing.*")
+	flags.StringVar((*string)(&opts.Scope), "scope", string(rpcdiff.ScopeAll), "part of schema to compare: components, methods or all")
+	flags.StringSliceVar(&opts.Objects, "only-object", nil, "report only changes of objects, e.g. METHOD_RESULT_TYPE")
+	flags.StringSliceVar(&opts.Experimental, "experimental", nil, "glob patterns of experimental methods, their changes are at most dangerous like with x-stability: experimental")
+	flags.BoolVar(&opts.IgnoreMethodServers, "ignore-method-servers", false, "true to skip comparison of servers overrides of methods")
+	flags.BoolVar(&opts.ShowLinks, "links", false, "true to render spec references of changes")
+	flags.BoolVar(&opts.ShowReasons, "reasons", false, "true to render why criticality of changes is assigned")
+	flags.BoolVar(&opts.Normalize, "normalize", false, "true to normalize schemas before comparison")
+	flags.Var(&opts.Canonicalize, "canonicalize", "comma separated toggles: ignore-key-order, null-as-absent, trim-space, sort-examples or all")
+	flags.Var(&opts.Usage, "usage", "json manifest with calls per day by method, e.g. {\"billing.Get\": 2000000}, to rank breaking changes by traffic")
+	flags.StringVar(&opts.Prometheus.URL, "prometheus", "", "prometheus url to query calls per day by method, e.g. http://prometheus:9090")
+	flags.StringVar(&opts.Prometheus.Query, "prometheus-query", rpcdiff.DefaultPrometheusQuery, "PromQL template returning calls per day, {{.Label}} is replaced by method label")
+	flags.StringVar(&opts.Prometheus.Label, "prometheus-label", rpcdiff.DefaultPrometheusLabel, "name of label with method name")
+	flags.Var(&opts.Owners, "owners", "CODEOWNERS-style file mapping method and component globs to teams, x-owner and x-team extensions take precedence")
+	flags.BoolVar(&opts.Evidence, "evidence", false, "true to render example payloads showing why param and result changes are breaking")
+	flags.BoolVar(&opts.FullValues, "full-values", false, "true to render long old and new values of changes without truncation")
+	flags.BoolVar(&opts.CheckDeterminism, "check-determinism", false, "true to compare twice and fail if changes differ in content or order")
+	flags.StringVar(&opts.RuleHook, "rule-hook", "", "command which reads changes as json lines on stdin and prints resulting changes")
+	flags.IntVar(maxScore, "max-score", defaultMaxScore, "exit with code 1 if diff score (0-100) is greater, breaking changes and policy violations fail regardless")
+}
+
+func repoCommand() *cobra.Command {
+	var (
+		config string
+		oldRef string
+		batch  rpcdiff.Batch
+		opts   rpcdiff.Options
+	)
+
+	command := &cobra.Command{
+		Use:   "repo",
+		Short: "compare schemas of every service in monorepo, exit with code 1 on breaking changes and 2 on failed services",
+		Run: func(cmd *cobra.Command, args []string) {
+			cfg, err := rpcdiff.LoadConfig(config)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(rpcdiff.ExitFailed)
+			}
+
+			cfg.Apply(&opts)
+			if err := opts.LoadUsage(); err != nil {
+				fmt.Println(err)
+				os.Exit(rpcdiff.ExitFailed)
+			}
+
+			diffs := rpcdiff.NewRepoDiff(cfg, oldRef, opts, batch)
+			fmt.Print(rpcdiff.RepoReport(diffs))
+
+			if code := rpcdiff.BatchExitCode(diffs); code != 0 {
+				os.Exit(code)
+			}
+		},
+	}
+
+	flags := command.Flags()
+	flags.SortFlags = false
+
+	flags.StringVarP(&config, "config", "c", ".rpcdiff.json", "path to config with services")
+	flags.StringVar(&oldRef, "old-ref", "", "git ref of old schemas, published urls are used if empty")
+	flags.BoolVar(&batch.FailFast, "fail-fast", false, "true to stop after the first failed or breaking service")
+	flags.IntVar(&batch.Parallel, "parallel", 4, "max number of services compared at once")
+
+	optionsFlags(flags, &opts, &batch.MaxScore)
+
+	return command
+}
+
+func compatCommand() *cobra.Command {
+	var (
+		new      string
+		maxScore int
+		opts     rpcdiff.Options
+	)
+
+	command := &cobra.Command{
+		Use:   "compat [old schemas from the oldest to the newest]",
+		Short: "check new schema compatibility with every old schema in range",
+		Args:  cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := opts.LoadUsage(); err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			diffs, err := rpcdiff.NewCompatDiffs(args, new, opts)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			fmt.Print(rpcdiff.CompatReport(diffs))
+
+			for _, cd := range diffs {
+				if cd.Error != "" || cd.Diff.IsBreaking(maxScore) {
+					os.Exit(1)
+				}
+			}
+		},
+	}
+
+	flags := command.Flags()
+	flags.SortFlags = false
+
+	flags.StringVarP(&new, "new", "n", "", "path/url to new schema")
+	cobra.MarkFlagRequired(flags, "new")
+
+	optionsFlags(flags, &opts, &maxScore)
+
+	return command
+}
+
+func rulesCommand() *cobra.Command {
+	var (
+		config string
+		opts   rpcdiff.Options
+	)
+
+	test := &cobra.Command{
+		Use:   "test [fixtures dir]",
+		Short: "run fixture pairs through comparison and check expected changes",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if config != "" {
+				cfg, err := rpcdiff.LoadConfig(config)
+				if err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+
+				cfg.Apply(&opts)
+			}
+
+			tests, err := rpcdiff.RunRuleTests(args[0], opts)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			fmt.Print(rpcdiff.RuleTestsReport(tests))
+
+			for _, rt := range tests {
+				if !rt.Passed() {
+					os.Exit(1)
+				}
+			}
+		},
+	}
+
+	flags := test.Flags()
+	flags.SortFlags = false
+
+	flags.StringVarP(&config, "config", "c", "", "path to config with taxonomy and rules")
+	flags.BoolVar(&opts.ShowMeta, "compare-meta", false, "true to compare schema meta info")
+	flags.BoolVar(&opts.HideExamples, "hide-examples", false, "true to skip examples comparison")
+	flags.StringVar(&opts.RuleHook, "rule-hook", "", "command which reads changes as json lines on stdin and prints resulting changes")
+
+	command := &cobra.Command{
+		Use:   "rules",
+		Short: "work with custom rules",
+	}
+	command.AddCommand(test)
+
+	return command
+}
+
+func snapshotCommand() *cobra.Command {
+	var (
+		dir  string
+		name string
+		opts rpcdiff.Options
+	)
+
+	save := &cobra.Command{
+		Use:   "save [schema]",
+		Short: "store canonical snapshot of schema",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			p, err := rpcdiff.SaveSnapshot(dir, args[0], name)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			fmt.Printf("Snapshot saved to: %s\n", p)
+		},
+	}
+
+	check := &cobra.Command{
+		Use:   "check [schema]",
+		Short: "compare schema with its saved snapshot, any change fails",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			diff, err := rpcdiff.CheckSnapshot(dir, args[0], name, opts)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			fmt.Println(diff.String())
+
+			if len(diff.Changes) > 0 {
+				fmt.Println("Run snapshot save to approve changes")
+				os.Exit(1)
+			}
+		},
+	}
+
+	check.Flags().BoolVar(&opts.ShowMeta, "compare-meta", false, "true to compare schema meta info")
+	check.Flags().BoolVar(&opts.HideExamples, "hide-examples", false, "true to skip examples comparison")
+
+	command := &cobra.Command{
+		Use:   "snapshot",
+		Short: "approve schema changes with saved snapshots",
+	}
+
+	flags := command.PersistentFlags()
+	flags.StringVar(&dir, "dir", ".rpcdiff/snapshots", "directory of snapshots")
+	flags.StringVar(&name, "name", "", "snapshot name, base name of schema if empty")
+
+	command.AddCommand(save, check)
+
+	return command
+}
+
+func consumersCommand() *cobra.Command {
+	var (
+		config   string
+		new      string
+		maxScore int
+		opts     rpcdiff.Options
+	)
+
+	command := &cobra.Command{
+		Use:   "consumers",
+		Short: "check new schema against schemas pinned by consumers",
+		Run: func(cmd *cobra.Command, args []string) {
+			cfg, err := rpcdiff.LoadConfig(config)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			cfg.Apply(&opts)
+			if err := opts.LoadUsage(); err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			diffs, err := rpcdiff.NewConsumerDiffs(cfg, new, opts)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			fmt.Print(rpcdiff.ConsumersReport(diffs))
+
+			for _, cd := range diffs {
+				if cd.Error != "" || cd.Diff.IsBreaking(maxScore) {
+					os.Exit(1)
+				}
+			}
+		},
+	}
+
+	flags := command.Flags()
+	flags.SortFlags = false
+
+	flags.StringVarP(&config, "config", "c", ".rpcdiff.json", "path to config with consumers")
+	flags.StringVarP(&new, "new", "n", "", "path/url to new schema")
+	cobra.MarkFlagRequired(flags, "new")
+
+	optionsFlags(flags, &opts, &maxScore)
+
+	return command
+}
+
+func changelogCommand() *cobra.Command {
+	var (
+		config string
+		old    string
+		new    string
+		date   string
+		opts   rpcdiff.Options
+	)
+
+	command := &cobra.Command{
+		Use:   "changelog",
+		Short: "print CHANGELOG.md section with changes grouped by added, changed and removed, headed by version of new schema",
+		Run: func(cmd *cobra.Command, args []string) {
+			if config != "" {
+				cfg, err := rpcdiff.LoadConfig(config)
+				if err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+
+				cfg.Apply(&opts)
+			}
+
+			diff, err := rpcdiff.NewDiff(old, new, opts)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			fmt.Print(rpcdiff.ChangelogFragment(diff, date))
+		},
+	}
+
+	flags := command.Flags()
+	flags.SortFlags = false
+
+	flags.StringVarP(&old, "old", "o", "", "path/url to old schema")
+	cobra.MarkFlagRequired(flags, "old")
+
+	flags.StringVarP(&new, "new", "n", "", "path/url to new schema")
+	cobra.MarkFlagRequired(flags, "new")
+
+	flags.StringVar(&date, "date", time.Now().Format("2006-01-02"), "release date in heading, empty to omit")
+	flags.StringVarP(&config, "config", "c", "", "path to config with taxonomy and rules")
+	flags.BoolVar(&opts.ShowMeta, "compare-meta", false, "true to compare schema meta info")
+	flags.BoolVar(&opts.HideExamples, "hide-examples", false, "true to skip examples comparison")
+	flags.StringSliceVar(&opts.Filter.Tags, "tag", nil, "compare only methods with any of tags")
+	flags.StringSliceVar(&opts.Filter.Methods, "method", nil, "compare only methods matching any of glob patterns, e.g. billing.*")
+
+	return command
+}
+
+func impactGoCommand() *cobra.Command {
+	var (
+		old  string
+		new  string
+		pkg  string
+		opts rpcdiff.Options
+	)
+
+	command := &cobra.Command{
+		Use:   "impact-go",
+		Short: "find go call sites of methods with breaking changes",
+		Run: func(cmd *cobra.Command, args []string) {
+			diff, err := rpcdiff.NewDiff(old, new, opts)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			sites, err := rpcdiff.GoImpact(pkg, diff)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			fmt.Print(rpcdiff.ImpactReport(sites))
+
+			if len(sites) > 0 {
+				os.Exit(1)
+			}
+		},
+	}
+
+	flags := command.Flags()
+	flags.SortFlags = false
+
+	flags.StringVar(&pkg, "pkg", "./...", "directory of go sources, /... suffix to scan recursively")
+	flags.StringVarP(&old, "old", "o", "", "path/url to old schema")
+	cobra.MarkFlagRequired(flags, "old")
+
+	flags.StringVarP(&new, "new", "n", "", "path/url to new schema")
+	cobra.MarkFlagRequired(flags, "new")
+
+	return command
+}
+
+func smokeCommand() *cobra.Command {
+	var (
+		old  string
+		new  string
+		out  string
+		opts rpcdiff.Options
+	)
+
+	command := &cobra.Command{
+		Use:   "smoke",
+		Short: "generate json-rpc requests for methods touched by diff",
+		Run: func(cmd *cobra.Command, args []string) {
+			diff, err := rpcdiff.NewDiff(old, new, opts)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			newBytes, err := rpcdiff.ReadFileOrURL(new)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			files, err := rpcdiff.WriteSmokeRequests(out, newBytes, diff)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			for _, f := range files {
+				fmt.Println(f)
+			}
+		},
+	}
+
+	flags := command.Flags()
+	flags.SortFlags = false
+
+	flags.StringVarP(&old, "old", "o", "", "path/url to old schema")
+	cobra.MarkFlagRequired(flags, "old")
+
+	flags.StringVarP(&new, "new", "n", "", "path/url to new schema")
+	cobra.MarkFlagRequired(flags, "new")
+
+	flags.StringVar(&out, "out", "smoke", "directory to write requests to")
+	flags.BoolVar(&opts.ShowMeta, "compare-meta", false, "true to compare schema meta info")
+
+	return command
+}
+
+func mockCommand() *cobra.Command {
+	var (
+		schema string
+		listen string
+	)
+
+	command := &cobra.Command{
+		Use:   "mock",
+		Short: "serve json-rpc responses synthesized from schema",
+		Run: func(cmd *cobra.Command, args []string) {
+			data, err := rpcdiff.ReadFileOrURL(schema)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			h, err := rpcdiff.NewMockHandler(data)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			fmt.Printf("Serving mock of %s on %s\n", schema, listen)
+			if err := http.ListenAndServe(listen, h); err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	flags := command.Flags()
+	flags.SortFlags = false
+
+	flags.StringVarP(&schema, "schema", "s", "", "path/url to schema")
+	cobra.MarkFlagRequired(flags, "schema")
+
+	flags.StringVar(&listen, "listen", ":8080", "address to listen on")
+
+	return command
+}
+
+func replayCommand() *cobra.Command {
+	var (
+		schema       string
+		interactions string
+	)
+
+	command := &cobra.Command{
+		Use:   "replay",
+		Short: "validate recorded json-rpc interactions against schema",
+		Run: func(cmd *cobra.Command, args []string) {
+			schemaBytes, err := rpcdiff.ReadFileOrURL(schema)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			data, err := rpcdiff.ReadFileOrURL(interactions)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			results, err := rpcdiff.Replay(data, schemaBytes)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			fmt.Print(rpcdiff.ReplayReport(results))
+
+			for _, rr := range results {
+				if len(rr.Errors) > 0 {
+					os.Exit(1)
+				}
+			}
+		},
+	}
+
+	flags := command.Flags()
+	flags.SortFlags = false
+
+	flags.StringVarP(&schema, "schema", "s", "", "path/url to schema")
+	cobra.MarkFlagRequired(flags, "schema")
+
+	flags.StringVarP(&interactions, "interactions", "i", "", "path/url to json lines of {request, response} or har archive")
+	cobra.MarkFlagRequired(flags, "interactions")
+
+	return command
+}
+
+func normalizeCommand() *cobra.Command {
+	var out string
+
+	command := &cobra.Command{
+		Use:   "normalize [schema]",
+		Short: "sort methods, drop empty fields and format schema",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			data, err := rpcdiff.ReadFileOrURL(args[0])
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			if data, err = rpcdiff.NormalizeSchema(data); err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			if out == "" {
+				os.Stdout.Write(data)
+				return
+			}
+
+			if err := ioutil.WriteFile(out, data, 0644); err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	command.Flags().StringVarP(&out, "out", "o", "", "path to write normalized schema to, stdout if empty")
+
+	return command
+}
+
+func mergeCommand() *cobra.Command {
+	var out string
+
+	command := &cobra.Command{
+		Use:   "merge [schemas]",
+		Short: "merge methods and components of schemas into one document, info is taken from the first schema",
+		Args:  cobra.MinimumNArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			docs := make([][]byte, 0, len(args))
+			for _, source := range args {
+				data, err := rpcdiff.ReadFileOrURL(source)
+				if err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+
+				docs = append(docs, data)
+			}
+
+			data, err := rpcdiff.MergeSchemas(args, docs)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			if out == "" {
+				os.Stdout.Write(data)
+				return
+			}
+
+			if err := ioutil.WriteFile(out, data, 0644); err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	command.Flags().StringVarP(&out, "out", "o", "", "path to write merged schema to, stdout if empty")
+
+	return command
+}
+
+func filterCommand() *cobra.Command {
+	var (
+		schema string
+		out    string
+		filter rpcdiff.Filter
+	)
+
+	command := &cobra.Command{
+		Use:   "filter",
+		Short: "keep only selected methods and components they reference",
+		Run: func(cmd *cobra.Command, args []string) {
+			data, err := rpcdiff.ReadFileOrURL(schema)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			if data, err = filter.Apply(data); err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			if data, err = rpcdiff.CanonicalJSON(data); err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			if out == "" {
+				os.Stdout.Write(data)
+				return
+			}
+
+			if err := ioutil.WriteFile(out, data, 0644); err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	flags := command.Flags()
+	flags.SortFlags = false
+
+	flags.StringVarP(&schema, "schema", "s", "", "path/url to schema")
+	cobra.MarkFlagRequired(flags, "schema")
+
+	flags.StringSliceVar(&filter.Tags, "tag", nil, "keep methods with any of tags")
+	flags.StringSliceVar(&filter.Methods, "method", nil, "keep methods matching any of glob patterns, e.g. billing.*")
+	flags.StringVarP(&out, "out", "o", "", "path to write filtered schema to, stdout if empty")
+
+	return command
+}
+
+func chainCommand() *cobra.Command {
+	var (
+		maxScore int
+		opts     rpcdiff.Options
+	)
+
+	command := &cobra.Command{
+		Use:   "compare [schemas from the oldest to the newest]",
+		Short: "compare every adjacent pair of schemas and the first schema with the last one",
+		Args:  cobra.MinimumNArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			diffs, err := rpcdiff.NewChainDiffs(args, opts)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			fmt.Print(rpcdiff.ChainReport(diffs))
+
+			for _, pd := range diffs {
+				if pd.Diff.IsBreaking(maxScore) {
+					os.Exit(1)
+				}
+			}
+		},
+	}
+
+	optionsFlags(command.Flags(), &opts, &maxScore)
+
+	return command
+}
+
+func verifyCommand() *cobra.Command {
+	var key string
+
+	command := &cobra.Command{
+		Use:   "verify [attestation]",
+		Short: "verify signed diff and print it",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			diff, err := rpcdiff.ReadAttestation(args[0], key)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			fmt.Println(diff.String())
+		},
+	}
+
+	command.Flags().StringVar(&key, "key", "", "path to PEM ed25519 public key")
+	cobra.MarkFlagRequired(command.Flags(), "key")
+
+	return command
+}
+
+func driftCommand() *cobra.Command {
+	var (
+		expected string
+		actual   string
+		config   string
+		opts     rpcdiff.Options
+	)
+
+	command := &cobra.Command{
+		Use:   "drift",
+		Short: "check that running service or generated schema matches schema committed in repository, exit with code 1 on drift",
+		Run: func(cmd *cobra.Command, args []string) {
+			if config != "" {
+				cfg, err := rpcdiff.LoadConfig(config)
+				if err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+
+				cfg.Apply(&opts)
+
+				diffs := rpcdiff.NewGeneratedDrift(cfg, opts)
+				fmt.Print(rpcdiff.GeneratedDriftReport(diffs))
+
+				for _, sd := range diffs {
+					if sd.Error != "" || len(sd.Diff.Changes) > 0 {
+						os.Exit(1)
+					}
+				}
+				return
+			}
+
+			if expected == "" || actual == "" {
+				fmt.Println("either --config or both --expected and --actual must be set")
+				os.Exit(1)
+			}
+
+			diff, err := rpcdiff.NewDrift(expected, actual, opts)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			fmt.Print(rpcdiff.DriftReport(diff))
+
+			if len(diff.Changes) > 0 {
+				os.Exit(1)
+			}
+		},
+	}
+
+	flags := command.Flags()
+	flags.StringVarP(&expected, "expected", "e", "", "path/url to committed schema")
+	flags.StringVarP(&actual, "actual", "a", "", "json-rpc endpoint of running service to call rpc.discover of, or path/url to its schema")
+	flags.StringVarP(&config, "config", "c", "", "path to config with services, runs their generate commands and compares output with committed schemas")
+
+	flags.BoolVar(&opts.ShowMeta, "compare-meta", false, "true to compare schema meta info")
+	flags.BoolVar(&opts.HideExamples, "hide-examples", false, "true to skip examples comparison")
+
+	return command
+}
+
+func fetchCommand() *cobra.Command {
+	var out string
+
+	command := &cobra.Command{
+		Use:   "fetch [path, url, rpc+url or exec:command]",
+		Short: "download schema, check it and save it formatted, e.g. as old schema for later diffs",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			data, diagnostics, err := rpcdiff.FetchSchema(args[0])
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			for _, diagnostic := range diagnostics {
+				fmt.Fprintln(os.Stderr, diagnostic.String())
+			}
+
+			if out == "" {
+				os.Stdout.Write(data)
+				return
+			}
+
+			if err := ioutil.WriteFile(out, data, 0644); err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	command.Flags().StringVarP(&out, "out", "o", "", "path to write schema to, stdout if empty")
+
+	return command
+}
+
+func bundleCommand() *cobra.Command {
+	var out string
+
+	command := &cobra.Command{
+		Use:   "bundle [root schema]",
+		Short: "inline external $refs into components of single self-contained schema",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			data, err := rpcdiff.BundleSchema(args[0])
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			if out == "" {
+				os.Stdout.Write(data)
+				return
+			}
+
+			if err := ioutil.WriteFile(out, data, 0644); err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	command.Flags().StringVarP(&out, "out", "o", "", "path to write bundled schema to, stdout if empty")
+
+	return command
+}
+
+func splitCommand() *cobra.Command {
+	var dir string
+
+	command := &cobra.Command{
+		Use:   "split [schema]",
+		Short: "extract components.schemas into separate files with rewritten $refs, inverse of bundle",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			paths, err := rpcdiff.SplitSchema(args[0], dir)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			for _, path := range paths {
+				fmt.Println(path)
+			}
+		},
+	}
+
+	command.Flags().StringVarP(&dir, "dir", "d", ".", "directory to write root schema and schemas/ to")
+
+	return command
+}
+
+func mutateCommand() *cobra.Command {
+	var out string
+
+	command := &cobra.Command{
+		Use:   "mutate [schema]",
+		Short: "generate mutated schemas with expected changes as fixtures for rules test",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			paths, err := rpcdiff.WriteMutations(args[0], out)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			for _, path := range paths {
+				fmt.Println(path)
+			}
+		},
+	}
+
+	command.Flags().StringVarP(&out, "out", "o", "mutations", "directory to write fixtures to")
+
+	return command
+}
+
+func historyCommand() *cobra.Command {
+	var (
+		config  string
+		service string
+		fromGit bool
+		path    string
+		since   string
+		format  rpcdiff.Format
+		output  string
+		opts    rpcdiff.Options
+
+		retention rpcdiff.Retention
+	)
+
+	importCmd := &cobra.Command{
+		Use:   "import",
+		Short: "backfill history store with every version of schema file and diffs between them",
+		Run: func(cmd *cobra.Command, args []string) {
+			if !fromGit {
+				fmt.Println("only import from git is supported, set --from-git")
+				os.Exit(1)
+			}
+
+			store, err := openHistoryStore(config, &opts)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			defer store.Close()
+
+			result, err := rpcdiff.ImportGitHistory(store, service, ".", path, opts)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			for _, skipped := range result.Skipped {
+				fmt.Printf("Skipped invalid version %s\n", skipped)
+			}
+			fmt.Printf("Imported %d version(s) of %s\n", result.Imported, service)
+		},
+	}
+
+	flags := importCmd.Flags()
+	flags.BoolVar(&fromGit, "from-git", false, "true to walk git history of schema file")
+	flags.StringVar(&path, "path", "", "path to schema file in git repository")
+	cobra.MarkFlagRequired(flags, "path")
+	flags.BoolVar(&opts.ShowMeta, "compare-meta", false, "true to compare schema meta info")
+	flags.BoolVar(&opts.HideExamples, "hide-examples", false, "true to skip examples comparison")
+
+	report := &cobra.Command{
+		Use:   "report",
+		Short: "render breaking changes per release, api size over time and the most churned methods",
+		Run: func(cmd *cobra.Command, args []string) {
+			sinceTime, err := rpcdiff.ParseSince(since)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			store, err := openHistoryStore(config, &opts)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			defer store.Close()
+
+			records, err := store.List(service)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			trend := rpcdiff.NewTrend(service, records, sinceTime)
+
+			var out string
+			switch format {
+			case rpcdiff.FormatMarkdown:
+				out = rpcdiff.TrendMarkdown(trend)
+			case rpcdiff.FormatHTML:
+				out, err = rpcdiff.TrendHTML(trend)
+			default:
+				err = fmt.Errorf("unknown format %q, supported: %s, %s", format, rpcdiff.FormatMarkdown, rpcdiff.FormatHTML)
+			}
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			if output == "" {
+				fmt.Print(out)
+			} else {
+				if err := ioutil.WriteFile(output, []byte(out), 0644); err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+			}
+		},
+	}
+
+	flags = report.Flags()
+	flags.StringVar(&since, "since", "", "include releases since date, e.g. 2024-01")
+	flags.StringVarP((*string)(&format), "format", "f", string(rpcdiff.FormatMarkdown), "output format: markdown or html")
+	flags.StringVar(&output, "output", "", "path to write report to instead of stdout, e.g. report.html")
+
+	catalog := &cobra.Command{
+		Use:   "catalog",
+		Short: "print backstage catalog-info annotations with compatibility status and changelog of the latest versions",
+		Run: func(cmd *cobra.Command, args []string) {
+			store, err := openHistoryStore(config, &opts)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			defer store.Close()
+
+			records, err := store.List(service)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+
+			fmt.Print(rpcdiff.CatalogReport(records))
+		},
+	}
+
+	gc := &cobra.Command{
+		Use:   "gc",
+		Short: "remove versions beyond storage retention of config or flags, the latest version of service is always kept",
+		Run: func(cmd *cobra.Command, args []string) {
+			var cfg *rpcdiff.Config
+			if config != "" {
+				var err error
+				if cfg, err = rpcdiff.LoadConfig(config); err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+			}
+
+			// flags override retention of config
+			limits := retention
+			if cfg != nil {
+				limits = cfg.Storage.Retention
+				if cmd.Flags().Changed("keep-last") {
+					limits.KeepLast = retention.KeepLast
+				}
+				if cmd.Flags().Changed("ttl-days") {
+					limits.TTLDays = retention.TTLDays
+				}
+			}
+
+			if !limits.Enabled() {
+				fmt.Println("retention is not set, use --keep-last, --ttl-days or storage.retention of config")
+				os.Exit(1)
+			}
+
+			store, err := rpcdiff.OpenConfigStore(cfg)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			defer store.Close()
+
+			removed, err := rpcdiff.CollectGarbage(store, service, limits, time.Now())
+			for _, r := range removed {
+				fmt.Printf("Removed %s@%s created %s\n", r.Service, r.Version, r.CreatedAt.Format("2006-01-02"))
+			}
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			fmt.Printf("Removed %d version(s)\n", len(removed))
+		},
+	}
+
+	flags = gc.Flags()
+	flags.IntVar(&retention.KeepLast, "keep-last", 0, "number of the latest versions to keep per service, every version if 0")
+	flags.IntVar(&retention.TTLDays, "ttl-days", 0, "remove versions older than number of days, no limit if 0")
+
+	command := &cobra.Command{
+		Use:   "history",
+		Short: "keep versions of service schemas and diffs between them in history store",
+	}
+
+	pflags := command.PersistentFlags()
+	pflags.StringVarP(&config, "config", "c", "", "path to config with storage and comparison settings, .rpcdiff/history is used if empty")
+	pflags.StringVar(&service, "service", "", "service name, catalog and gc include every service if empty")
+
+	// service is optional for catalog only
+	importCmd.PreRunE = requireFlag(&service, "service")
+	report.PreRunE = requireFlag(&service, "service")
+
+	command.AddCommand(importCmd, report, catalog, gc)
+
+	return command
+}
+
+// openHistoryStore opens store of config, filesystem store in current dir if config is empty, options are set from config
+func openHistoryStore(config string, opts *rpcdiff.Options) (rpcdiff.Store, error) {
+	if config == "" {
+		return rpcdiff.OpenStore(rpcdiff.StorageConfig{}, ".")
+	}
+
+	cfg, err := rpcdiff.LoadConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.Apply(opts)
+
+	return rpcdiff.OpenConfigStore(cfg)
+}
+
+// requireFlag returns cobra PreRunE which fails if flag value is empty
+func requireFlag(value *string, name string) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if *value == "" {
+			return fmt.Errorf("required flag \"%s\" not set", name)
+		}
+		return nil
+	}
+}
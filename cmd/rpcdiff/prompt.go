@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// promptedFlags are flags asked in interactive mode, required ones first
+var promptedFlags = []string{"old", "new", "compare-meta", "hide-examples"}
+
+// isTerminal checks that file is a character device other than null device, e.g. interactive stdin
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	if err != nil || fi.Mode()&os.ModeCharDevice == 0 {
+		return false
+	}
+
+	null, err := os.Stat(os.DevNull)
+	return err != nil || !os.SameFile(fi, null)
+}
+
+// promptFlags asks values of flags not given in command line, empty answer keeps default value
+func promptFlags(flags *pflag.FlagSet, names []string, in io.Reader, out io.Writer) error {
+	reader := bufio.NewReader(in)
+	for _, name := range names {
+		f := flags.Lookup(name)
+		if f == nil || f.Changed {
+			continue
+		}
+
+		isBool := f.Value.Type() == "bool"
+		if isBool {
+			fmt.Fprintf(out, "%s? [y/N]: ", f.Usage)
+		} else {
+			fmt.Fprintf(out, "%s: ", f.Usage)
+		}
+
+		line, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return fmt.Errorf("read answer error: %w", err)
+		}
+
+		answer := strings.TrimSpace(line)
+		if isBool {
+			switch strings.ToLower(answer) {
+			case "y", "yes":
+				answer = "true"
+			case "n", "no":
+				answer = "false"
+			}
+		}
+
+		if answer != "" {
+			if err := flags.Set(name, answer); err != nil {
+				return fmt.Errorf("invalid %s: %w", name, err)
+			}
+		}
+
+		if err == io.EOF {
+			break
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+func TestPromptFlags(t *testing.T) {
+	var (
+		old, new     string
+		meta, hideEx bool
+	)
+
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.StringVarP(&old, "old", "o", "", "path/url to old schema")
+	flags.StringVarP(&new, "new", "n", "", "path/url to new schema")
+	flags.BoolVar(&meta, "compare-meta", false, "true to compare schema meta info")
+	flags.BoolVar(&hideEx, "hide-examples", false, "true to skip examples comparison")
+
+	if err := flags.Parse([]string{"--new", "new.json"}); err != nil {
+		t.Fatalf("parse error: %s", err)
+	}
+
+	var out bytes.Buffer
+	if err := promptFlags(flags, promptedFlags, strings.NewReader("old.json\ny\n\n"), &out); err != nil {
+		t.Fatalf("promptFlags() error: %s", err)
+	}
+
+	if old != "old.json" || new != "new.json" || !meta || hideEx {
+		t.Errorf("promptFlags() = %v %v %v %v, want old.json new.json true false", old, new, meta, hideEx)
+	}
+
+	want := "path/url to old schema: true to compare schema meta info? [y/N]: true to skip examples comparison? [y/N]: "
+	if out.String() != want {
+		t.Errorf("promptFlags() output = %q, want %q", out.String(), want)
+	}
+
+	flags.Lookup("hide-examples").Changed = false
+	if err := promptFlags(flags, []string{"hide-examples"}, strings.NewReader("sure\n"), &out); err == nil {
+		t.Errorf("promptFlags() with invalid bool wanted error")
+	}
+}
@@ -0,0 +1,55 @@
+package rpcdiff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CommitSuggestion returns conventional commit message derived from diff
+func CommitSuggestion(diff *Diff) string {
+	if len(diff.Changes) == 0 {
+		return ""
+	}
+
+	typ := map[Bump]string{BumpMajor: "feat(api)!", BumpMinor: "feat(api)", BumpPatch: "fix(api)"}[recommendedBump(diff.Changes)]
+	broken := brokenSubjects(diff.Changes)
+
+	subject := "update api schema"
+	if len(diff.Changes) == 1 {
+		subject = changeSubjectLine(diff.Changes[0])
+	}
+
+	buf := strings.Builder{}
+	fmt.Fprintf(&buf, "%s: %s\n\n", typ, subject)
+	for _, line := range changelogEntries(diff) {
+		fmt.Fprintf(&buf, "%s\n", line)
+	}
+
+	if len(broken) > 0 {
+		fmt.Fprintf(&buf, "\nBREAKING CHANGE: affected %s\n", strings.Join(broken, ", "))
+	}
+
+	return buf.String()
+}
+
+// changeSubjectLine returns short imperative description of change
+func changeSubjectLine(c Change) string {
+	verb := map[ChangeType]string{Added: "add", Removed: "remove", Changed: "change"}[c.Type]
+
+	switch c.Object {
+	case Method:
+		if c.Type != Changed {
+			return fmt.Sprintf("%s method %s", verb, after(c.Path, "methods"))
+		}
+	case MethodParam:
+		if name := after(c.Path, "params"); name != "" {
+			return fmt.Sprintf("%s arg %s of %s", verb, name, after(c.Path, "methods"))
+		}
+	case ComponentsSchema:
+		if name := after(c.Path, "schemas"); name != "" {
+			return fmt.Sprintf("%s schema %s", verb, name)
+		}
+	}
+
+	return fmt.Sprintf("%s %s", verb, changeSubject(c))
+}
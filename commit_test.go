@@ -0,0 +1,43 @@
+package rpcdiff
+
+import "testing"
+
+func Test_commitSuggestion(t *testing.T) {
+	tests := []struct {
+		name    string
+		changes []Change
+		want    string
+	}{
+		{
+			name: "removed method",
+			changes: []Change{
+				{Path: []string{"methods", "user.Delete"}, Type: Removed, Object: Method, Criticality: Breaking},
+			},
+			want: "feat(api)!: remove method user.Delete\n\n- [breaking] Removed method \"user.Delete\"\n\nBREAKING CHANGE: affected user.Delete\n",
+		},
+		{
+			name: "added arg",
+			changes: []Change{
+				{Path: []string{"methods", "user.Get", "params", "full"}, Type: Added, Object: MethodParam, Criticality: NonBreaking},
+			},
+			want: "feat(api): add arg full of user.Get\n\n- [non breaking] Added optional arg \"full\" to method \"user.Get\"\n",
+		},
+		{
+			name: "several",
+			changes: []Change{
+				{Path: []string{"methods", "user.Get", "params", "a"}, Type: Removed, Object: MethodParam, Criticality: NonBreaking},
+				{Path: []string{"methods", "user.Get", "params", "b"}, Type: Removed, Object: MethodParam, Criticality: NonBreaking},
+			},
+			want: "fix(api): update api schema\n\n- [non breaking] Removed arg \"a\" from method \"user.Get\"\n- [non breaking] Removed arg \"b\" from method \"user.Get\"\n",
+		},
+		{name: "no changes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CommitSuggestion(&Diff{Changes: tt.changes})
+			if got != tt.want {
+				t.Errorf("CommitSuggestion() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package rpcdiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// CompatDiff is a diff of new schema against one of old schemas
+type CompatDiff struct {
+	Source  string `json:"source"`
+	Version string `json:"version"`
+	Diff    *Diff  `json:"diff,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Label returns version and source of old schema
+func (c CompatDiff) Label() string {
+	if c.Version == "" {
+		return c.Source
+	}
+
+	return fmt.Sprintf("%s (%s)", c.Version, c.Source)
+}
+
+// Compatible checks that new schema has no changes of Breaking or more critical levels of taxonomy against old one
+func (c CompatDiff) Compatible() bool {
+	return c.Error == "" && c.Diff != nil && !c.Diff.hasBreaking()
+}
+
+// NewCompatDiffs compares new schema with every old schema, olds are ordered from the oldest to the newest
+func NewCompatDiffs(olds []string, new string, options Options) ([]CompatDiff, error) {
+	newBytes, err := ReadFileOrURL(new)
+	if err != nil {
+		return nil, fmt.Errorf("read new schema error: %w", err)
+	}
+
+	result := make([]CompatDiff, 0, len(olds))
+	for _, old := range olds {
+		cd := CompatDiff{Source: old}
+
+		oldBytes, err := ReadFileOrURL(old)
+		if err != nil {
+			cd.Error = fmt.Sprintf("read old schema error: %s", err)
+			result = append(result, cd)
+			continue
+		}
+
+		cd.Version = schemaVersion(oldBytes)
+		if cd.Diff, err = NewDiffBytes(oldBytes, newBytes, options); err != nil {
+			cd.Error = err.Error()
+		}
+
+		result = append(result, cd)
+	}
+
+	return result, nil
+}
+
+// oldestCompatible returns the oldest schema which new schema and all schemas after it are compatible with
+func oldestCompatible(diffs []CompatDiff) *CompatDiff {
+	var result *CompatDiff
+	for i := len(diffs) - 1; i >= 0; i-- {
+		if !diffs[i].Compatible() {
+			break
+		}
+
+		result = &diffs[i]
+	}
+
+	return result
+}
+
+// CompatReport renders compatibility of new schema with every old schema
+func CompatReport(diffs []CompatDiff) string {
+	buf := strings.Builder{}
+	for _, cd := range diffs {
+		if cd.Error != "" {
+			fmt.Fprintf(&buf, "%s: error: %s\n", cd.Label(), cd.Error)
+			continue
+		}
+
+		fmt.Fprintf(&buf, "%s: %s change(s), score %d\n", cd.Label(), cd.Diff.Options.taxonomy().title(cd.Diff.Criticality), cd.Diff.Score)
+	}
+
+	if oldest := oldestCompatible(diffs); oldest != nil {
+		fmt.Fprintf(&buf, "Oldest compatible version: %s\n", oldest.Label())
+	} else {
+		buf.WriteString("New schema is not compatible with the newest old schema\n")
+	}
+
+	return buf.String()
+}
+
+// schemaVersion returns info.version of schema
+func schemaVersion(data []byte) string {
+	var doc struct {
+		Info struct {
+			Version string `json:"version"`
+		} `json:"info"`
+	}
+	_ = json.Unmarshal(data, &doc)
+
+	return doc.Info.Version
+}
@@ -0,0 +1,44 @@
+package rpcdiff
+
+import "testing"
+
+func Test_oldestCompatible(t *testing.T) {
+	diffs, err := NewCompatDiffs([]string{"testdata/openrpc_old.json", "testdata/openrpc_new.json"}, "testdata/openrpc_new.json", Options{})
+	if err != nil {
+		t.Fatalf("new compat diffs error: %s", err)
+	}
+
+	oldest := oldestCompatible(diffs)
+	if oldest == nil || oldest.Source != "testdata/openrpc_new.json" {
+		t.Fatalf("oldestCompatible() = %+v, wanted %v", oldest, "testdata/openrpc_new.json")
+	}
+
+	if diffs[0].Compatible() {
+		t.Errorf("diffs[0] must be incompatible")
+	}
+}
+
+func TestCompatDiff_Compatible(t *testing.T) {
+	taxonomy := Taxonomy{{Level: "CRITICAL"}, {Level: Breaking}, {Level: Dangerous}, {Level: NonBreaking}}
+
+	tests := []struct {
+		name        string
+		criticality CriticalityLevel
+		taxonomy    Taxonomy
+		want        bool
+	}{
+		{name: "breaking", criticality: Breaking, want: false},
+		{name: "dangerous", criticality: Dangerous, want: true},
+		{name: "custom level above breaking", criticality: "CRITICAL", taxonomy: taxonomy, want: false},
+		{name: "dangerous of custom taxonomy", criticality: Dangerous, taxonomy: taxonomy, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := CompatDiff{Diff: &Diff{Criticality: tt.criticality, Options: Options{Taxonomy: tt.taxonomy}}}
+			if got := c.Compatible(); got != tt.want {
+				t.Errorf("Compatible() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package rpcdiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+)
+
+// Config is a configuration file of rpcdiff, paths in config are relative to its directory
+type Config struct {
+	Services      []ServiceConfig  `json:"services"`
+	Consumers     []ConsumerConfig `json:"consumers,omitempty"`
+	Policy        *Policy          `json:"policy,omitempty"`
+	Budget        *Budget          `json:"budget,omitempty"`
+	Taxonomy      Taxonomy         `json:"taxonomy,omitempty"`
+	Rules         Rules            `json:"rules,omitempty"`
+	Canonicalize  Canonicalize     `json:"canonicalize"`
+	IgnoreServers []string         `json:"ignoreServers,omitempty"`
+	Experimental  []string         `json:"experimental,omitempty"` // glob patterns of experimental methods
+	Storage       StorageConfig    `json:"storage"`                // history store, filesystem in .rpcdiff/history by default
+	Prometheus    *Prometheus      `json:"prometheus,omitempty"`   // source of calls per day by method
+
+	dir string
+}
+
+// ServiceConfig maps service name to its schema
+type ServiceConfig struct {
+	Name string `json:"name"`
+	Path string `json:"path"`          // path to schema file in repository
+	URL  string `json:"url,omitempty"` // url of published schema
+
+	Generate string `json:"generate,omitempty"` // command printing schema generated from code, run in config dir
+}
+
+// LoadConfig reads json config from file
+func LoadConfig(path string) (*Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read config error: %w", err)
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("parse config error: %w", err)
+	}
+
+	for i, service := range cfg.Services {
+		if service.Name == "" {
+			return nil, fmt.Errorf("service #%d: name is empty", i)
+		}
+		if service.Path == "" {
+			return nil, fmt.Errorf("service %s: path is empty", service.Name)
+		}
+	}
+
+	for i, consumer := range cfg.Consumers {
+		if consumer.Name == "" {
+			return nil, fmt.Errorf("consumer #%d: name is empty", i)
+		}
+		if consumer.Schema == "" {
+			return nil, fmt.Errorf("consumer %s: schema is empty", consumer.Name)
+		}
+	}
+
+	if len(cfg.Taxonomy) > 0 {
+		if err := cfg.Taxonomy.Validate(); err != nil {
+			return nil, fmt.Errorf("taxonomy: %w", err)
+		}
+	}
+
+	if err := cfg.Rules.Validate(); err != nil {
+		return nil, fmt.Errorf("rules: %w", err)
+	}
+
+	cfg.dir = filepath.Dir(path)
+
+	return &cfg, nil
+}
+
+// Apply sets comparison options from config, canonicalize toggles are merged with flags
+func (c *Config) Apply(opts *Options) {
+	opts.Policy = c.Policy
+	opts.Budget = c.Budget
+	opts.Taxonomy = c.Taxonomy
+	opts.Rules = c.Rules
+	opts.Canonicalize = opts.Canonicalize.merge(c.Canonicalize)
+	opts.IgnoreServers = c.IgnoreServers
+	opts.Experimental = append(opts.Experimental, c.Experimental...)
+	if c.Prometheus != nil && opts.Prometheus.URL == "" {
+		opts.Prometheus = *c.Prometheus
+	}
+}
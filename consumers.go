@@ -0,0 +1,115 @@
+package rpcdiff
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ConsumerConfig is a consumer pinned to a schema version
+type ConsumerConfig struct {
+	Name   string `json:"name"`
+	Schema string `json:"schema"` // path or url of pinned schema
+}
+
+// ConsumerDiff is a diff of new schema against schema pinned by consumer
+type ConsumerDiff struct {
+	Consumer string   `json:"consumer"`
+	Version  string   `json:"version"`
+	Broken   []string `json:"broken,omitempty"` // methods and components with breaking changes
+	Diff     *Diff    `json:"diff,omitempty"`
+	Error    string   `json:"error,omitempty"`
+}
+
+// NewConsumerDiffs compares new schema with pinned schema of every configured consumer
+func NewConsumerDiffs(cfg *Config, new string, options Options) ([]ConsumerDiff, error) {
+	newBytes, err := ReadFileOrURL(new)
+	if err != nil {
+		return nil, fmt.Errorf("read new schema error: %w", err)
+	}
+
+	result := make([]ConsumerDiff, 0, len(cfg.Consumers))
+	for _, consumer := range cfg.Consumers {
+		cd := ConsumerDiff{Consumer: consumer.Name}
+
+		schema := consumer.Schema
+		if _, err := url.ParseRequestURI(schema); err != nil {
+			schema = filepath.Join(cfg.dir, schema)
+		}
+
+		oldBytes, err := ReadFileOrURL(schema)
+		if err != nil {
+			cd.Error = fmt.Sprintf("read pinned schema error: %s", err)
+			result = append(result, cd)
+			continue
+		}
+
+		cd.Version = schemaVersion(oldBytes)
+		if cd.Diff, err = NewDiffBytes(oldBytes, newBytes, options); err != nil {
+			cd.Error = err.Error()
+		}
+
+		if cd.Diff != nil {
+			cd.Broken = brokenSubjects(cd.Diff.Changes)
+		}
+
+		result = append(result, cd)
+	}
+
+	return result, nil
+}
+
+// brokenSubjects returns sorted methods and components with breaking changes
+func brokenSubjects(changes []Change) []string {
+	seen := map[string]bool{}
+	for _, c := range changes {
+		if c.Criticality == Breaking {
+			seen[changeSubject(c)] = true
+		}
+	}
+
+	result := make([]string, 0, len(seen))
+	for s := range seen {
+		result = append(result, s)
+	}
+	sort.Strings(result)
+
+	return result
+}
+
+// changeSubject returns method name or component path of change
+func changeSubject(c Change) string {
+	if name := after(c.Path, "methods"); name != "" {
+		return name
+	}
+
+	if len(c.Path) > 3 && c.Path[0] == "components" {
+		return strings.Join(c.Path[:3], ".")
+	}
+
+	return strings.Join(c.Path, ".")
+}
+
+// ConsumersReport renders matrix of consumers and broken methods
+func ConsumersReport(diffs []ConsumerDiff) string {
+	buf := strings.Builder{}
+	for _, cd := range diffs {
+		label := cd.Consumer
+		if cd.Version != "" {
+			label = fmt.Sprintf("%s (%s)", cd.Consumer, cd.Version)
+		}
+
+		switch {
+		case cd.Error != "":
+			fmt.Fprintf(&buf, "%s: error: %s\n", label, cd.Error)
+		case len(cd.Broken) == 0:
+			fmt.Fprintf(&buf, "%s: compatible\n", label)
+		default:
+			fmt.Fprintf(&buf, "%s: broken %s\n", label, strings.Join(cd.Broken, ", "))
+		}
+	}
+
+	return buf.String()
+}
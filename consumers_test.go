@@ -0,0 +1,37 @@
+package rpcdiff
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewConsumerDiffs(t *testing.T) {
+	cfg := &Config{
+		Consumers: []ConsumerConfig{
+			{Name: "web", Schema: "openrpc_old.json"},
+			{Name: "mobile", Schema: "openrpc_new.json"},
+			{Name: "legacy", Schema: "missing.json"},
+		},
+		dir: "testdata",
+	}
+
+	diffs, err := NewConsumerDiffs(cfg, "testdata/openrpc_new.json", Options{})
+	if err != nil {
+		t.Fatalf("new consumer diffs error: %s", err)
+	}
+
+	if len(diffs[0].Broken) == 0 || !contains(diffs[0].Broken, "check.RemovedMethod") {
+		t.Errorf("diffs[0].Broken = %v, wanted check.RemovedMethod", diffs[0].Broken)
+	}
+
+	if len(diffs[1].Broken) != 0 || diffs[1].Error != "" {
+		t.Errorf("diffs[1] = %+v, wanted compatible", diffs[1])
+	}
+
+	report := ConsumersReport(diffs)
+	for _, want := range []string{"web (", "mobile (", ": compatible\n", "legacy: error: "} {
+		if !strings.Contains(report, want) {
+			t.Errorf("ConsumersReport() doesn't contain %q:\n%s", want, report)
+		}
+	}
+}
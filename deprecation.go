@@ -0,0 +1,123 @@
+package rpcdiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// deprecatedSince returns x-deprecated-since versions of methods and schema properties by change path
+func deprecatedSince(data []byte) map[string]string {
+	var raw struct {
+		Methods []struct {
+			Name  string `json:"name"`
+			Since string `json:"x-deprecated-since"`
+		} `json:"methods"`
+		Components struct {
+			Schemas map[string]struct {
+				Properties map[string]struct {
+					Since string `json:"x-deprecated-since"`
+				} `json:"properties"`
+			} `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil
+	}
+
+	result := map[string]string{}
+	for _, method := range raw.Methods {
+		if method.Since != "" {
+			result[strings.Join([]string{"methods", method.Name}, ".")] = method.Since
+		}
+	}
+
+	for schemaName, schema := range raw.Components.Schemas {
+		for propName, prop := range schema.Properties {
+			if prop.Since != "" {
+				result[strings.Join([]string{"components", "schemas", schemaName, "properties", propName}, ".")] = prop.Since
+			}
+		}
+	}
+
+	return result
+}
+
+// applyGracePeriod downgrades removals of methods and properties deprecated at least grace minor versions ago,
+// removals before grace period has elapsed are reported as violations
+func (p *Policy) applyGracePeriod(changes []Change, deprecated map[string]string, newVersion string) []Violation {
+	if p == nil || len(deprecated) == 0 {
+		return nil
+	}
+
+	var violations []Violation
+	for i, c := range changes {
+		if c.Type != Removed || (c.Object != Method && c.Object != ComponentsSchemaProperty) {
+			continue
+		}
+
+		since, ok := deprecated[strings.Join(c.Path, ".")]
+		if !ok {
+			continue
+		}
+
+		if versionElapsed(since, newVersion, p.DeprecationGrace) {
+			changes[i].Criticality = NonBreaking
+			changes[i].Reason = fmt.Sprintf("removed after deprecation grace period since %s", since)
+			continue
+		}
+
+		violations = append(violations, Violation{
+			Rule:    RuleDeprecationGrace,
+			Path:    c.Path,
+			Message: fmt.Sprintf(`%s is removed in %s before grace period of %d minor version(s) since %s`, strings.Join(c.Path, "."), newVersion, p.DeprecationGrace, since),
+		})
+	}
+
+	return violations
+}
+
+// versionElapsed checks that version is at least grace minor versions after since, new major version always elapses
+func versionElapsed(since, version string, grace int) bool {
+	sinceVersion, ok := parseVersion(since)
+	if !ok {
+		return false
+	}
+
+	newVersion, ok := parseVersion(version)
+	if !ok {
+		return false
+	}
+
+	if newVersion[0] != sinceVersion[0] {
+		return newVersion[0] > sinceVersion[0]
+	}
+
+	return newVersion[1]-sinceVersion[1] >= grace && (newVersion[1] > sinceVersion[1] || newVersion[2] >= sinceVersion[2])
+}
+
+// parseVersion parses major, minor and patch of semantic version with optional v prefix
+func parseVersion(version string) ([3]int, bool) {
+	var result [3]int
+
+	version = strings.TrimPrefix(version, "v")
+	if i := strings.IndexAny(version, "-+"); i >= 0 {
+		version = version[:i]
+	}
+
+	parts := strings.Split(version, ".")
+	if len(parts) == 0 || len(parts) > 3 {
+		return result, false
+	}
+
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return result, false
+		}
+		result[i] = n
+	}
+
+	return result, true
+}
@@ -0,0 +1,74 @@
+package rpcdiff
+
+import (
+	"fmt"
+	"testing"
+)
+
+func Test_versionElapsed(t *testing.T) {
+	tests := []struct {
+		since   string
+		version string
+		grace   int
+		want    bool
+	}{
+		{since: "v1.2.0", version: "v1.4.0", grace: 2, want: true},
+		{since: "v1.2.0", version: "v1.3.5", grace: 2, want: false},
+		{since: "1.2.0", version: "v2.0.0", grace: 2, want: true},
+		{since: "v1.2.3", version: "v1.2.3", grace: 0, want: true},
+		{since: "v1.2.3", version: "v1.2.2", grace: 0, want: false},
+		{since: "unknown", version: "v1.2.2", grace: 0, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s-%s-%d", tt.since, tt.version, tt.grace), func(t *testing.T) {
+			if got := versionElapsed(tt.since, tt.version, tt.grace); got != tt.want {
+				t.Errorf("versionElapsed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewDiffBytes_gracePeriod(t *testing.T) {
+	old := []byte(`{
+		"openrpc": "1.2.6",
+		"info": {"title": "test", "version": "v1.2.0"},
+		"methods": [
+			{"name": "user.Get", "params": [], "result": {"name": "result", "schema": {"type": "boolean"}}},
+			{"name": "user.Sunset", "deprecated": true, "x-deprecated-since": "v1.0.0", "params": [], "result": {"name": "result", "schema": {"type": "boolean"}}},
+			{"name": "user.Early", "deprecated": true, "x-deprecated-since": "v1.2.0", "params": [], "result": {"name": "result", "schema": {"type": "boolean"}}}
+		],
+		"components": {}
+	}`)
+
+	new := []byte(`{
+		"openrpc": "1.2.6",
+		"info": {"title": "test", "version": "v1.3.0"},
+		"methods": [
+			{"name": "user.Get", "params": [], "result": {"name": "result", "schema": {"type": "boolean"}}}
+		],
+		"components": {}
+	}`)
+
+	diff, err := NewDiffBytes(old, new, Options{Policy: &Policy{DeprecationGrace: 2}})
+	if err != nil {
+		t.Fatalf("new diff error: %s", err)
+	}
+
+	levels := map[string]CriticalityLevel{}
+	for _, c := range diff.Changes {
+		levels[after(c.Path, "methods")] = c.Criticality
+	}
+
+	if levels["user.Sunset"] != NonBreaking {
+		t.Errorf("user.Sunset criticality = %v, wanted %v", levels["user.Sunset"], NonBreaking)
+	}
+
+	if levels["user.Early"] != Breaking {
+		t.Errorf("user.Early criticality = %v, wanted %v", levels["user.Early"], Breaking)
+	}
+
+	if len(diff.Violations) != 1 || diff.Violations[0].Rule != RuleDeprecationGrace {
+		t.Errorf("diff.Violations = %v, wanted one %s violation", diff.Violations, RuleDeprecationGrace)
+	}
+}
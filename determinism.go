@@ -0,0 +1,55 @@
+package rpcdiff
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// newDiffChecked compares schemas twice and fails if resulting changes differ in content or order
+func newDiffChecked(compare func(options Options) (*Diff, error), options Options) (*Diff, error) {
+	options.CheckDeterminism = false
+
+	first, err := compare(options)
+	if err != nil {
+		return nil, err
+	}
+
+	second, err := compare(options)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := sameChanges(first.Changes, second.Changes); err != nil {
+		return nil, fmt.Errorf("comparison is not deterministic: %w", err)
+	}
+
+	first.Options.CheckDeterminism = true
+
+	return first, nil
+}
+
+// sameChanges checks that changes of two runs are equal and in the same order
+func sameChanges(a, b []Change) error {
+	if len(a) != len(b) {
+		return fmt.Errorf("%d changes in the first run, %d in the second", len(a), len(b))
+	}
+
+	for i := range a {
+		ja, err := json.Marshal(a[i])
+		if err != nil {
+			return err
+		}
+
+		jb, err := json.Marshal(b[i])
+		if err != nil {
+			return err
+		}
+
+		if !bytes.Equal(ja, jb) {
+			return fmt.Errorf("change #%d is %q in the first run, %q in the second", i+1, a[i].String(), b[i].String())
+		}
+	}
+
+	return nil
+}
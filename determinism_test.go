@@ -0,0 +1,57 @@
+package rpcdiff
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestNewDiffBytes_checkDeterminism(t *testing.T) {
+	oldJSON, err := ioutil.ReadFile("testdata/openrpc_old.json")
+	if err != nil {
+		t.Fatalf("read error: %s", err)
+	}
+
+	newJSON, err := ioutil.ReadFile("testdata/openrpc_new.json")
+	if err != nil {
+		t.Fatalf("read error: %s", err)
+	}
+
+	want, err := NewDiffBytes(oldJSON, newJSON, Options{ShowMeta: true})
+	if err != nil {
+		t.Fatalf("new diff error: %s", err)
+	}
+
+	got, err := NewDiffBytes(oldJSON, newJSON, Options{ShowMeta: true, CheckDeterminism: true})
+	if err != nil {
+		t.Fatalf("new diff error: %s", err)
+	}
+
+	if err := sameChanges(got.Changes, want.Changes); err != nil {
+		t.Errorf("NewDiffBytes() with determinism check differs: %s", err)
+	}
+}
+
+func TestSameChanges(t *testing.T) {
+	a := Change{Path: []string{"methods", "a"}, Type: Removed, Object: Method, Criticality: Breaking}
+	b := Change{Path: []string{"methods", "b"}, Type: Added, Object: Method, Criticality: NonBreaking}
+
+	tests := []struct {
+		name          string
+		first, second []Change
+		wantErr       string
+	}{
+		{"equal", []Change{a, b}, []Change{a, b}, ""},
+		{"order", []Change{a, b}, []Change{b, a}, `change #1 is "Removed method \"a\""`},
+		{"count", []Change{a, b}, []Change{a}, "2 changes in the first run, 1 in the second"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := sameChanges(tt.first, tt.second)
+			if tt.wantErr == "" && err != nil || tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("sameChanges() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
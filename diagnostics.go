@@ -0,0 +1,138 @@
+package rpcdiff
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	openrpc "github.com/vmkteam/meta-schema/v2"
+)
+
+// Diagnostic is a problem found in schema, it doesn't stop comparison
+type Diagnostic struct {
+	Schema  string   `json:"schema"` // old or new
+	Path    []string `json:"path"`
+	Message string   `json:"message"`
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("[%s] %s", d.Schema, d.Message)
+}
+
+// diagnoseDocument finds duplicate definitions in document, first definition is used for comparison.
+// Raw data is optional and used to find duplicate keys lost on unmarshalling.
+func diagnoseDocument(schema string, data []byte, doc *openrpc.OpenrpcDocument) []Diagnostic {
+	var diagnostics []Diagnostic
+	add := func(path []string, format string, args ...interface{}) {
+		diagnostics = append(diagnostics, Diagnostic{
+			Schema:  schema,
+			Path:    path,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	// methods and their params
+	methods := map[string]bool{}
+	for _, method := range doc.Methods {
+		if method.MethodObject == nil {
+			continue
+		}
+
+		if methods[method.Name] {
+			add([]string{"methods", method.Name}, `duplicate method "%s", first definition is used`, method.Name)
+			continue
+		}
+		methods[method.Name] = true
+
+		params := map[string]bool{}
+		for _, param := range method.Params {
+			name := paramName(param, doc)
+			if params[name] {
+				add([]string{"methods", method.Name, "params", name}, `duplicate arg "%s" at method "%s", first definition is used`, name, method.Name)
+			}
+			params[name] = true
+		}
+	}
+
+	if doc.Components == nil || doc.Components.Schemas == nil {
+		return diagnostics
+	}
+
+	// schemas
+	schemas := map[string]bool{}
+	for _, s := range *doc.Components.Schemas {
+		if s.JSONSchemaObject == nil {
+			continue
+		}
+
+		if schemas[s.Id] {
+			add([]string{"components", "schemas", s.Id}, `duplicate schema "%s", first definition is used`, s.Id)
+		}
+		schemas[s.Id] = true
+	}
+
+	if len(data) == 0 {
+		return diagnostics
+	}
+
+	var raw struct {
+		Components struct {
+			Schemas json.RawMessage `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return diagnostics
+	}
+
+	for _, key := range duplicateKeys(raw.Components.Schemas) {
+		add([]string{"components", "schemas", key}, `duplicate schema "%s", last definition is used`, key)
+	}
+
+	return diagnostics
+}
+
+// duplicateKeys returns keys which appear more than once in json object
+func duplicateKeys(data json.RawMessage) []string {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if t, err := dec.Token(); err != nil || t != json.Delim('{') {
+		return nil
+	}
+
+	var result []string
+	seen := map[string]bool{}
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return result
+		}
+
+		key, _ := t.(string)
+		if seen[key] {
+			result = append(result, key)
+		}
+		seen[key] = true
+
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return result
+		}
+	}
+
+	return result
+}
+
+// recoverChanges runs comparison of path, its panic is reported as diagnostic so other paths are still compared
+func recoverChanges(path []string, compare func() []Change) (changes []Change, diagnostics []Diagnostic) {
+	defer func() {
+		if r := recover(); r != nil {
+			changes, diagnostics = nil, []Diagnostic{{
+				Schema:  "diff",
+				Path:    appendPath(path),
+				Message: fmt.Sprintf(`comparison of "%s" failed: %v`, strings.Join(path, "."), r),
+			}}
+		}
+	}()
+
+	return compare(), nil
+}
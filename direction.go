@@ -0,0 +1,117 @@
+package rpcdiff
+
+import (
+	"strings"
+
+	openrpc "github.com/vmkteam/meta-schema/v2"
+)
+
+// schemaDirections returns direction of every components schema by methods which reference it directly,
+// through content descriptors or other schemas in any of documents, schemas not used by methods are missing.
+func schemaDirections(docs ...*openrpc.OpenrpcDocument) map[string]schemaDirection {
+	inputs, outputs := map[string]bool{}, map[string]bool{}
+	for _, doc := range docs {
+		if doc == nil {
+			continue
+		}
+
+		for _, method := range doc.Methods {
+			for _, param := range method.Params {
+				reachSchemas(descriptorSchema(param.ContentDescriptorObject, param.ReferenceObject, doc), doc, inputs)
+			}
+
+			if method.Result != nil {
+				reachSchemas(descriptorSchema(method.Result.ContentDescriptorObject, method.Result.ReferenceObject, doc), doc, outputs)
+			}
+		}
+	}
+
+	result := map[string]schemaDirection{}
+	for name := range inputs {
+		result[name] = directionInput
+	}
+	for name := range outputs {
+		if inputs[name] {
+			result[name] = directionBoth
+		} else {
+			result[name] = directionOutput
+		}
+	}
+
+	return result
+}
+
+// descriptorSchema returns schema of content descriptor or of descriptor it references
+func descriptorSchema(cd *openrpc.ContentDescriptorObject, ref *openrpc.ReferenceObject, doc *openrpc.OpenrpcDocument) *openrpc.JSONSchema {
+	if cd == nil && ref != nil {
+		cd = resolveContentDescriptor(ref.Ref, doc)
+	}
+
+	if cd == nil {
+		return nil
+	}
+
+	return cd.Schema
+}
+
+// reachSchemas adds names of components schemas referenced by schema directly or through other schemas to seen
+func reachSchemas(schema *openrpc.JSONSchema, doc *openrpc.OpenrpcDocument, seen map[string]bool) {
+	for _, ref := range schemaRefs(schema, nil) {
+		if !strings.HasPrefix(ref, schemaRefPrefix) {
+			continue
+		}
+
+		name := strings.TrimPrefix(ref, schemaRefPrefix)
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		if schemas := componentsSchemas(doc); schemas != nil {
+			if s, ok := schemas.Get(name); ok {
+				reachSchemas(&s, doc, seen)
+			}
+		}
+	}
+}
+
+// schemaRefs appends $ref values of schema and its subschemas to refs
+func schemaRefs(schema *openrpc.JSONSchema, refs []string) []string {
+	if schema == nil || schema.JSONSchemaObject == nil {
+		return refs
+	}
+
+	s := schema.JSONSchemaObject
+	if s.Ref != "" {
+		refs = append(refs, s.Ref)
+	}
+
+	for _, sub := range []*openrpc.JSONSchema{s.AdditionalItems, s.Contains, s.AdditionalProperties, s.PropertyNames, s.If, s.Then, s.Else, s.Not} {
+		refs = schemaRefs(sub, refs)
+	}
+
+	if s.Items != nil {
+		refs = schemaRefs(s.Items.JSONSchema, refs)
+		if s.Items.SchemaArray != nil {
+			for i := range *s.Items.SchemaArray {
+				refs = schemaRefs(&(*s.Items.SchemaArray)[i], refs)
+			}
+		}
+	}
+
+	for _, m := range []*openrpc.SchemaMap{s.Definitions, s.Properties, s.PatternProperties} {
+		if m != nil {
+			for i := range *m {
+				refs = schemaRefs(&(*m)[i], refs)
+			}
+		}
+	}
+
+	for _, list := range [][]openrpc.JSONSchema{s.AllOf, s.AnyOf, s.OneOf} {
+		for i := range list {
+			refs = schemaRefs(&list[i], refs)
+		}
+	}
+
+	return refs
+}
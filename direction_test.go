@@ -0,0 +1,68 @@
+package rpcdiff
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSchemaDirections(t *testing.T) {
+	schema := func(status, filter string) []byte {
+		return []byte(fmt.Sprintf(`{"openrpc":"1.2.6","info":{"title":"api","version":"1.0.0"},
+			"methods":[
+				{"name":"get","params":[{"name":"filter","schema":{"$ref":"#/components/schemas/Filter"}}],"result":{"$ref":"#/components/contentDescriptors/Result"}},
+				{"name":"find","params":[{"name":"item","schema":{"$ref":"#/components/schemas/Item"}}],"result":{"name":"r","schema":{"$ref":"#/components/schemas/Item"}}}
+			],
+			"components":{
+				"contentDescriptors":{"Result":{"name":"Result","schema":{"$ref":"#/components/schemas/User"}}},
+				"schemas":{
+					"User":{"type":"object","properties":{"status":{"$ref":"#/components/schemas/Status"}}},
+					"Status":{"type":"string","enum":%s},
+					"Filter":{"type":"string","enum":%s},
+					"Item":{"type":"string"},
+					"Unused":{"type":"string"}
+				}
+			}}`, status, filter))
+	}
+
+	old, err := parseDocument(schema(`["a","b"]`, `["a","b"]`))
+	if err != nil {
+		t.Fatalf("parseDocument() error: %s", err)
+	}
+
+	dirs := schemaDirections(old)
+	want := map[string]schemaDirection{"User": directionOutput, "Status": directionOutput, "Filter": directionInput, "Item": directionBoth}
+	for name, dir := range want {
+		if dirs[name] != dir {
+			t.Errorf("schemaDirections()[%s] = %v, want %v", name, dirs[name], dir)
+		}
+	}
+	if _, ok := dirs["Unused"]; ok {
+		t.Errorf("schemaDirections() has unused schema")
+	}
+
+	tests := []struct {
+		name           string
+		status, filter string
+		want           CriticalityLevel
+	}{
+		{name: "value dropped from output", status: `["a"]`, filter: `["a","b"]`, want: NonBreaking},
+		{name: "value added to output", status: `["a","b","c"]`, filter: `["a","b"]`, want: Dangerous},
+		{name: "value dropped from input", status: `["a","b"]`, filter: `["a"]`, want: Breaking},
+		{name: "value added to input", status: `["a","b"]`, filter: `["a","b","c"]`, want: NonBreaking},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff, err := NewDiffBytes(schema(`["a","b"]`, `["a","b"]`), schema(tt.status, tt.filter), Options{})
+			if err != nil {
+				t.Fatalf("NewDiffBytes() error: %s", err)
+			}
+
+			for _, c := range diff.Changes {
+				if c.Path[0] == "components" && last(c.Path) == "enum" && c.Criticality != tt.want {
+					t.Errorf("%s: criticality = %v, want %v", c.String(), c.Criticality, tt.want)
+				}
+			}
+		})
+	}
+}
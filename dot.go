@@ -0,0 +1,64 @@
+package rpcdiff
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// levelColor returns color of criticality level in graphs, white for nodes without changes
+func levelColor(taxonomy Taxonomy, level CriticalityLevel) string {
+	switch rank := taxonomy.rank(level); {
+	case level == "":
+		return "white"
+	case rank <= taxonomy.rank(Breaking):
+		return "red"
+	case level == PossiblyBreaking:
+		return "gold"
+	case rank < taxonomy.rank(NonBreaking):
+		return "orange"
+	case level == Info:
+		return "lightgrey"
+	default:
+		return "palegreen"
+	}
+}
+
+// dotReport renders impact graph of diff in graphviz dot, affected nodes without changes are dashed
+func dotReport(diff *Diff) string {
+	taxonomy := diff.Options.taxonomy()
+	g := newImpactGraph(diff)
+
+	buf := strings.Builder{}
+	buf.WriteString(diff.commentHeader("//"))
+	buf.WriteString("digraph rpcdiff {\n")
+	buf.WriteString("  rankdir=LR;\n")
+	buf.WriteString("  node [shape=box, style=filled];\n")
+
+	for _, n := range g.Nodes {
+		attrs := []string{
+			"label=" + strconv.Quote(n.Label),
+			"fillcolor=" + strconv.Quote(levelColor(taxonomy, n.Criticality)),
+		}
+
+		if n.Schema {
+			attrs = append(attrs, "shape=ellipse")
+		}
+
+		if n.Criticality == "" {
+			attrs = append(attrs, `style="filled,dashed"`, `tooltip="affected"`)
+		} else {
+			attrs = append(attrs, "tooltip="+strconv.Quote(taxonomy.title(n.Criticality)))
+		}
+
+		fmt.Fprintf(&buf, "  %s [%s];\n", strconv.Quote(n.ID), strings.Join(attrs, ", "))
+	}
+
+	for _, e := range g.Edges {
+		fmt.Fprintf(&buf, "  %s -> %s;\n", strconv.Quote(e[0]), strconv.Quote(e[1]))
+	}
+
+	buf.WriteString("}\n")
+
+	return buf.String()
+}
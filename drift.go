@@ -0,0 +1,142 @@
+package rpcdiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+)
+
+// rpcScheme is a prefix of schema source which is json-rpc endpoint serving rpc.discover
+const rpcScheme = "rpc+"
+
+// discoverSchema calls rpc.discover of json-rpc endpoint and returns its result
+func discoverSchema(endpoint string) ([]byte, error) {
+	body, err := httpDo(func() (*http.Request, error) {
+		req, err := http.NewRequest(http.MethodPost, endpoint, strings.NewReader(`{"jsonrpc":"2.0","method":"rpc.discover","id":1}`))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		return req, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("rpc.discover: %w", err)
+	}
+
+	var res struct {
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &res); err != nil {
+		return nil, fmt.Errorf("rpc.discover: invalid response: %w", err)
+	}
+
+	if res.Error != nil {
+		return nil, fmt.Errorf("rpc.discover: error %d: %s", res.Error.Code, res.Error.Message)
+	}
+
+	if len(res.Result) == 0 || string(res.Result) == "null" {
+		return nil, fmt.Errorf("rpc.discover: result is empty")
+	}
+
+	return res.Result, nil
+}
+
+// NewDrift compares schema committed in repository with schema of running service.
+// Actual http(s) url is json-rpc endpoint which schema is taken by rpc.discover.
+func NewDrift(expected, actual string, options Options) (*Diff, error) {
+	expectedBytes, err := ReadFileOrURL(expected)
+	if err != nil {
+		return nil, fmt.Errorf("read expected schema error: %w", err)
+	}
+
+	if strings.HasPrefix(actual, "http://") || strings.HasPrefix(actual, "https://") {
+		actual = rpcScheme + actual
+	}
+
+	actualBytes, err := ReadFileOrURL(actual)
+	if err != nil {
+		return nil, fmt.Errorf("read actual schema error: %w", err)
+	}
+
+	return newDiffSources(expected, actual, expectedBytes, actualBytes, options)
+}
+
+// DriftReport renders drift of running service from committed schema
+func DriftReport(diff *Diff) string {
+	if len(diff.Changes) == 0 {
+		return "No drift: service matches committed schema\n"
+	}
+
+	return fmt.Sprintf("Service drifted from committed schema\n%s\n", strings.TrimSuffix(diff.String(), "\n"))
+}
+
+// NewGeneratedDrift runs generator of every configured service and compares its output with committed schema.
+// Services without generator are skipped.
+func NewGeneratedDrift(cfg *Config, options Options) []ServiceDiff {
+	var result []ServiceDiff
+	for _, service := range cfg.Services {
+		if service.Generate == "" {
+			continue
+		}
+
+		sd := ServiceDiff{Service: service.Name}
+
+		diff, err := newGeneratedDiff(cfg.dir, service, options)
+		if err != nil {
+			sd.Error = err.Error()
+		}
+		sd.Diff = diff
+
+		result = append(result, sd)
+	}
+
+	return result
+}
+
+func newGeneratedDiff(dir string, service ServiceConfig, options Options) (*Diff, error) {
+	committed := filepath.Join(dir, service.Path)
+	expectedBytes, err := ReadFileOrURL(committed)
+	if err != nil {
+		return nil, fmt.Errorf("read committed schema error: %w", err)
+	}
+
+	actualBytes, err := runExec(dir, service.Generate)
+	if err != nil {
+		return nil, fmt.Errorf("generate schema error: %w", err)
+	}
+
+	return newDiffSources(committed, execScheme+service.Generate, expectedBytes, actualBytes, options)
+}
+
+// GeneratedDriftReport renders drift of generated schemas from committed ones as sections
+func GeneratedDriftReport(diffs []ServiceDiff) string {
+	if len(diffs) == 0 {
+		return "No services with generator in config\n"
+	}
+
+	buf := strings.Builder{}
+	for i, sd := range diffs {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+
+		fmt.Fprintf(&buf, "=== %s\n", sd.Service)
+		switch {
+		case sd.Error != "":
+			fmt.Fprintf(&buf, "Error: %s\n", sd.Error)
+		case len(sd.Diff.Changes) == 0:
+			buf.WriteString("No drift: generated schema matches committed schema\n")
+		default:
+			fmt.Fprintf(&buf, "Generated schema drifted from committed schema\n%s\n", strings.TrimSuffix(sd.Diff.String(), "\n"))
+		}
+	}
+
+	return buf.String()
+}
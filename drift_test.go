@@ -0,0 +1,112 @@
+package rpcdiff
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newDiscoverServer(t *testing.T, schema string) *httptest.Server {
+	data, err := ioutil.ReadFile(schema)
+	if err != nil {
+		t.Fatalf("read error: %s", err)
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string `json:"method"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Method != "rpc.discover" {
+			w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32601,"message":"Method not found"}}`))
+			return
+		}
+
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":`))
+		w.Write(data)
+		w.Write([]byte(`}`))
+	}))
+}
+
+func TestNewDrift(t *testing.T) {
+	srv := newDiscoverServer(t, "testdata/openrpc_new.json")
+	defer srv.Close()
+
+	diff, err := NewDrift("testdata/openrpc_new.json", srv.URL, Options{})
+	if err != nil {
+		t.Fatalf("new drift error: %s", err)
+	}
+
+	if got := DriftReport(diff); got != "No drift: service matches committed schema\n" {
+		t.Errorf("DriftReport() = %v, wanted no drift", got)
+	}
+
+	diff, err = NewDrift("testdata/openrpc_old.json", srv.URL, Options{})
+	if err != nil {
+		t.Fatalf("new drift error: %s", err)
+	}
+
+	if got := DriftReport(diff); !strings.HasPrefix(got, "Service drifted from committed schema\nNew schema has breaking change(s)") {
+		t.Errorf("DriftReport() = %v, wanted breaking drift", got)
+	}
+}
+
+func TestDiscoverSchema(t *testing.T) {
+	srv := newDiscoverServer(t, "testdata/openrpc_old.json")
+	defer srv.Close()
+
+	data, err := ReadFileOrURL(rpcScheme + srv.URL)
+	if err != nil {
+		t.Fatalf("ReadFileOrURL() error: %s", err)
+	}
+
+	doc, err := parseDocument(data)
+	if err != nil || doc.Openrpc == nil {
+		t.Errorf("ReadFileOrURL() = %s, wanted openrpc document", data)
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32601,"message":"Method not found"}}`))
+	}))
+	defer failing.Close()
+
+	if _, err := discoverSchema(failing.URL); err == nil || !strings.Contains(err.Error(), "Method not found") {
+		t.Errorf("discoverSchema() error = %v, wanted Method not found", err)
+	}
+}
+
+func TestNewGeneratedDrift(t *testing.T) {
+	cfg := &Config{
+		Services: []ServiceConfig{
+			{Name: "same", Path: "openrpc_new.json", Generate: "cat openrpc_new.json"},
+			{Name: "drifted", Path: "openrpc_new.json", Generate: "cat openrpc_old.json"},
+			{Name: "published", Path: "openrpc_new.json", URL: "testdata/openrpc_old.json"},
+			{Name: "failed", Path: "openrpc_new.json", Generate: "false"},
+		},
+		dir: "testdata",
+	}
+
+	diffs := NewGeneratedDrift(cfg, Options{})
+	if len(diffs) != 3 {
+		t.Fatalf("len(diffs) = %v, wanted %v", len(diffs), 3)
+	}
+
+	if diffs[0].Error != "" || len(diffs[0].Diff.Changes) != 0 {
+		t.Errorf("diffs[0] = %+v, wanted no drift", diffs[0])
+	}
+
+	if diffs[1].Error != "" || len(diffs[1].Diff.Changes) == 0 {
+		t.Errorf("diffs[1] = %+v, wanted drift", diffs[1])
+	}
+
+	if diffs[2].Service != "failed" || diffs[2].Error == "" {
+		t.Errorf("diffs[2] = %+v, wanted generator error", diffs[2])
+	}
+
+	report := GeneratedDriftReport(diffs)
+	if !strings.Contains(report, "=== same\nNo drift") || !strings.Contains(report, "=== drifted\nGenerated schema drifted") || !strings.Contains(report, "=== failed\nError: ") {
+		t.Errorf("unexpected report: %s", report)
+	}
+}
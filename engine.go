@@ -0,0 +1,128 @@
+// Package rpcdiff compares openrpc schemas and reports their changes, the command line tool is in cmd/rpcdiff.
+package rpcdiff
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Fetcher reads schema from source, e.g. file path or url
+type Fetcher func(source string) ([]byte, error)
+
+// defaultConcurrency is a number of schemas fetched at once by default
+const defaultConcurrency = 2
+
+// Engine compares schemas, it is configured by EngineOption and safe for concurrent use
+type Engine struct {
+	fetch       Fetcher
+	options     Options
+	rules       Rules // rules of WithRules, they precede rules of options
+	concurrency int
+}
+
+// EngineOption configures Engine
+type EngineOption func(e *Engine)
+
+// WithFetcher sets fetcher of schemas, ReadFileOrURL is used by default
+func WithFetcher(f Fetcher) EngineOption {
+	return func(e *Engine) {
+		if f != nil {
+			e.fetch = f
+		}
+	}
+}
+
+// WithOptions sets comparison options, rules of WithRules are kept in any order of engine options
+func WithOptions(options Options) EngineOption {
+	return func(e *Engine) {
+		e.options = options
+	}
+}
+
+// WithOptionsFunc changes fields of comparison options set by preceding engine options, e.g. resets them to zero values
+func WithOptionsFunc(set func(o *Options)) EngineOption {
+	return func(e *Engine) {
+		set(&e.options)
+	}
+}
+
+// WithRules adds user rules of changes criticality, they take precedence over rules of options
+func WithRules(r Rules) EngineOption {
+	return func(e *Engine) {
+		e.rules = append(e.rules, r...)
+	}
+}
+
+// WithConcurrency sets max number of schemas fetched at once, values less than 1 mean sequential fetching
+func WithConcurrency(n int) EngineOption {
+	return func(e *Engine) {
+		if n < 1 {
+			n = 1
+		}
+		e.concurrency = n
+	}
+}
+
+// New returns engine configured by options
+func New(opts ...EngineOption) *Engine {
+	e := &Engine{
+		fetch:       ReadFileOrURL,
+		concurrency: defaultConcurrency,
+	}
+
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	if len(e.rules) > 0 {
+		e.options.Rules = append(append(Rules{}, e.rules...), e.options.Rules...)
+	}
+
+	return e
+}
+
+// Options returns comparison options of engine
+func (e *Engine) Options() Options {
+	return e.options
+}
+
+// Diff fetches and compares old and new schemas
+func (e *Engine) Diff(old, new string) (*Diff, error) {
+	data, errs := e.fetchAll([]string{old, new})
+	if errs[0] != nil {
+		return nil, fmt.Errorf("read old schema error: %w", errs[0])
+	}
+
+	if errs[1] != nil {
+		return nil, fmt.Errorf("read new schema error: %w", errs[1])
+	}
+
+	return newDiffSources(old, new, data[0], data[1], e.options)
+}
+
+// DiffBytes compares old and new schemas
+func (e *Engine) DiffBytes(old, new []byte) (*Diff, error) {
+	return NewDiffBytes(old, new, e.options)
+}
+
+// fetchAll fetches sources with at most concurrency fetches at once, remote ones are slow
+func (e *Engine) fetchAll(sources []string) ([][]byte, []error) {
+	var (
+		data = make([][]byte, len(sources))
+		errs = make([]error, len(sources))
+		sem  = make(chan struct{}, e.concurrency)
+		wg   sync.WaitGroup
+	)
+
+	for i, source := range sources {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, source string) {
+			defer func() { <-sem; wg.Done() }()
+			data[i], errs[i] = e.fetch(source)
+		}(i, source)
+	}
+	wg.Wait()
+
+	return data, errs
+}
@@ -0,0 +1,78 @@
+package rpcdiff
+
+import (
+	"errors"
+	"io/ioutil"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNew(t *testing.T) {
+	rules := Rules{{Object: Method, Type: Removed, Criticality: Dangerous}}
+	e := New(WithOptions(Options{ShowMeta: true}), WithRules(rules), WithConcurrency(0))
+
+	if !e.Options().ShowMeta || len(e.Options().Rules) != 1 {
+		t.Errorf("Options() = %+v, want meta and rules", e.Options())
+	}
+
+	if e.concurrency != 1 {
+		t.Errorf("concurrency = %v, want %v", e.concurrency, 1)
+	}
+
+	if e := New(WithRules(rules), WithOptions(Options{ShowMeta: true})); len(e.Options().Rules) != 1 || !e.Options().ShowMeta {
+		t.Errorf("Options() = %+v, want meta and rules of WithRules", e.Options())
+	}
+
+	optionRules := Rules{{Object: Method, Criticality: Info}}
+	for _, e := range []*Engine{
+		New(WithOptions(Options{Rules: optionRules}), WithRules(rules)),
+		New(WithRules(rules), WithOptions(Options{Rules: optionRules})),
+	} {
+		if got := e.Options().Rules; len(got) != 2 || got[0].Criticality != Dangerous || got[1].Criticality != Info {
+			t.Errorf("Options().Rules = %+v, want rules of WithRules before rules of options", got)
+		}
+	}
+
+	if e := New(WithOptions(Options{ShowMeta: true, HideExamples: true}), WithOptionsFunc(func(o *Options) {
+		o.Scope = ScopeMethods
+		o.HideExamples = false
+	})); !e.Options().ShowMeta || e.Options().Scope != ScopeMethods || e.Options().HideExamples {
+		t.Errorf("Options() = %+v, want options changed by WithOptionsFunc", e.Options())
+	}
+}
+
+func TestEngine_Diff(t *testing.T) {
+	var running, max int32
+	fetch := func(source string) ([]byte, error) {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			m := atomic.LoadInt32(&max)
+			if n <= m || atomic.CompareAndSwapInt32(&max, m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		return ioutil.ReadFile(source)
+	}
+
+	diff, err := New(WithFetcher(fetch), WithConcurrency(1)).Diff("testdata/openrpc_old.json", "testdata/openrpc_new.json")
+	if err != nil {
+		t.Fatalf("Diff() error: %s", err)
+	}
+
+	if diff.Criticality != Breaking || diff.Old.Source != "testdata/openrpc_old.json" {
+		t.Errorf("Diff() = %v from %v, want %v", diff.Criticality, diff.Old.Source, Breaking)
+	}
+
+	if max != 1 {
+		t.Errorf("concurrent fetches = %v, want %v", max, 1)
+	}
+
+	failing := func(source string) ([]byte, error) { return nil, errors.New("unavailable") }
+	if _, err := New(WithFetcher(failing)).Diff("old.json", "new.json"); err == nil {
+		t.Errorf("Diff() error = nil, want fetch error")
+	}
+}
@@ -0,0 +1,142 @@
+package rpcdiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	openrpc "github.com/vmkteam/meta-schema/v2"
+)
+
+// Evidence is a synthesized payload valid against one schema which fails against the other
+type Evidence struct {
+	ValidFor string      `json:"validFor"` // old or new
+	Payload  interface{} `json:"payload"`  // params of request or result of response
+	Error    string      `json:"error"`    // validation error against the other schema
+}
+
+func (e Evidence) String() string {
+	return fmt.Sprintf("%s %s", e.payload(), e.verdict())
+}
+
+// payload returns payload as json
+func (e Evidence) payload() string {
+	data, _ := json.Marshal(e.Payload)
+	return string(data)
+}
+
+// verdict explains which schema accepts payload and why the other one rejects it
+func (e Evidence) verdict() string {
+	other := "new"
+	if e.ValidFor == "new" {
+		other = "old"
+	}
+
+	return fmt.Sprintf("is valid for %s schema, %s schema rejects it: %s", e.ValidFor, other, e.Error)
+}
+
+// paramObjects and resultObjects are objects of changes which get evidence
+var (
+	paramObjects  = map[ChangeObject]bool{MethodParam: true, MethodParamType: true, MethodParamStructure: true}
+	resultObjects = map[ChangeObject]bool{MethodResult: true, MethodResultType: true, MethodResultLoosened: true}
+)
+
+// addEvidence synthesizes evidence of breaking param and result changes: requests valid for old schema
+// which new schema rejects and results of new schema which old schema rejects
+func addEvidence(changes []Change, oldDoc, newDoc *openrpc.OpenrpcDocument) {
+	memo := map[string]*Evidence{}
+	for i, c := range changes {
+		if c.Criticality != Breaking {
+			continue
+		}
+
+		name := after(c.Path, "methods")
+		oldMethod, newMethod := findMethod(oldDoc, name), findMethod(newDoc, name)
+		if oldMethod == nil || newMethod == nil {
+			continue
+		}
+
+		var key string
+		switch {
+		case paramObjects[c.Object]:
+			key = "params " + name
+			if _, ok := memo[key]; !ok {
+				memo[key] = paramsEvidence(oldMethod, oldDoc, newDoc)
+			}
+		case resultObjects[c.Object]:
+			key = "result " + name
+			if _, ok := memo[key]; !ok {
+				memo[key] = resultEvidence(newMethod, oldDoc, newDoc)
+			}
+		default:
+			continue
+		}
+
+		changes[i].Evidence = memo[key]
+	}
+}
+
+// paramsEvidence returns request params of old method which new schema rejects, minimal params are tried first
+func paramsEvidence(method *openrpc.MethodObject, oldDoc, newDoc *openrpc.OpenrpcDocument) *Evidence {
+	full := newSmokeRequest(method, oldDoc)
+	minimal := full
+	if byName, ok := full.Params.(map[string]interface{}); ok {
+		params := map[string]interface{}{}
+		for name, value := range byName {
+			if isRequiredParamName(method, name, oldDoc) {
+				params[name] = value
+			}
+		}
+		minimal.Params = params
+	}
+
+	v := &Validator{doc: newDoc}
+	for _, req := range []smokeRequest{minimal, full} {
+		data, err := json.Marshal(req)
+		if err != nil {
+			return nil
+		}
+
+		if err := v.ValidateRequest(data); err != nil {
+			return &Evidence{ValidFor: "old", Payload: req.Params, Error: validationMessage(err)}
+		}
+	}
+
+	return nil
+}
+
+// resultEvidence returns result of new method which old schema rejects
+func resultEvidence(method *openrpc.MethodObject, oldDoc, newDoc *openrpc.OpenrpcDocument) *Evidence {
+	result := mockResult(method, newDoc)
+
+	data, err := json.Marshal(map[string]interface{}{"result": result})
+	if err != nil {
+		return nil
+	}
+
+	if err := (&Validator{doc: oldDoc}).ValidateResponse(method.Name, data); err != nil {
+		return &Evidence{ValidFor: "new", Payload: result, Error: validationMessage(err)}
+	}
+
+	return nil
+}
+
+// isRequiredParamName checks that param of method is required
+func isRequiredParamName(method *openrpc.MethodObject, name string, doc *openrpc.OpenrpcDocument) bool {
+	for _, param := range method.Params {
+		if paramName(param, doc) == name {
+			return isRequiredParam(param, doc)
+		}
+	}
+
+	return false
+}
+
+// validationMessage returns problems of validation error without method name
+func validationMessage(err error) string {
+	if verr, ok := err.(*ValidationError); ok {
+		return strings.Join(verr.Errors, "; ")
+	}
+
+	return err.Error()
+}
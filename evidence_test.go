@@ -0,0 +1,55 @@
+package rpcdiff
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_addEvidence(t *testing.T) {
+	old := []byte(`{"openrpc":"1.2.6","info":{"title":"api","version":"1.0.0"},"methods":[
+		{"name":"user.Get","params":[{"name":"id","required":true,"schema":{"type":"string"}}],"result":{"name":"r","schema":{"type":"string"}}}]}`)
+	new := []byte(`{"openrpc":"1.2.6","info":{"title":"api","version":"1.0.1"},"methods":[
+		{"name":"user.Get","params":[{"name":"id","required":true,"schema":{"type":"integer"}}],"result":{"name":"r","schema":{"type":"integer"}}}]}`)
+
+	diff, err := NewDiffBytes(old, new, Options{Evidence: true})
+	if err != nil {
+		t.Fatalf("NewDiffBytes() error: %s", err)
+	}
+
+	tests := map[ChangeObject]Evidence{
+		MethodParamType: {ValidFor: "old", Payload: map[string]interface{}{"id": "string"}},
+		MethodResult:    {ValidFor: "new", Payload: float64(1)},
+	}
+
+	for _, c := range diff.Changes {
+		want, ok := tests[c.Object]
+		if !ok {
+			continue
+		}
+		delete(tests, c.Object)
+
+		if c.Evidence == nil {
+			t.Errorf("%s evidence = nil, want %v", c.Object, want)
+			continue
+		}
+
+		if c.Evidence.ValidFor != want.ValidFor || !reflect.DeepEqual(c.Evidence.Payload, want.Payload) || c.Evidence.Error == "" {
+			t.Errorf("%s evidence = %+v, want %+v", c.Object, *c.Evidence, want)
+		}
+	}
+
+	if len(tests) != 0 {
+		t.Errorf("changes = %v, want changes of %v", diff.Changes, tests)
+	}
+
+	diff, err = NewDiffBytes(old, new, Options{})
+	if err != nil {
+		t.Fatalf("NewDiffBytes() error: %s", err)
+	}
+
+	for _, c := range diff.Changes {
+		if c.Evidence != nil {
+			t.Errorf("%s evidence = %v, want nil without option", c.Object, c.Evidence)
+		}
+	}
+}
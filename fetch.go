@@ -0,0 +1,41 @@
+package rpcdiff
+
+import (
+	"fmt"
+
+	openrpc "github.com/vmkteam/meta-schema/v2"
+)
+
+// checkDocument parses data as openrpc document with non-empty version
+func checkDocument(data []byte) (*openrpc.OpenrpcDocument, error) {
+	doc, err := parseDocument(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if doc.Openrpc == nil {
+		return nil, fmt.Errorf("openrpc version is empty")
+	}
+
+	return doc, nil
+}
+
+// FetchSchema reads schema from any source (path, url, rpc+url, exec:), checks it and formats it canonically.
+// Diagnostics of schema are returned along with formatted schema.
+func FetchSchema(source string) ([]byte, []Diagnostic, error) {
+	data, err := ReadFileOrURL(source)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read schema error: %w", err)
+	}
+
+	doc, err := checkDocument(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid schema: %w", err)
+	}
+
+	if data, err = CanonicalJSON(data); err != nil {
+		return nil, nil, err
+	}
+
+	return data, diagnoseDocument("fetched", data, doc), nil
+}
@@ -0,0 +1,41 @@
+package rpcdiff
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+)
+
+func TestFetchSchema(t *testing.T) {
+	srv := newDiscoverServer(t, "testdata/openrpc_new.json")
+	defer srv.Close()
+
+	data, _, err := FetchSchema(rpcScheme + srv.URL)
+	if err != nil {
+		t.Fatalf("FetchSchema() error: %s", err)
+	}
+
+	if !bytes.HasPrefix(data, []byte("{\n  \"")) || !bytes.HasSuffix(data, []byte("}\n")) {
+		t.Errorf("FetchSchema() = %s, wanted indented json", data)
+	}
+
+	original, err := ioutil.ReadFile("testdata/openrpc_new.json")
+	if err != nil {
+		t.Fatalf("read error: %s", err)
+	}
+
+	diff, err := NewDiffBytes(original, data, Options{ShowMeta: true})
+	if err != nil {
+		t.Fatalf("new diff error: %s", err)
+	}
+
+	if len(diff.Changes) != 0 {
+		t.Errorf("FetchSchema() changed schema: %v", diff.String())
+	}
+
+	for _, source := range []string{"testdata/missing.json", "exec:echo {}", "exec:echo [1]"} {
+		if _, _, err := FetchSchema(source); err == nil {
+			t.Errorf("FetchSchema(%q) wanted error", source)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package rpcdiff
+
+import (
+	"bytes"
+	"encoding/json"
+	"path"
+	"strings"
+)
+
+// Filter selects methods of schema, only components referenced by selected methods are kept
+type Filter struct {
+	Tags    []string // methods with any of tags
+	Methods []string // methods matching any of glob patterns, e.g. billing.*
+}
+
+// enabled checks that any selector is set
+func (f Filter) enabled() bool {
+	return len(f.Tags) > 0 || len(f.Methods) > 0
+}
+
+// Apply returns schema with selected methods and components they transitively reference
+func (f Filter) Apply(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var doc map[string]interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	components, _ := doc["components"].(map[string]interface{})
+	methods, _ := doc["methods"].([]interface{})
+
+	selected := []interface{}{}
+	for _, m := range methods {
+		if f.match(m, components) {
+			selected = append(selected, m)
+		}
+	}
+	doc["methods"] = selected
+
+	if components != nil {
+		doc["components"] = referencedComponents(selected, components)
+	}
+
+	return json.Marshal(doc)
+}
+
+// match checks raw method against selectors
+func (f Filter) match(method interface{}, components map[string]interface{}) bool {
+	name := methodName(method)
+	for _, pattern := range f.Methods {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+
+	m, _ := method.(map[string]interface{})
+	tags, _ := m["tags"].([]interface{})
+	for _, tag := range tags {
+		name := tagName(tag, components)
+		for _, t := range f.Tags {
+			if name == t {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// tagName returns name of raw tag object or referenced component tag
+func tagName(tag interface{}, components map[string]interface{}) string {
+	t, _ := tag.(map[string]interface{})
+	if ref, ok := t["$ref"].(string); ok {
+		t, _ = resolveRawRef(ref, components).(map[string]interface{})
+	}
+
+	name, _ := t["name"].(string)
+
+	return name
+}
+
+// resolveRawRef returns raw component by #/components/kind/name reference
+func resolveRawRef(ref string, components map[string]interface{}) interface{} {
+	kind, name, ok := splitComponentRef(ref)
+	if !ok {
+		return nil
+	}
+
+	items, _ := components[kind].(map[string]interface{})
+
+	return items[name]
+}
+
+// splitComponentRef splits #/components/kind/name reference
+func splitComponentRef(ref string) (kind, name string, ok bool) {
+	const prefix = "#/components/"
+	if !strings.HasPrefix(ref, prefix) {
+		return "", "", false
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(ref, prefix), "/", 2)
+	if len(parts) != 2 {
+		return "", "", false
+	}
+
+	unescape := strings.NewReplacer("~1", "/", "~0", "~")
+
+	return parts[0], unescape.Replace(parts[1]), true
+}
+
+// referencedComponents returns components transitively referenced by value
+func referencedComponents(value interface{}, components map[string]interface{}) map[string]interface{} {
+	result := map[string]interface{}{}
+
+	queue := collectRefs(value, nil)
+	seen := map[string]bool{}
+	for len(queue) > 0 {
+		ref := queue[0]
+		queue = queue[1:]
+
+		if seen[ref] {
+			continue
+		}
+		seen[ref] = true
+
+		item := resolveRawRef(ref, components)
+		if item == nil {
+			continue
+		}
+
+		kind, name, _ := splitComponentRef(ref)
+		items, ok := result[kind].(map[string]interface{})
+		if !ok {
+			items = map[string]interface{}{}
+			result[kind] = items
+		}
+		items[name] = item
+
+		queue = collectRefs(item, queue)
+	}
+
+	return result
+}
+
+// collectRefs appends all $ref values found in raw json value
+func collectRefs(v interface{}, refs []string) []string {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		if ref, ok := val["$ref"].(string); ok {
+			refs = append(refs, ref)
+		}
+
+		for _, item := range val {
+			refs = collectRefs(item, refs)
+		}
+	case []interface{}:
+		for _, item := range val {
+			refs = collectRefs(item, refs)
+		}
+	}
+
+	return refs
+}
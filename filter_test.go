@@ -0,0 +1,83 @@
+package rpcdiff
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestFilter_apply(t *testing.T) {
+	data := []byte(`{"openrpc": "1.2.6", "info": {"title": "test", "version": "1.0.0"}, "methods": [
+		{"name": "user.Get", "tags": [{"$ref": "#/components/tags/public"}], "params": [], "result": {"$ref": "#/components/contentDescriptors/User"}},
+		{"name": "user.Sync", "tags": [{"name": "internal"}], "params": [], "result": {"name": "r", "schema": {"$ref": "#/components/schemas/Internal"}}}
+	], "components": {
+		"tags": {"public": {"name": "public"}},
+		"contentDescriptors": {"User": {"name": "User", "schema": {"$ref": "#/components/schemas/User"}}},
+		"schemas": {
+			"User": {"type": "object", "properties": {"address": {"$ref": "#/components/schemas/Address"}}},
+			"Address": {"type": "string"},
+			"Internal": {"type": "string"}
+		}
+	}}`)
+
+	got, err := Filter{Tags: []string{"public"}}.Apply(data)
+	if err != nil {
+		t.Fatalf("filter error: %s", err)
+	}
+
+	var doc struct {
+		Methods []struct {
+			Name string `json:"name"`
+		} `json:"methods"`
+		Components map[string]map[string]interface{} `json:"components"`
+	}
+	if err := json.Unmarshal(got, &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(doc.Methods) != 1 || doc.Methods[0].Name != "user.Get" {
+		t.Errorf("methods = %+v, wanted user.Get", doc.Methods)
+	}
+
+	components := map[string][]string{}
+	for kind, items := range doc.Components {
+		for name := range items {
+			components[kind] = append(components[kind], name)
+		}
+	}
+
+	for kind := range components {
+		sort.Strings(components[kind])
+	}
+
+	want := map[string][]string{
+		"tags":               {"public"},
+		"contentDescriptors": {"User"},
+		"schemas":            {"Address", "User"},
+	}
+	if !reflect.DeepEqual(components, want) {
+		t.Errorf("components = %v, want %v", components, want)
+	}
+}
+
+func TestNewDiff_methodFilter(t *testing.T) {
+	diff, err := NewDiff("testdata/openrpc_old.json", "testdata/openrpc_new.json", Options{
+		Filter: Filter{Methods: []string{"check.Add*", "check.RemovedMethod"}},
+	})
+	if err != nil {
+		t.Fatalf("new diff error: %s", err)
+	}
+
+	if len(diff.Changes) == 0 {
+		t.Fatalf("wanted changes of selected methods")
+	}
+
+	for _, c := range diff.Changes {
+		name := after(c.Path, "methods")
+		if name != "check.RemovedMethod" && !strings.HasPrefix(name, "check.Add") {
+			t.Errorf("unexpected change %s", c.String())
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package rpcdiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Format is an output format of diff
+type Format string
+
+const (
+	FormatText       Format = "text"
+	FormatJSON       Format = "json"
+	FormatMarkdown   Format = "markdown"
+	FormatHTML       Format = "html"
+	FormatWarningsNG Format = "warnings-ng"
+	FormatDot        Format = "dot"
+	FormatMermaid    Format = "mermaid"
+)
+
+// formats are supported output formats
+var formats = []Format{FormatText, FormatJSON, FormatMarkdown, FormatHTML, FormatWarningsNG, FormatDot, FormatMermaid}
+
+// Validate checks that format is supported
+func (f Format) Validate() error {
+	for _, format := range formats {
+		if f == format {
+			return nil
+		}
+	}
+
+	names := make([]string, 0, len(formats))
+	for _, format := range formats {
+		names = append(names, string(format))
+	}
+
+	return fmt.Errorf("unknown format %q, supported: %s", f, strings.Join(names, ", "))
+}
+
+// RenderDiff renders diff of schema file in format, reports start with metadata
+func RenderDiff(diff *Diff, format Format, file string) (string, error) {
+	switch format {
+	case FormatJSON:
+		return jsonReport(diff)
+	case FormatMarkdown:
+		return markdownReport(diff), nil
+	case FormatHTML:
+		return htmlReport(diff)
+	case FormatWarningsNG:
+		return warningsNGReport(diff, file)
+	case FormatDot:
+		return dotReport(diff), nil
+	case FormatMermaid:
+		return mermaidReport(diff), nil
+	default:
+		return diff.Header() + "\n" + diff.String() + "\n", nil
+	}
+}
+
+// jsonReport serializes full diff with metadata, changes and their old and new values
+func jsonReport(diff *Diff) (string, error) {
+	data, err := json.MarshalIndent(diff, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(data) + "\n", nil
+}
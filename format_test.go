@@ -0,0 +1,38 @@
+package rpcdiff
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func Test_renderDiff_json(t *testing.T) {
+	diff, err := NewDiff("testdata/openrpc_old.json", "testdata/openrpc_new.json", Options{})
+	if err != nil {
+		t.Fatalf("new diff error: %s", err)
+	}
+
+	out, err := RenderDiff(diff, FormatJSON, "testdata/openrpc_new.json")
+	if err != nil {
+		t.Fatalf("RenderDiff() error: %s", err)
+	}
+
+	var got Diff
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("unmarshal error: %s", err)
+	}
+
+	if got.Criticality != diff.Criticality || len(got.Changes) != len(diff.Changes) {
+		t.Fatalf("RenderDiff() = %v with %d changes, want %v with %d changes", got.Criticality, len(got.Changes), diff.Criticality, len(diff.Changes))
+	}
+
+	for i, c := range got.Changes {
+		want := diff.Changes[i]
+		if c.Fingerprint != want.Fingerprint || c.Type != want.Type || c.Object != want.Object || len(c.Path) != len(want.Path) {
+			t.Errorf("change %d = %+v, want %+v", i, c, want)
+		}
+
+		if (c.Old == nil) != (want.Old == nil) || (c.New == nil) != (want.New == nil) {
+			t.Errorf("change %d values = %v %v, want %v %v", i, c.Old, c.New, want.Old, want.New)
+		}
+	}
+}
@@ -3,15 +3,17 @@ module github.com/vmkteam/rpcdiff
 go 1.17
 
 require (
+	github.com/antonmedv/expr v1.12.7
 	github.com/fatih/structs v1.1.0
+	github.com/lib/pq v1.9.0
+	github.com/mattn/go-sqlite3 v2.0.3+incompatible
 	github.com/spf13/cobra v1.4.0
+	github.com/spf13/pflag v1.0.5
 	github.com/thoas/go-funk v0.6.0
 	github.com/vmkteam/meta-schema/v2 v2.0.1
 )
 
 require (
-	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/iancoleman/orderedmap v0.2.0 // indirect
 	github.com/inconshreveable/mousetrap v1.0.0 // indirect
-	github.com/spf13/pflag v1.0.5 // indirect
 )
@@ -0,0 +1,160 @@
+package rpcdiff
+
+import (
+	"sort"
+	"strings"
+)
+
+// prefixes of references to components schemas and content descriptors
+const (
+	schemaRefPrefix     = "#/components/schemas/"
+	descriptorRefPrefix = "#/components/contentDescriptors/"
+)
+
+// graphNode is a changed or affected method or components schema
+type graphNode struct {
+	ID          string // method name or components.schemas.Name
+	Label       string
+	Schema      bool
+	Criticality CriticalityLevel // the most critical change, empty for nodes affected by changed schemas only
+}
+
+// impactGraph is a graph of changed methods and schemas with their $ref dependencies
+type impactGraph struct {
+	Nodes []graphNode
+	Edges [][2]string // dependent node id, dependency node id
+}
+
+// newImpactGraph builds graph of changed methods and schemas and methods and schemas which depend on them
+// by $ref in old or new schema
+func newImpactGraph(diff *Diff) impactGraph {
+	taxonomy := diff.Options.taxonomy()
+
+	levels := map[string]CriticalityLevel{}
+	for _, c := range diff.Changes {
+		id := graphNodeID(c)
+		if id == "" {
+			continue
+		}
+
+		if level, ok := levels[id]; !ok || taxonomy.rank(c.Criticality) < taxonomy.rank(level) {
+			levels[id] = c.Criticality
+		}
+	}
+
+	deps := map[string]map[string]bool{}
+	for _, data := range [][]byte{diff.oldJSON, diff.newJSON} {
+		collectDependencies(data, deps)
+	}
+
+	// nodes are changed nodes and their dependents
+	included := map[string]bool{}
+	var include func(id string)
+	include = func(id string) {
+		if included[id] {
+			return
+		}
+		included[id] = true
+
+		for dependent, dependencies := range deps {
+			if dependencies[id] {
+				include(dependent)
+			}
+		}
+	}
+	for id := range levels {
+		include(id)
+	}
+
+	var g impactGraph
+	for id := range included {
+		node := graphNode{ID: id, Label: id, Criticality: levels[id]}
+		if strings.HasPrefix(id, "components.schemas.") {
+			node.Schema, node.Label = true, strings.TrimPrefix(id, "components.schemas.")
+		}
+		g.Nodes = append(g.Nodes, node)
+
+		for dependency := range deps[id] {
+			if included[dependency] {
+				g.Edges = append(g.Edges, [2]string{id, dependency})
+			}
+		}
+	}
+
+	sort.Slice(g.Nodes, func(i, j int) bool { return g.Nodes[i].ID < g.Nodes[j].ID })
+	sort.Slice(g.Edges, func(i, j int) bool {
+		if g.Edges[i][0] != g.Edges[j][0] {
+			return g.Edges[i][0] < g.Edges[j][0]
+		}
+		return g.Edges[i][1] < g.Edges[j][1]
+	})
+
+	return g
+}
+
+// graphNodeID returns node of change, empty for changes of info, servers and other document fields
+func graphNodeID(c Change) string {
+	switch {
+	case len(c.Path) > 1 && c.Path[0] == "methods":
+		return c.Path[1]
+	case len(c.Path) > 2 && c.Path[0] == "components" && c.Path[1] == "schemas":
+		return strings.Join(c.Path[:3], ".")
+	}
+
+	return ""
+}
+
+// collectDependencies adds schemas referenced by every method directly or through content descriptors
+// and by every components schema of document to deps
+func collectDependencies(data []byte, deps map[string]map[string]bool) {
+	doc, err := decodeObject(data)
+	if err != nil {
+		return
+	}
+
+	components, _ := doc["components"].(map[string]interface{})
+	descriptors, _ := components["contentDescriptors"].(map[string]interface{})
+
+	add := func(id string, v interface{}) {
+		if deps[id] == nil {
+			deps[id] = map[string]bool{}
+		}
+
+		for _, dep := range schemaDependencies(v, descriptors) {
+			deps[id][dep] = true
+		}
+	}
+
+	methods, _ := doc["methods"].([]interface{})
+	for _, v := range methods {
+		method, _ := v.(map[string]interface{})
+		if name, _ := method["name"].(string); name != "" {
+			add(name, method)
+		}
+	}
+
+	schemas, _ := components["schemas"].(map[string]interface{})
+	for name, schema := range schemas {
+		add("components.schemas."+name, schema)
+	}
+}
+
+// schemaDependencies returns node ids of components schemas referenced by v directly or through content descriptors
+func schemaDependencies(v interface{}, descriptors map[string]interface{}) []string {
+	var result []string
+	for _, ref := range collectRefs(v, nil) {
+		switch {
+		case strings.HasPrefix(ref, schemaRefPrefix):
+			result = append(result, "components.schemas."+strings.TrimPrefix(ref, schemaRefPrefix))
+		case strings.HasPrefix(ref, descriptorRefPrefix):
+			// params and results referencing descriptors depend on schemas of descriptors
+			for _, ref := range collectRefs(descriptors[strings.TrimPrefix(ref, descriptorRefPrefix)], nil) {
+				if strings.HasPrefix(ref, schemaRefPrefix) {
+					result = append(result, "components.schemas."+strings.TrimPrefix(ref, schemaRefPrefix))
+				}
+			}
+		}
+	}
+
+	return result
+}
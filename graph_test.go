@@ -0,0 +1,112 @@
+package rpcdiff
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_newImpactGraph(t *testing.T) {
+	diff, err := NewDiff("testdata/openrpc_old.json", "testdata/openrpc_new.json", Options{})
+	if err != nil {
+		t.Fatalf("new diff error: %s", err)
+	}
+
+	g := newImpactGraph(diff)
+
+	nodes := map[string]graphNode{}
+	for _, n := range g.Nodes {
+		nodes[n.ID] = n
+	}
+
+	tests := []struct {
+		id          string
+		criticality CriticalityLevel
+		schema      bool
+	}{
+		{id: "check.RemovedMethod", criticality: Breaking},
+		{id: "check.SchemaUpdated"},
+		{id: "components.schemas.ChangePropType", criticality: Breaking, schema: true},
+	}
+	for _, tt := range tests {
+		n, ok := nodes[tt.id]
+		if !ok {
+			t.Errorf("newImpactGraph() has no node %s", tt.id)
+			continue
+		}
+
+		if n.Criticality != tt.criticality || n.Schema != tt.schema {
+			t.Errorf("node %s = %+v, want %v schema %v", tt.id, n, tt.criticality, tt.schema)
+		}
+	}
+
+	if _, ok := nodes["check.UntouchedMethod"]; ok {
+		t.Errorf("newImpactGraph() has untouched method")
+	}
+
+	var found bool
+	for _, e := range g.Edges {
+		found = found || e == [2]string{"check.SchemaUpdated", "components.schemas.ChangePropType"}
+	}
+	if !found {
+		t.Errorf("newImpactGraph() edges = %v, want edge of check.SchemaUpdated", g.Edges)
+	}
+
+	out := dotReport(diff)
+	for _, want := range []string{
+		"// rpcdiff ",
+		"// New: testdata/openrpc_new.json",
+		"digraph rpcdiff {",
+		`"check.RemovedMethod" [label="check.RemovedMethod", fillcolor="red", tooltip="breaking"];`,
+		`"check.SchemaUpdated" -> "components.schemas.ChangePropType";`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("dotReport() = %v, want %v", out, want)
+		}
+	}
+}
+
+func Test_mermaidReport(t *testing.T) {
+	diff, err := NewDiff("testdata/openrpc_old.json", "testdata/openrpc_new.json", Options{})
+	if err != nil {
+		t.Fatalf("new diff error: %s", err)
+	}
+
+	out := mermaidReport(diff)
+	for _, want := range []string{
+		"```mermaid\nflowchart LR\n%% rpcdiff ",
+		"%% Old: testdata/openrpc_old.json",
+		`n7["check.RemovedMethod"]`,
+		`n11(["ChangePropType"])`,
+		"n8 --> n11",
+		"class n1,n3,n7,n11,n12,n13 red",
+		"classDef white fill:white,stroke-dasharray:5 5",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("mermaidReport() = %v, want %v", out, want)
+		}
+	}
+}
+
+func Test_newImpactGraph_contentDescriptors(t *testing.T) {
+	schema := func(idType string) []byte {
+		return []byte(`{"openrpc": "1.2.6", "info": {"title": "api", "version": "1"},
+			"methods": [{"name": "user.Get", "params": [], "result": {"$ref": "#/components/contentDescriptors/User"}}],
+			"components": {
+				"contentDescriptors": {"User": {"name": "User", "schema": {"$ref": "#/components/schemas/User"}}},
+				"schemas": {"User": {"type": "object", "properties": {"id": {"type": "` + idType + `"}}}}
+			}}`)
+	}
+
+	diff, err := NewDiffBytes(schema("integer"), schema("string"), Options{})
+	if err != nil {
+		t.Fatalf("new diff error: %s", err)
+	}
+
+	var found bool
+	for _, e := range newImpactGraph(diff).Edges {
+		found = found || e == [2]string{"user.Get", "components.schemas.User"}
+	}
+	if !found {
+		t.Errorf("newImpactGraph() edges = %v, want edge of user.Get", newImpactGraph(diff).Edges)
+	}
+}
@@ -0,0 +1,162 @@
+package rpcdiff
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// gitVersion is a commit which changed schema file
+type gitVersion struct {
+	Hash      string
+	Tag       string
+	CreatedAt time.Time
+}
+
+// name returns tag of version or its short hash
+func (v gitVersion) name() string {
+	if v.Tag != "" {
+		return v.Tag
+	}
+
+	if len(v.Hash) > 12 {
+		return v.Hash[:12]
+	}
+
+	return v.Hash
+}
+
+// HistoryImport is a result of history import
+type HistoryImport struct {
+	Imported int
+	Skipped  []string // versions which are not valid documents
+}
+
+// ImportGitHistory saves every version of schema file from git history to store with diff against previous version.
+// Path is relative to dir, versions with unchanged schema are skipped, records of the same schema are replaced.
+func ImportGitHistory(store Store, service, dir, path string, options Options) (*HistoryImport, error) {
+	versions, err := gitVersions(dir, path)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		result HistoryImport
+		prev   []byte
+		last   time.Time
+	)
+	for _, v := range versions {
+		data, err := gitShow(dir, v.Hash, path)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := parseDocument(data); err != nil {
+			result.Skipped = append(result.Skipped, fmt.Sprintf("%s: %s", v.name(), err))
+			continue
+		}
+
+		r := Record{Service: service, Version: v.name(), Digest: digest(data), CreatedAt: v.CreatedAt, Schema: data}
+
+		// keep order of commits made in the same second
+		if !r.CreatedAt.After(last) {
+			r.CreatedAt = last.Add(time.Nanosecond)
+		}
+		if prev != nil {
+			if r.Digest == digest(prev) {
+				continue
+			}
+
+			if r.Diff, err = NewDiffBytes(prev, data, options); err != nil {
+				return nil, fmt.Errorf("version %s: %w", v.name(), err)
+			}
+		}
+
+		if err := store.Put(r); err != nil {
+			return nil, fmt.Errorf("version %s: %w", v.name(), err)
+		}
+
+		prev, last = data, r.CreatedAt
+		result.Imported++
+	}
+
+	return &result, nil
+}
+
+// gitVersions returns commits which changed path, the oldest first, with their tags. Commits deleting path are skipped.
+func gitVersions(dir, path string) ([]gitVersion, error) {
+	// every status except deletions, which have no file to show; git 2.39 lists nothing with --diff-filter=d and a path
+	out, err := git(dir, "log", "--reverse", "--diff-filter=ACMRT", "--format=%H %ct", "--", path)
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := gitTags(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []gitVersion
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+
+		ts, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("git log: invalid commit time %q", fields[1])
+		}
+
+		result = append(result, gitVersion{Hash: fields[0], Tag: tags[fields[0]], CreatedAt: time.Unix(ts, 0).UTC()})
+	}
+
+	if len(result) == 0 {
+		return nil, fmt.Errorf("no commits of %s found", path)
+	}
+
+	return result, nil
+}
+
+// gitTags returns tag names by commit hash, annotated tags are dereferenced
+func gitTags(dir string) (map[string]string, error) {
+	out, err := git(dir, "for-each-ref", "--sort=creatordate", "--format=%(objectname) %(*objectname) %(refname:short)", "refs/tags")
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]string{}
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Split(line, " ")
+		if len(fields) != 3 {
+			continue
+		}
+
+		hash := fields[0]
+		if fields[1] != "" {
+			hash = fields[1]
+		}
+		result[hash] = fields[2]
+	}
+
+	return result, nil
+}
+
+// git runs git command in dir and returns its stdout
+func git(dir string, args ...string) (string, error) {
+	var stderr bytes.Buffer
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s error: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+
+	return string(out), nil
+}
@@ -0,0 +1,98 @@
+package rpcdiff
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// newGitRepo creates git repository with commits of schema versions, versions with tag are tagged,
+// empty schema deletes file
+func newGitRepo(t *testing.T, path string, versions []struct{ schema, tag string }) string {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	run := func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com", "GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v error: %s: %s", args, err, out)
+		}
+	}
+
+	run("init", "-q")
+	for i, v := range versions {
+		if v.schema == "" {
+			run("rm", "-q", path)
+		} else {
+			data, err := ioutil.ReadFile(v.schema)
+			if err != nil {
+				data = []byte(v.schema)
+			}
+
+			if err := ioutil.WriteFile(filepath.Join(dir, path), data, 0644); err != nil {
+				t.Fatalf("write error: %s", err)
+			}
+
+			run("add", path)
+		}
+		run("commit", "-q", "--allow-empty", "-m", fmt.Sprintf("version %d", i))
+		if v.tag != "" {
+			run("tag", "-a", v.tag, "-m", v.tag)
+		}
+	}
+
+	return dir
+}
+
+func TestImportGitHistory(t *testing.T) {
+	dir := newGitRepo(t, "api.json", []struct{ schema, tag string }{
+		{schema: "testdata/openrpc_old.json", tag: "v1.0.0"},
+		{schema: "{"},
+		{schema: "testdata/openrpc_old.json"},
+		{schema: ""},
+		{schema: "testdata/openrpc_new.json", tag: "v1.1.0"},
+	})
+
+	store, err := OpenStore(StorageConfig{}, dir)
+	if err != nil {
+		t.Fatalf("OpenStore() error: %s", err)
+	}
+
+	result, err := ImportGitHistory(store, "billing", dir, "api.json", Options{})
+	if err != nil {
+		t.Fatalf("ImportGitHistory() error: %s", err)
+	}
+
+	if result.Imported != 2 || len(result.Skipped) != 1 {
+		t.Errorf("ImportGitHistory() = %+v, want 2 imported and 1 skipped version", result)
+	}
+
+	records, err := store.List("billing")
+	if err != nil {
+		t.Fatalf("List() error: %s", err)
+	}
+
+	if len(records) != 2 || records[0].Version != "v1.0.0" || records[1].Version != "v1.1.0" {
+		t.Fatalf("records = %+v, want v1.0.0 and v1.1.0", records)
+	}
+
+	if records[0].Diff != nil || records[1].Diff == nil || records[1].Diff.Criticality != Breaking {
+		t.Errorf("diffs = %v, %v, want breaking diff of the second version only", records[0].Diff, records[1].Diff)
+	}
+
+	// import is idempotent
+	if _, err := ImportGitHistory(store, "billing", dir, "api.json", Options{}); err != nil {
+		t.Fatalf("ImportGitHistory() error: %s", err)
+	}
+
+	if records, err = store.List(""); err != nil || len(records) != 2 {
+		t.Errorf("records after second import = %d, %v, want 2", len(records), err)
+	}
+}
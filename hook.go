@@ -0,0 +1,54 @@
+package rpcdiff
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// runRuleHook streams changes as json lines to external command and reads resulting changes back.
+// Hook prints every change it keeps (modified or not) and extra changes, one json per line.
+func runRuleHook(command string, changes []Change) ([]Change, error) {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return changes, nil
+	}
+
+	var stdin, stdout, stderr bytes.Buffer
+	enc := json.NewEncoder(&stdin)
+	for _, c := range changes {
+		if err := enc.Encode(c); err != nil {
+			return nil, err
+		}
+	}
+
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = &stdin
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("rule hook error: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	var result []Change
+	scanner := bufio.NewScanner(&stdout)
+	scanner.Buffer(nil, 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+
+		var c Change
+		if err := json.Unmarshal(scanner.Bytes(), &c); err != nil {
+			return nil, fmt.Errorf("rule hook output line %d: %w", line, err)
+		}
+
+		result = append(result, c)
+	}
+
+	return result, scanner.Err()
+}
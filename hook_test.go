@@ -0,0 +1,49 @@
+package rpcdiff
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewDiff_ruleHook(t *testing.T) {
+	// hook drops method error changes and adds one extra change
+	hook := filepath.Join(t.TempDir(), "hook.sh")
+	script := `#!/bin/sh
+grep -v '"object":"METHOD_ERROR"'
+echo '{"path":["methods","check.Custom"],"type":"REMOVED","object":"METHOD","criticality":"BREAKING"}'
+`
+	if err := ioutil.WriteFile(hook, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	plain, err := NewDiff("testdata/openrpc_old.json", "testdata/openrpc_new.json", Options{})
+	if err != nil {
+		t.Fatalf("new diff error: %s", err)
+	}
+
+	diff, err := NewDiff("testdata/openrpc_old.json", "testdata/openrpc_new.json", Options{RuleHook: hook})
+	if err != nil {
+		t.Fatalf("new diff error: %s", err)
+	}
+
+	var errors int
+	for _, c := range plain.Changes {
+		if c.Object == MethodError {
+			errors++
+		}
+	}
+
+	if len(diff.Changes) != len(plain.Changes)-errors+1 {
+		t.Errorf("changes = %v, wanted %v", len(diff.Changes), len(plain.Changes)-errors+1)
+	}
+
+	last := diff.Changes[len(diff.Changes)-1]
+	if last.String() != `Removed method "check.Custom"` || last.Score == 0 {
+		t.Errorf("extra change = %v, score %v", last.String(), last.Score)
+	}
+
+	if _, err := NewDiff("testdata/openrpc_old.json", "testdata/openrpc_new.json", Options{RuleHook: "false"}); err == nil {
+		t.Errorf("failed hook must return error")
+	}
+}
@@ -0,0 +1,220 @@
+package rpcdiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"sort"
+	"strings"
+)
+
+// htmlTemplate is a standalone report, sections of methods and schemas with critical changes are expanded
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>rpcdiff: {{.New}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #24292f; }
+code, pre { font-family: SFMono-Regular, Consolas, Menlo, monospace; font-size: 90%; }
+pre { background: #f6f8fa; padding: .5em; margin: .25em 0; overflow-x: auto; }
+details { border: 1px solid #d0d7de; border-radius: 6px; margin: .5em 0; padding: .5em 1em; }
+summary { cursor: pointer; font-weight: 600; }
+table { border-collapse: collapse; width: 100%; margin-top: .5em; }
+td { border-top: 1px solid #d0d7de; padding: .4em; vertical-align: top; }
+.badge { display: inline-block; border-radius: 1em; padding: 0 .6em; font-size: 80%; font-weight: 600; white-space: nowrap; }
+.meta { color: #57606a; }
+</style>
+</head>
+<body>
+<h1>Schema diff</h1>
+<p class="meta">{{.Tool}}<br>Old: <code>{{.Old}}</code><br>New: <code>{{.New}}</code>{{if .Options}}<br>Options: {{.Options}}{{end}}</p>
+{{if .Verdict}}<p><span class="badge" style="{{.Style}}">{{.Verdict}}</span> score {{.Score}}</p>{{else}}<p>There is no difference between schemas</p>{{end}}
+{{range .Lists}}<h2>{{.Title}} ({{len .Items}})</h2>
+<ul>{{range .Items}}<li>{{.}}</li>{{end}}</ul>
+{{end}}{{range .Sections}}<details{{if .Open}} open{{end}}>
+<summary><span class="badge" style="{{.Style}}">{{.Level}}</span> <code>{{.Subject}}</code> ({{len .Changes}})</summary>
+<table>
+{{range .Changes}}<tr id="{{.Anchor}}">
+<td><span class="badge" style="{{.Style}}">{{.Level}}</span></td>
+<td>{{if .Link}}<a href="{{.Link}}">{{.Message}}</a>{{else}}{{.Message}}{{end}}
+{{if .Reason}}<br><span class="meta">why: {{.Reason}}</span>{{end}}
+{{if .Owner}}<br><span class="meta">owner: {{.Owner}}</span>{{end}}
+{{if .Old}}<div>old:<pre>{{.Old}}</pre></div>{{end}}
+{{if .New}}<div>new:<pre>{{.New}}</pre></div>{{end}}
+{{if .Evidence}}<div>example:<pre>{{.Evidence.Payload}}</pre>{{.Evidence.Verdict}}</div>{{end}}</td>
+</tr>
+{{end}}</table>
+</details>
+{{end}}<script>
+// expand section of linked change
+var row = location.hash && document.getElementById(location.hash.slice(1));
+if (row && row.closest("details")) { row.closest("details").open = true; row.scrollIntoView(); }
+</script>
+</body>
+</html>
+`))
+
+type htmlReportData struct {
+	Tool, Old, New, Options string
+	Verdict                 string
+	Style                   template.CSS
+	Score                   int
+	Lists                   []htmlList
+	Sections                []htmlSection
+}
+
+type htmlList struct {
+	Title string
+	Items []string
+}
+
+// htmlSection is a collapsible section of changes of one method or schema
+type htmlSection struct {
+	Subject string
+	Level   string
+	Style   template.CSS
+	Open    bool
+	Changes []htmlChange
+
+	rank int
+}
+
+type htmlChange struct {
+	Anchor        string
+	Level         string
+	Style         template.CSS
+	Message, Link string
+	Reason, Owner string
+	Old, New      string
+	Evidence      *htmlEvidence
+}
+
+type htmlEvidence struct {
+	Payload, Verdict string
+}
+
+// htmlReport renders diff as standalone html page with collapsible section per method or schema
+func htmlReport(diff *Diff) (string, error) {
+	taxonomy := diff.Options.taxonomy()
+	m := diff.Metadata
+
+	data := htmlReportData{
+		Tool:    strings.TrimSpace(m.Tool + " " + m.Version + ", " + m.CreatedAt.Format("2006-01-02 15:04:05 MST")),
+		Old:     diff.Old.String(),
+		New:     diff.New.String(),
+		Options: strings.Join(m.Options, ", "),
+		Score:   diff.Score,
+	}
+
+	if len(diff.Changes) > 0 {
+		data.Verdict, data.Style = taxonomy.title(diff.Criticality), htmlBadgeStyle(taxonomy, diff.Criticality)
+	}
+
+	lists := []htmlList{{Title: "Diagnostics"}, {Title: "Policy violations"}, {Title: "Budget warnings"}}
+	for _, d := range diff.Diagnostics {
+		lists[0].Items = append(lists[0].Items, d.String())
+	}
+	for _, v := range diff.Violations {
+		lists[1].Items = append(lists[1].Items, v.String())
+	}
+	for _, v := range diff.Budget {
+		lists[2].Items = append(lists[2].Items, v.String())
+	}
+
+	for _, list := range lists {
+		if len(list.Items) > 0 {
+			data.Lists = append(data.Lists, list)
+		}
+	}
+
+	sections := map[string]*htmlSection{}
+	for _, c := range diff.Changes {
+		subject := changeSubject(c)
+		s, ok := sections[subject]
+		if !ok {
+			s = &htmlSection{Subject: subject, rank: len(taxonomy)}
+			sections[subject] = s
+		}
+
+		if rank := taxonomy.rank(c.Criticality); rank < s.rank {
+			s.rank, s.Level, s.Style = rank, taxonomy.title(c.Criticality), htmlBadgeStyle(taxonomy, c.Criticality)
+		}
+
+		s.Changes = append(s.Changes, newHTMLChange(c, taxonomy, diff.Options))
+	}
+
+	for _, s := range sections {
+		s.Open = s.rank <= taxonomy.rank(Breaking)
+		data.Sections = append(data.Sections, *s)
+	}
+
+	// the most critical sections first
+	sort.Slice(data.Sections, func(i, j int) bool {
+		if data.Sections[i].rank != data.Sections[j].rank {
+			return data.Sections[i].rank < data.Sections[j].rank
+		}
+		return data.Sections[i].Subject < data.Sections[j].Subject
+	})
+
+	buf := strings.Builder{}
+	if err := htmlTemplate.Execute(&buf, data); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+func newHTMLChange(c Change, taxonomy Taxonomy, options Options) htmlChange {
+	result := htmlChange{
+		Anchor:  c.Anchor(),
+		Level:   taxonomy.title(c.Criticality),
+		Style:   htmlBadgeStyle(taxonomy, c.Criticality),
+		Message: c.String(),
+		Owner:   c.Owner,
+		Old:     htmlValue(c.Old),
+		New:     htmlValue(c.New),
+	}
+
+	if c.IsHeuristic() {
+		result.Message += fmt.Sprintf(" (confidence %.0f%%)", c.Confidence*100)
+	}
+
+	if options.ShowLinks {
+		result.Link = c.Reference
+	}
+
+	if options.ShowReasons {
+		result.Reason = c.Reason
+	}
+
+	if c.Evidence != nil {
+		result.Evidence = &htmlEvidence{Payload: c.Evidence.payload(), Verdict: c.Evidence.verdict()}
+	}
+
+	return result
+}
+
+// htmlBadgeStyle returns inline style of criticality badge colored as nodes of dot report
+func htmlBadgeStyle(taxonomy Taxonomy, level CriticalityLevel) template.CSS {
+	color := levelColor(taxonomy, level)
+	if color == "red" {
+		return "background: red; color: white"
+	}
+
+	return template.CSS("background: " + color + "; color: black")
+}
+
+// htmlValue renders old or new value as indented json, nil is empty
+func htmlValue(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return ""
+	}
+
+	return string(data)
+}
@@ -0,0 +1,38 @@
+package rpcdiff
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_htmlReport(t *testing.T) {
+	diff := &Diff{
+		Criticality: Breaking,
+		Score:       100,
+		Changes: []Change{
+			{Path: []string{"methods", "<b>.Add", "params", "a"}, Type: Added, Object: MethodParam, Criticality: NonBreaking, New: map[string]interface{}{"name": "a"}},
+			{Path: []string{"methods", "user.Get"}, Type: Removed, Object: Method, Criticality: Breaking, Old: map[string]interface{}{"name": "user.Get"}, Fingerprint: "4f1c"},
+		},
+	}
+
+	got, err := htmlReport(diff)
+	if err != nil {
+		t.Fatalf("htmlReport() error: %s", err)
+	}
+
+	breaking := strings.Index(got, `<details open>
+<summary><span class="badge" style="background: red; color: white">breaking</span> <code>user.Get</code> (1)</summary>`)
+	nonBreaking := strings.Index(got, `<details>
+<summary><span class="badge" style="background: palegreen; color: black">non breaking</span> <code>&lt;b&gt;.Add</code> (1)</summary>`)
+	if breaking == -1 || nonBreaking == -1 || breaking > nonBreaking {
+		t.Errorf("htmlReport() = %v, want expanded breaking section before collapsed non breaking one", got)
+	}
+
+	if !strings.Contains(got, "old:<pre>{\n  &#34;name&#34;: &#34;user.Get&#34;\n}</pre>") {
+		t.Errorf("htmlReport() = %v, want old value of removed method", got)
+	}
+
+	if !strings.Contains(got, `<tr id="change-4f1c">`) {
+		t.Errorf("htmlReport() = %v, want anchor of removed method row", got)
+	}
+}
@@ -0,0 +1,104 @@
+package rpcdiff
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CallSite is a string literal in go source matching method with breaking changes
+type CallSite struct {
+	Method   string `json:"method"`
+	Position string `json:"position"` // file:line:column
+}
+
+// GoImpact finds call sites of broken methods in go files of pattern, pattern is a directory with optional /... suffix
+func GoImpact(pattern string, diff *Diff) ([]CallSite, error) {
+	broken := map[string]string{}
+	for _, c := range diff.Changes {
+		if name := after(c.Path, "methods"); name != "" && c.Criticality == Breaking {
+			broken[strings.ToLower(name)] = name
+		}
+	}
+
+	if len(broken) == 0 {
+		return nil, nil
+	}
+
+	var files []string
+	dir, recursive := strings.TrimSuffix(pattern, "/..."), strings.HasSuffix(pattern, "/...")
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			name := info.Name()
+			if path != dir && (!recursive || name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if strings.HasSuffix(path, ".go") {
+			files = append(files, path)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result []CallSite
+	fset := token.NewFileSet()
+	for _, file := range files {
+		f, err := parser.ParseFile(fset, file, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		// zenrpc lowercases method names, so literals are matched case-insensitive
+		ast.Inspect(f, func(n ast.Node) bool {
+			lit, ok := n.(*ast.BasicLit)
+			if !ok || lit.Kind != token.STRING {
+				return true
+			}
+
+			s, err := strconv.Unquote(lit.Value)
+			if err != nil {
+				return true
+			}
+
+			if method, ok := broken[strings.ToLower(s)]; ok {
+				result = append(result, CallSite{Method: method, Position: fset.Position(lit.Pos()).String()})
+			}
+
+			return true
+		})
+	}
+
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Method < result[j].Method })
+
+	return result, nil
+}
+
+// ImpactReport renders call sites of broken methods
+func ImpactReport(sites []CallSite) string {
+	if len(sites) == 0 {
+		return "No call sites of methods with breaking changes found\n"
+	}
+
+	buf := strings.Builder{}
+	for _, site := range sites {
+		fmt.Fprintf(&buf, "%s: method \"%s\" has breaking changes\n", site.Position, site.Method)
+	}
+
+	return buf.String()
+}
@@ -0,0 +1,27 @@
+package rpcdiff
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_goImpact(t *testing.T) {
+	diff, err := NewDiff("testdata/openrpc_old.json", "testdata/openrpc_new.json", Options{})
+	if err != nil {
+		t.Fatalf("new diff error: %s", err)
+	}
+
+	sites, err := GoImpact("testdata/impact/...", diff)
+	if err != nil {
+		t.Fatalf("go impact error: %s", err)
+	}
+
+	if len(sites) != 1 || sites[0].Method != "check.RemovedMethod" || !strings.HasSuffix(sites[0].Position, "client.go:4:20") {
+		t.Errorf("GoImpact() = %+v, wanted check.RemovedMethod at client.go:4:20", sites)
+	}
+
+	// not recursive
+	if sites, _ := GoImpact("testdata/impact", diff); len(sites) != 0 {
+		t.Errorf("GoImpact() = %+v, wanted no sites", sites)
+	}
+}
@@ -0,0 +1,36 @@
+package rpcdiff
+
+// infoFields are fields which only document schema
+var infoFields = map[string]bool{
+	"summary":     true,
+	"description": true,
+	"title":       true,
+}
+
+// isInfoChange reports whether non breaking change touches only schema meta: info, server names and descriptions
+func isInfoChange(c Change) bool {
+	if c.Criticality != NonBreaking || len(c.Path) == 0 {
+		return false
+	}
+
+	switch c.Object {
+	case SchemaInfo, SchemaVersion:
+		return true
+	}
+
+	field := c.Path[len(c.Path)-1]
+	if (c.Object == SchemaServers || c.Object == MethodServers) && field == "name" && len(c.Path) >= 3 && c.Path[len(c.Path)-3] == "servers" {
+		return true
+	}
+
+	return infoFields[field]
+}
+
+// markInfo moves meta changes to Info level
+func markInfo(changes []Change) {
+	for i := range changes {
+		if isInfoChange(changes[i]) {
+			changes[i].Criticality = Info
+		}
+	}
+}
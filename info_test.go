@@ -0,0 +1,51 @@
+package rpcdiff
+
+import (
+	"testing"
+)
+
+func Test_isInfoChange(t *testing.T) {
+	tests := []struct {
+		name   string
+		change Change
+		want   bool
+	}{
+		{name: "schema info", change: Change{Path: []string{"info", "title"}, Object: SchemaInfo, Criticality: NonBreaking}, want: true},
+		{name: "method summary", change: Change{Path: []string{"methods", "a", "summary"}, Object: Method, Criticality: NonBreaking}, want: true},
+		{name: "server name", change: Change{Path: []string{"servers", "http://a", "name"}, Object: SchemaServers, Criticality: NonBreaking}, want: true},
+		{name: "server variable", change: Change{Path: []string{"servers", "http://a", "variables", "name"}, Object: SchemaServers, Criticality: NonBreaking}},
+		{name: "new method", change: Change{Path: []string{"methods", "a"}, Object: Method, Criticality: NonBreaking}},
+		{name: "dangerous description", change: Change{Path: []string{"methods", "a", "description"}, Object: Method, Criticality: Dangerous}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isInfoChange(tt.change); got != tt.want {
+				t.Errorf("isInfoChange() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewDiffBytes_info(t *testing.T) {
+	old := []byte(`{"openrpc":"1.2.6","info":{"title":"api","version":"1.0.0"},"methods":[{"name":"a","summary":"old","params":[],"result":{"name":"r","schema":{"type":"string"}}}]}`)
+	new := []byte(`{"openrpc":"1.2.6","info":{"title":"api v2","version":"1.0.1"},"methods":[{"name":"a","summary":"new","params":[],"result":{"name":"r","schema":{"type":"string"}}}]}`)
+
+	diff, err := NewDiffBytes(old, new, Options{ShowMeta: true})
+	if err != nil {
+		t.Fatalf("NewDiffBytes() error: %s", err)
+	}
+
+	if len(diff.Changes) != 3 {
+		t.Fatalf("len(diff.Changes) = %v, want %v", len(diff.Changes), 3)
+	}
+
+	for _, c := range diff.Changes {
+		if c.Criticality != Info || c.Score != 0 {
+			t.Errorf("change %v = %v with score %v, want %v with score 0", c.String(), c.Criticality, c.Score, Info)
+		}
+	}
+
+	if diff.Criticality != NonBreaking || diff.Score != 0 {
+		t.Errorf("diff = %v with score %v, want %v with score 0", diff.Criticality, diff.Score, NonBreaking)
+	}
+}
@@ -0,0 +1,145 @@
+package rpcdiff
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// quotedRe matches quoted method, schema and value names in change messages
+var quotedRe = regexp.MustCompile(`"([^"]*)"`)
+
+// markdownReport renders diff as markdown document with table of changes per criticality level
+func markdownReport(diff *Diff) string {
+	taxonomy := diff.Options.taxonomy()
+
+	buf := strings.Builder{}
+	buf.WriteString("# Schema diff\n\n")
+	fmt.Fprintf(&buf, "- Old: %s\n", markdownDocument(diff.Old))
+	fmt.Fprintf(&buf, "- New: %s\n", markdownDocument(diff.New))
+	if m := diff.Metadata; m.Tool != "" {
+		fmt.Fprintf(&buf, "- Generated: %s %s, %s\n", m.Tool, m.Version, m.CreatedAt.Format(time.RFC3339))
+		if len(m.Options) > 0 {
+			fmt.Fprintf(&buf, "- Options: %s\n", markdownCode(strings.Join(m.Options, ", ")))
+		}
+	}
+	if len(diff.Changes) == 0 {
+		buf.WriteString("- Result: no difference between schemas\n")
+	} else {
+		fmt.Fprintf(&buf, "- Result: **%s** change(s), score %d\n", taxonomy.title(diff.Criticality), diff.Score)
+	}
+
+	lists := []struct {
+		title string
+		items []fmt.Stringer
+	}{
+		{title: "Diagnostics"},
+		{title: "Policy violations"},
+		{title: "Budget warnings"},
+	}
+	for _, d := range diff.Diagnostics {
+		lists[0].items = append(lists[0].items, d)
+	}
+	for _, v := range diff.Violations {
+		lists[1].items = append(lists[1].items, v)
+	}
+	for _, v := range diff.Budget {
+		lists[2].items = append(lists[2].items, v)
+	}
+
+	for _, list := range lists {
+		if len(list.items) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(&buf, "\n## %s (%d)\n\n", list.title, len(list.items))
+		for _, item := range list.items {
+			fmt.Fprintf(&buf, "- %s\n", markdownText(item.String()))
+		}
+	}
+
+	for _, l := range taxonomy {
+		var changes []Change
+		for _, c := range diff.Changes {
+			if c.Criticality == l.Level {
+				changes = append(changes, c)
+			}
+		}
+
+		if len(changes) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(&buf, "\n## %s changes (%d)\n\n", strings.Title(taxonomy.title(l.Level)), len(changes))
+		buf.WriteString("| Change | Object | Score |\n|---|---|---|\n")
+		for _, c := range changes {
+			// anchors make rows linkable from annotations and comments
+			fmt.Fprintf(&buf, "| <a id=\"%s\"></a>%s | `%s` | %d |\n", c.Anchor(), markdownCell(markdownChange(c, diff.Options)), c.Object, c.Score)
+		}
+	}
+
+	return buf.String()
+}
+
+// markdownChange renders change message with reason, evidence and reference enabled by options
+func markdownChange(c Change, options Options) string {
+	message := markdownText(c.String())
+	if c.IsHeuristic() {
+		message += fmt.Sprintf(" (confidence %.0f%%)", c.Confidence*100)
+	}
+
+	if options.ShowLinks && c.Reference != "" {
+		message = fmt.Sprintf("[%s](%s)", message, c.Reference)
+	}
+
+	if options.ShowReasons && c.Reason != "" {
+		message += "<br>why: " + markdownText(c.Reason)
+	}
+
+	if c.Owner != "" {
+		message += "<br>owner: " + markdownCode(c.Owner)
+	}
+
+	if c.Evidence != nil {
+		message += fmt.Sprintf("<br>example: %s %s", markdownCode(c.Evidence.payload()), markdownText(c.Evidence.verdict()))
+	}
+
+	return message
+}
+
+// markdownDocument renders source of document as code with its title and version
+func markdownDocument(d Document) string {
+	source := d.Source
+	if source == "" {
+		source = "-"
+	}
+
+	result := markdownCode(source)
+	if info := strings.TrimSpace(d.Title + " " + d.Version); info != "" {
+		result += fmt.Sprintf(" (%s)", info)
+	}
+
+	return result
+}
+
+// markdownText renders quoted names of message as code
+func markdownText(s string) string {
+	return quotedRe.ReplaceAllStringFunc(s, func(quoted string) string {
+		return markdownCode(strings.Trim(quoted, `"`))
+	})
+}
+
+// markdownCode renders s as code span, backticks of s are fenced by double ones
+func markdownCode(s string) string {
+	if strings.Contains(s, "`") {
+		return "`` " + s + " ``"
+	}
+
+	return "`" + s + "`"
+}
+
+// markdownCell escapes pipes and line breaks of table cell
+func markdownCell(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+}
@@ -0,0 +1,59 @@
+package rpcdiff
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_markdownText(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: `Removed method "user.Get"`, want: "Removed method `user.Get`"},
+		{in: `Changed "x" from "a` + "`" + `b" to "c"`, want: "Changed `x` from `` a`b `` to `c`"},
+		{in: "no names", want: "no names"},
+	}
+
+	for _, tt := range tests {
+		if got := markdownText(tt.in); got != tt.want {
+			t.Errorf("markdownText(%v) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func Test_markdownReport(t *testing.T) {
+	diff := &Diff{
+		Criticality: Breaking,
+		Score:       100,
+		Old:         Document{Source: "old.json", Title: "api", Version: "1.0.0"},
+		New:         Document{Source: "new.json"},
+		Metadata:    Metadata{Tool: "rpcdiff", Version: "1.2.3", CreatedAt: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), Options: []string{"compare-meta"}},
+		Changes: []Change{
+			{Path: []string{"methods", "user.Get"}, Type: Removed, Object: Method, Criticality: Breaking, Score: 100, Fingerprint: "4f1c"},
+			{Path: []string{"methods", "a|b"}, Type: Added, Object: Method, Criticality: NonBreaking, Score: 0, Fingerprint: "9ab2"},
+		},
+	}
+
+	want := "# Schema diff\n\n" +
+		"- Old: `old.json` (api 1.0.0)\n" +
+		"- New: `new.json`\n" +
+		"- Generated: rpcdiff 1.2.3, 2024-05-01T10:00:00Z\n" +
+		"- Options: `compare-meta`\n" +
+		"- Result: **breaking** change(s), score 100\n\n" +
+		"## Breaking changes (1)\n\n" +
+		"| Change | Object | Score |\n|---|---|---|\n" +
+		"| <a id=\"change-4f1c\"></a>Removed method `user.Get` | `METHOD` | 100 |\n\n" +
+		"## Non Breaking changes (1)\n\n" +
+		"| Change | Object | Score |\n|---|---|---|\n" +
+		"| <a id=\"change-9ab2\"></a>Added method `a\\|b` | `METHOD` | 0 |\n"
+
+	if got := markdownReport(diff); got != want {
+		t.Errorf("markdownReport() = %v, want %v", got, want)
+	}
+
+	if got := markdownReport(&Diff{}); !strings.Contains(got, "- Result: no difference between schemas\n") {
+		t.Errorf("markdownReport() = %v, want no difference", got)
+	}
+}
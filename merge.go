@@ -0,0 +1,101 @@
+package rpcdiff
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// MergeSchemas merges methods and components of documents into the first one.
+// Methods and components with the same name must be equal, otherwise all conflicts are returned as error.
+func MergeSchemas(sources []string, docs [][]byte) ([]byte, error) {
+	var (
+		result    map[string]interface{}
+		conflicts []string
+		methods   []interface{}
+	)
+
+	merged := map[string]interface{}{} // components
+
+	methodIndex := map[string]int{}      // position in methods
+	methodSources := map[string]int{}    // document of method
+	componentSources := map[string]int{} // document of component
+
+	for i, data := range docs {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+
+		var doc map[string]interface{}
+		if err := dec.Decode(&doc); err != nil {
+			return nil, fmt.Errorf("%s: %w", sources[i], err)
+		}
+
+		if result == nil {
+			result = doc
+		}
+
+		// methods
+		docMethods, _ := doc["methods"].([]interface{})
+		for _, m := range docMethods {
+			name := methodName(m)
+			if j, ok := methodIndex[name]; ok {
+				if !reflect.DeepEqual(methods[j], m) {
+					conflicts = append(conflicts, fmt.Sprintf(`method "%s" of %s conflicts with %s`, name, sources[i], sources[methodSources[name]]))
+				}
+				continue
+			}
+
+			methodIndex[name] = len(methods)
+			methodSources[name] = i
+			methods = append(methods, m)
+		}
+
+		// components
+		components, _ := doc["components"].(map[string]interface{})
+		for kind, v := range components {
+			items, ok := v.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			target, ok := merged[kind].(map[string]interface{})
+			if !ok {
+				target = map[string]interface{}{}
+				merged[kind] = target
+			}
+
+			for name, item := range items {
+				key := kind + "." + name
+				if existing, ok := target[name]; ok {
+					if !reflect.DeepEqual(existing, item) {
+						conflicts = append(conflicts, fmt.Sprintf(`component "%s" of %s conflicts with %s`, key, sources[i], sources[componentSources[key]]))
+					}
+					continue
+				}
+
+				componentSources[key] = i
+				target[name] = item
+			}
+		}
+	}
+
+	if len(conflicts) > 0 {
+		sort.Strings(conflicts)
+		return nil, fmt.Errorf("merge conflicts:\n%s", strings.Join(conflicts, "\n"))
+	}
+
+	result["methods"] = methods
+	if len(merged) > 0 {
+		result["components"] = merged
+	}
+
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(data, '\n'), nil
+}
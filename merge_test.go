@@ -0,0 +1,44 @@
+package rpcdiff
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_mergeSchemas(t *testing.T) {
+	a := []byte(`{"openrpc": "1.2.6", "info": {"title": "gateway", "version": "1.0.0"}, "methods": [
+		{"name": "user.Get", "params": [], "result": {"$ref": "#/components/contentDescriptors/User"}}
+	], "components": {"contentDescriptors": {"User": {"name": "User", "schema": {"type": "object"}}}}}`)
+	b := []byte(`{"openrpc": "1.2.6", "info": {"title": "billing", "version": "2.0.0"}, "methods": [
+		{"name": "user.Get", "params": [], "result": {"$ref": "#/components/contentDescriptors/User"}},
+		{"name": "billing.Pay", "params": [], "result": {"name": "ok", "schema": {"type": "boolean"}}}
+	], "components": {"contentDescriptors": {"User": {"name": "User", "schema": {"type": "object"}}}, "schemas": {"Money": {"type": "integer"}}}}`)
+	c := []byte(`{"openrpc": "1.2.6", "info": {"title": "other", "version": "1.0.0"}, "methods": [
+		{"name": "billing.Pay", "params": [], "result": {"name": "ok", "schema": {"type": "string"}}}
+	], "components": {"schemas": {"Money": {"type": "number"}}}}`)
+
+	data, err := MergeSchemas([]string{"a.json", "b.json"}, [][]byte{a, b})
+	if err != nil {
+		t.Fatalf("merge error: %s", err)
+	}
+
+	doc, err := parseDocument(data)
+	if err != nil {
+		t.Fatalf("parse merged schema error: %s", err)
+	}
+
+	if len(doc.Methods) != 2 || doc.Info.Title != "gateway" || doc.Components.Schemas == nil || doc.Components.ContentDescriptors == nil {
+		t.Errorf("unexpected merged schema: %s", data)
+	}
+
+	_, err = MergeSchemas([]string{"a.json", "b.json", "c.json"}, [][]byte{a, b, c})
+	if err == nil {
+		t.Fatalf("wanted merge conflicts")
+	}
+
+	for _, want := range []string{`method "billing.Pay" of c.json conflicts with b.json`, `component "schemas.Money" of c.json conflicts with b.json`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error doesn't contain %q: %s", want, err)
+		}
+	}
+}
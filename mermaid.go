@@ -0,0 +1,60 @@
+package rpcdiff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// mermaidReport renders impact graph of diff as mermaid flowchart in markdown code block,
+// nodes are colored as in dot report
+func mermaidReport(diff *Diff) string {
+	taxonomy := diff.Options.taxonomy()
+	g := newImpactGraph(diff)
+
+	buf := strings.Builder{}
+	buf.WriteString("```mermaid\nflowchart LR\n")
+	buf.WriteString(diff.commentHeader("%%"))
+
+	// mermaid ids must be plain, so nodes are numbered
+	ids := map[string]string{}
+	classes := map[string][]string{}
+	for i, n := range g.Nodes {
+		id := fmt.Sprintf("n%d", i)
+		ids[n.ID] = id
+
+		label := strings.ReplaceAll(n.Label, `"`, "#quot;")
+		if n.Schema {
+			fmt.Fprintf(&buf, "  %s([\"%s\"])\n", id, label)
+		} else {
+			fmt.Fprintf(&buf, "  %s[\"%s\"]\n", id, label)
+		}
+
+		color := levelColor(taxonomy, n.Criticality)
+		classes[color] = append(classes[color], id)
+	}
+
+	for _, e := range g.Edges {
+		fmt.Fprintf(&buf, "  %s --> %s\n", ids[e[0]], ids[e[1]])
+	}
+
+	colors := make([]string, 0, len(classes))
+	for color := range classes {
+		colors = append(colors, color)
+	}
+	sort.Strings(colors)
+
+	for _, color := range colors {
+		style := "fill:" + color
+		if color == "white" {
+			style += ",stroke-dasharray:5 5"
+		}
+
+		fmt.Fprintf(&buf, "  classDef %s %s\n", color, style)
+		fmt.Fprintf(&buf, "  class %s %s\n", strings.Join(classes[color], ","), color)
+	}
+
+	buf.WriteString("```\n")
+
+	return buf.String()
+}
@@ -0,0 +1,124 @@
+package rpcdiff
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	openrpc "github.com/vmkteam/meta-schema/v2"
+)
+
+// Version of rpcdiff, set on build by -ldflags "-X github.com/vmkteam/rpcdiff.Version=x.y.z"
+var Version = "0.0.0"
+
+// Metadata describes how diff was made, so archived reports are self-describing
+type Metadata struct {
+	Tool      string    `json:"tool"`
+	Version   string    `json:"version"`
+	CreatedAt time.Time `json:"createdAt"`
+	Options   []string  `json:"options,omitempty"` // options different from defaults, e.g. compare-meta, scope=methods
+}
+
+// newMetadata returns metadata of comparison with options
+func newMetadata(options Options) Metadata {
+	return Metadata{
+		Tool:      "rpcdiff",
+		Version:   Version,
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+		Options:   options.describe(),
+	}
+}
+
+// Document identifies compared schema
+type Document struct {
+	Source  string `json:"source,omitempty"` // path or url, empty if schema is given as bytes
+	Title   string `json:"title,omitempty"`
+	Version string `json:"version,omitempty"`
+	Methods int    `json:"methods"` // number of compared methods
+	Digest  string `json:"digest"`  // sha256 of raw schema
+}
+
+// newDocument describes parsed document of raw schema
+func newDocument(raw []byte, doc *openrpc.OpenrpcDocument) Document {
+	d := Document{Methods: len(doc.Methods), Digest: digest(raw)}
+	if doc.Info != nil {
+		d.Title, d.Version = doc.Info.Title, doc.Info.Version
+	}
+
+	return d
+}
+
+// describe returns options different from defaults in flags notation
+func (o Options) describe() []string {
+	var result []string
+	add := func(set bool, format string, args ...interface{}) {
+		if set {
+			result = append(result, fmt.Sprintf(format, args...))
+		}
+	}
+
+	add(o.ShowMeta, "compare-meta")
+	add(o.HideExamples, "hide-examples")
+	add(o.ShowLinks, "links")
+	add(o.ShowReasons, "reasons")
+	add(len(o.Filter.Tags) > 0, "tag=%s", strings.Join(o.Filter.Tags, ","))
+	add(len(o.Filter.Methods) > 0, "method=%s", strings.Join(o.Filter.Methods, ","))
+	add(o.Scope != "" && o.Scope != ScopeAll, "scope=%s", o.Scope)
+	add(len(o.Objects) > 0, "only-object=%s", strings.Join(o.Objects, ","))
+	add(o.Normalize, "normalize")
+	add(o.Canonicalize.enabled(), "canonicalize=%s", o.Canonicalize.String())
+	add(o.RuleHook != "", "rule-hook=%s", o.RuleHook)
+	add(o.CheckDeterminism, "check-determinism")
+	add(o.FullValues, "full-values")
+	add(o.Evidence, "evidence")
+	add(o.Pin.Old != "", "old-sha256=%s", o.Pin.Old)
+	add(o.Pin.New != "", "new-sha256=%s", o.Pin.New)
+	add(o.Policy != nil, "policy")
+	add(o.Budget != nil, "budget")
+	add(len(o.Taxonomy) > 0, "taxonomy")
+	add(len(o.Rules) > 0, "rules=%d", len(o.Rules))
+	add(len(o.Experimental) > 0, "experimental=%s", strings.Join(o.Experimental, ","))
+	add(len(o.IgnoreServers) > 0, "ignore-servers=%s", strings.Join(o.IgnoreServers, ","))
+	add(o.IgnoreMethodServers, "ignore-method-servers")
+	add(len(o.Owners) > 0, "owners=%d", len(o.Owners))
+	add(len(o.Usage) > 0, "usage=%d", len(o.Usage))
+
+	return result
+}
+
+// Header renders metadata and documents of diff as report Header
+func (d *Diff) Header() string {
+	m := d.Metadata
+	buf := strings.Builder{}
+	fmt.Fprintf(&buf, "%s %s, %s\n", m.Tool, m.Version, m.CreatedAt.Format(time.RFC3339))
+	fmt.Fprintf(&buf, "Old: %s\n", d.Old.String())
+	fmt.Fprintf(&buf, "New: %s\n", d.New.String())
+	if len(m.Options) > 0 {
+		fmt.Fprintf(&buf, "Options: %s\n", strings.Join(m.Options, ", "))
+	}
+
+	return buf.String()
+}
+
+// commentHeader renders report header as comment lines starting with prefix, e.g. // for dot
+func (d *Diff) commentHeader(prefix string) string {
+	buf := strings.Builder{}
+	for _, line := range strings.Split(strings.TrimSuffix(d.Header(), "\n"), "\n") {
+		buf.WriteString(prefix + " " + line + "\n")
+	}
+
+	return buf.String()
+}
+
+func (d Document) String() string {
+	source := d.Source
+	if source == "" {
+		source = "-"
+	}
+
+	if info := strings.TrimSpace(d.Title + " " + d.Version); info != "" {
+		return fmt.Sprintf("%s (%s)", source, info)
+	}
+
+	return source
+}
@@ -0,0 +1,64 @@
+package rpcdiff
+
+import (
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewDiff_metadata(t *testing.T) {
+	diff, err := NewDiff("testdata/openrpc_old.json", "testdata/openrpc_new.json", Options{ShowMeta: true, Scope: ScopeMethods})
+	if err != nil {
+		t.Fatalf("new diff error: %s", err)
+	}
+
+	m := diff.Metadata
+	if m.Tool != "rpcdiff" || m.Version != Version || m.CreatedAt.IsZero() {
+		t.Errorf("metadata = %v %v %v, want rpcdiff %v and timestamp", m.Tool, m.Version, m.CreatedAt, Version)
+	}
+
+	newJSON, err := ioutil.ReadFile("testdata/openrpc_new.json")
+	if err != nil {
+		t.Fatalf("read error: %s", err)
+	}
+
+	want := Document{Source: "testdata/openrpc_new.json", Title: "test_old", Version: "v0.0.0-b35e0598ad2f7ebd89ac036e29113f0a", Methods: 11, Digest: digest(newJSON)}
+	if diff.New != want {
+		t.Errorf("new document = %#v, want %#v", diff.New, want)
+	}
+
+	if diff.Old.Source != "testdata/openrpc_old.json" {
+		t.Errorf("old document source = %v, want testdata/openrpc_old.json", diff.Old.Source)
+	}
+
+	out, err := RenderDiff(diff, FormatText, "")
+	if err != nil {
+		t.Fatalf("RenderDiff() error: %s", err)
+	}
+
+	header := "Old: testdata/openrpc_old.json (test_old v0.0.0-b35e0598ad2f7ebd89ac036e29113f0f)\nNew: testdata/openrpc_new.json (test_old v0.0.0-b35e0598ad2f7ebd89ac036e29113f0a)\nOptions: compare-meta, scope=methods\n\nNew schema has"
+	if !strings.HasPrefix(out, "rpcdiff ") || !strings.Contains(out, header) {
+		t.Errorf("RenderDiff() = %v, wanted metadata header", out)
+	}
+}
+
+func TestOptions_describe(t *testing.T) {
+	tests := []struct {
+		name    string
+		options Options
+		want    []string
+	}{
+		{"defaults", Options{Scope: ScopeAll}, nil},
+		{"flags", Options{HideExamples: true, Filter: Filter{Tags: []string{"a", "b"}}, Canonicalize: Canonicalize{TrimSpace: true}}, []string{"hide-examples", "tag=a,b", "canonicalize=trim-space"}},
+		{"config", Options{Rules: Rules{{}, {}}, IgnoreServers: []string{"staging"}}, []string{"rules=2", "ignore-servers=staging"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.options.describe(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("describe() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
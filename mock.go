@@ -0,0 +1,142 @@
+package rpcdiff
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+
+	openrpc "github.com/vmkteam/meta-schema/v2"
+)
+
+// json-rpc error codes
+const (
+	codeParseError     = -32700
+	codeMethodNotFound = -32601
+)
+
+type mockRequest struct {
+	ID     *json.RawMessage `json:"id"`
+	Method string           `json:"method"`
+}
+
+type mockResponse struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id"`
+	Result  interface{}      `json:"result,omitempty"`
+	Error   *mockError       `json:"error,omitempty"`
+}
+
+type mockError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// mockHandler serves json-rpc responses with result examples of methods or values synthesized from result schemas
+type mockHandler struct {
+	doc *openrpc.OpenrpcDocument
+}
+
+// NewMockHandler returns handler serving mock json-rpc responses of schema
+func NewMockHandler(data []byte) (http.Handler, error) {
+	doc, err := parseDocument(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mockHandler{doc: doc}, nil
+}
+
+func (h *mockHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var result interface{}
+	if body = bytes.TrimSpace(body); len(body) > 0 && body[0] == '[' {
+		var reqs []mockRequest
+		if err := json.Unmarshal(body, &reqs); err != nil {
+			result = parseErrorResponse()
+		} else {
+			var resps []mockResponse
+			for _, req := range reqs {
+				if req.ID != nil {
+					resps = append(resps, h.response(req))
+				}
+			}
+			if len(resps) > 0 {
+				result = resps
+			}
+		}
+	} else {
+		var req mockRequest
+		if err := json.Unmarshal(body, &req); err != nil {
+			result = parseErrorResponse()
+		} else if req.ID != nil {
+			result = h.response(req)
+		}
+	}
+
+	// notifications have no response
+	if result == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// response returns result of method
+func (h *mockHandler) response(req mockRequest) mockResponse {
+	resp := mockResponse{JSONRPC: "2.0", ID: req.ID}
+
+	method := findMethod(h.doc, req.Method)
+	if method == nil {
+		resp.Error = &mockError{Code: codeMethodNotFound, Message: "Method not found"}
+		return resp
+	}
+
+	resp.Result = mockResult(method, h.doc)
+	if resp.Result == nil {
+		resp.Result = json.RawMessage("null")
+	}
+
+	return resp
+}
+
+// mockResult returns value of the first result example or synthesizes it from result schema
+func mockResult(method *openrpc.MethodObject, doc *openrpc.OpenrpcDocument) interface{} {
+	for _, example := range method.Examples {
+		if example.ExamplePairingObject != nil && example.Result != nil && example.Result.ExampleObject != nil {
+			return example.Result.Value
+		}
+	}
+
+	if method.Result == nil {
+		return nil
+	}
+
+	cd := method.Result.ContentDescriptorObject
+	if method.Result.ReferenceObject != nil {
+		cd = resolveContentDescriptor(method.Result.ReferenceObject.Ref, doc)
+	}
+
+	if cd == nil {
+		return nil
+	}
+
+	return synthesizeValue(getSchemaObject(cd.Schema), doc)
+}
+
+func parseErrorResponse() mockResponse {
+	null := json.RawMessage("null")
+	return mockResponse{JSONRPC: "2.0", ID: &null, Error: &mockError{Code: codeParseError, Message: "Parse error"}}
+}
@@ -0,0 +1,47 @@
+package rpcdiff
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func Test_mockHandler(t *testing.T) {
+	h, err := NewMockHandler([]byte(`{
+		"openrpc": "1.2.6",
+		"info": {"title": "test", "version": "1.0.0"},
+		"methods": [
+			{"name": "user.Get", "params": [], "result": {"name": "user", "schema": {"type": "object", "required": ["id"], "properties": {"id": {"type": "integer"}}}}},
+			{"name": "user.Count", "params": [], "result": {"name": "count", "schema": {"type": "integer"}}, "examples": [{"name": "ten", "params": [], "result": {"name": "count", "value": 10}}]}
+		]
+	}`))
+	if err != nil {
+		t.Fatalf("new mock handler error: %s", err)
+	}
+
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{name: "synthesized", body: `{"jsonrpc": "2.0", "id": 1, "method": "user.Get"}`, want: `{"jsonrpc":"2.0","id":1,"result":{"id":1}}`},
+		{name: "example", body: `{"jsonrpc": "2.0", "id": "a", "method": "user.Count"}`, want: `{"jsonrpc":"2.0","id":"a","result":10}`},
+		{name: "not found", body: `{"jsonrpc": "2.0", "id": 2, "method": "user.Delete"}`, want: `{"jsonrpc":"2.0","id":2,"error":{"code":-32601,"message":"Method not found"}}`},
+		{name: "batch", body: `[{"jsonrpc": "2.0", "id": 1, "method": "user.Count"}, {"jsonrpc": "2.0", "method": "user.Get"}]`, want: `[{"jsonrpc":"2.0","id":1,"result":10}]`},
+		{name: "notification", body: `{"jsonrpc": "2.0", "method": "user.Get"}`, want: ``},
+		{name: "parse error", body: `{`, want: `{"jsonrpc":"2.0","id":null,"error":{"code":-32700,"message":"Parse error"}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body)))
+
+			body, _ := ioutil.ReadAll(w.Body)
+			if got := strings.TrimSpace(string(body)); got != tt.want {
+				t.Errorf("response = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,312 @@
+package rpcdiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// Mutation is a systematic change of schema with changes expected from comparison:
+// the change itself and result type changes of methods which results reference mutated components schema
+type Mutation struct {
+	Name     string
+	Expected []Change
+	apply    func(doc map[string]interface{})
+}
+
+// expectedChange is a change of rule test fixture, see changeKey
+type expectedChange struct {
+	Path        []string         `json:"path"`
+	Type        ChangeType       `json:"type"`
+	Object      ChangeObject     `json:"object"`
+	Criticality CriticalityLevel `json:"criticality"`
+}
+
+// unsafeNameChars are replaced in fixture names
+var unsafeNameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// WriteMutations writes rule test fixture of every mutation of schema to dir and returns fixture paths
+func WriteMutations(source, dir string) ([]string, error) {
+	data, err := ReadFileOrURL(source)
+	if err != nil {
+		return nil, fmt.Errorf("read schema error: %w", err)
+	}
+
+	parsed, err := parseDocument(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse schema error: %w", err)
+	}
+
+	if data, err = CanonicalJSON(data); err != nil {
+		return nil, err
+	}
+
+	doc, err := decodeObject(data)
+	if err != nil {
+		return nil, err
+	}
+
+	deps := map[string]map[string]bool{}
+	collectDependencies(data, deps)
+
+	var paths []string
+	for _, m := range schemaMutations(doc, schemaDirections(parsed), resultMethods(doc, deps)) {
+		// every mutation is applied to its own copy of schema
+		mutated, err := decodeObject(data)
+		if err != nil {
+			return nil, err
+		}
+		m.apply(mutated)
+
+		fixture := filepath.Join(dir, unsafeNameChars.ReplaceAllString(m.Name, "_"))
+		if err := writeMutation(fixture, data, mutated, m.Expected); err != nil {
+			return nil, fmt.Errorf("write mutation %s error: %w", m.Name, err)
+		}
+
+		paths = append(paths, fixture)
+	}
+
+	return paths, nil
+}
+
+// writeMutation writes old.json, new.json and expected.json of fixture
+func writeMutation(fixture string, old []byte, new interface{}, expected []Change) error {
+	newJSON, err := json.MarshalIndent(new, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	fixtureChanges := make([]expectedChange, 0, len(expected))
+	for _, c := range expected {
+		fixtureChanges = append(fixtureChanges, expectedChange{Path: c.Path, Type: c.Type, Object: c.Object, Criticality: c.Criticality})
+	}
+
+	expectedJSON, err := json.MarshalIndent(fixtureChanges, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(fixture, 0755); err != nil {
+		return err
+	}
+
+	files := map[string][]byte{"old.json": old, "new.json": append(newJSON, '\n'), "expected.json": append(expectedJSON, '\n')}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(fixture, name), content, 0644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// schemaMutations returns mutations of methods, params and components schemas of document.
+// Expected criticality of components schemas changes depends on directions of schemas, results are methods by schemas
+// their results reference, see resultMethods.
+func schemaMutations(doc map[string]interface{}, directions map[string]schemaDirection, results map[string][]string) []Mutation {
+	var result []Mutation
+
+	methods, _ := doc["methods"].([]interface{})
+	for i, v := range methods {
+		method, _ := v.(map[string]interface{})
+		name, _ := method["name"].(string)
+		if name == "" {
+			continue
+		}
+
+		i := i
+		result = append(result, Mutation{
+			Name:     "remove-method-" + name,
+			Expected: []Change{{Path: []string{"methods", name}, Type: Removed, Object: Method, Criticality: Breaking}},
+			apply: func(doc map[string]interface{}) {
+				methods := doc["methods"].([]interface{})
+				doc["methods"] = append(methods[:i:i], methods[i+1:]...)
+			},
+		})
+
+		params, _ := method["params"].([]interface{})
+		for j, v := range params {
+			param, _ := v.(map[string]interface{})
+			paramName, _ := param["name"].(string)
+			if paramName == "" {
+				continue
+			}
+
+			j, path := j, []string{"methods", name, "params", paramName}
+			if required, _ := param["required"].(bool); !required {
+				result = append(result, Mutation{
+					Name:     fmt.Sprintf("require-param-%s-%s", name, paramName),
+					Expected: []Change{{Path: appendPath(path, "required"), Type: Changed, Object: MethodParam, Criticality: Breaking}},
+					apply: func(doc map[string]interface{}) {
+						mutatedParam(doc, i, j)["required"] = true
+					},
+				})
+			}
+
+			if schema, _ := param["schema"].(map[string]interface{}); mutableType(schema) {
+				result = append(result, Mutation{
+					Name:     fmt.Sprintf("change-param-type-%s-%s", name, paramName),
+					Expected: []Change{{Path: appendPath(path, "schema", "type"), Type: Changed, Object: MethodParamType, Criticality: Breaking}},
+					apply: func(doc map[string]interface{}) {
+						changeType(mutatedParam(doc, i, j)["schema"].(map[string]interface{}))
+					},
+				})
+			}
+		}
+	}
+
+	components, _ := doc["components"].(map[string]interface{})
+	schemas, _ := components["schemas"].(map[string]interface{})
+	for _, name := range sortedKeys(schemas) {
+		schema, _ := schemas[name].(map[string]interface{})
+		path := []string{"components", "schemas", name}
+
+		name := name
+
+		// dropped enum values narrow schema, that breaks inputs only
+		enumLevel := typeChangeLevel(typeNarrowed, directions[name])
+		enumResults := resultChanges(results[name], name, typeChangeLevel(typeNarrowed, directionOutput))
+		typeResults := resultChanges(results[name], name, typeChangeLevel(typeIncompatible, directionOutput))
+
+		if len(enumValues(schema)) > 1 {
+			result = append(result, Mutation{
+				Name:     "drop-enum-value-" + name,
+				Expected: append([]Change{{Path: appendPath(path, "enum"), Type: Changed, Object: ComponentsSchema, Criticality: enumLevel}}, enumResults...),
+				apply: func(doc map[string]interface{}) {
+					dropEnumValue(mutatedSchema(doc, name))
+				},
+			})
+		}
+
+		properties, _ := schema["properties"].(map[string]interface{})
+		for _, propName := range sortedKeys(properties) {
+			prop, _ := properties[propName].(map[string]interface{})
+			propPath, propName := appendPath(path, "properties", propName), propName
+
+			if len(enumValues(prop)) > 1 {
+				result = append(result, Mutation{
+					Name:     fmt.Sprintf("drop-enum-value-%s-%s", name, propName),
+					Expected: append([]Change{{Path: appendPath(propPath, "enum"), Type: Changed, Object: ComponentsSchemaProperty, Criticality: enumLevel}}, enumResults...),
+					apply: func(doc map[string]interface{}) {
+						dropEnumValue(mutatedProperty(doc, name, propName))
+					},
+				})
+			}
+
+			if mutableType(prop) {
+				result = append(result, Mutation{
+					Name:     fmt.Sprintf("change-property-type-%s-%s", name, propName),
+					Expected: append([]Change{{Path: appendPath(propPath, "type"), Type: Changed, Object: ComponentsSchemaPropertyType, Criticality: Breaking}}, typeResults...),
+					apply: func(doc map[string]interface{}) {
+						changeType(mutatedProperty(doc, name, propName))
+					},
+				})
+			}
+		}
+	}
+
+	return result
+}
+
+// resultMethods returns names of methods by names of components schemas their results reference
+// directly, through content descriptors or through other schemas
+func resultMethods(doc map[string]interface{}, deps map[string]map[string]bool) map[string][]string {
+	components, _ := doc["components"].(map[string]interface{})
+	descriptors, _ := components["contentDescriptors"].(map[string]interface{})
+
+	result := map[string][]string{}
+	methods, _ := doc["methods"].([]interface{})
+	for _, v := range methods {
+		method, _ := v.(map[string]interface{})
+		name, _ := method["name"].(string)
+		if name == "" {
+			continue
+		}
+
+		for _, schema := range reachableSchemas(method["result"], descriptors, deps) {
+			result[schema] = append(result[schema], name)
+		}
+	}
+
+	return result
+}
+
+// resultChanges returns result type changes of methods attributed to changes of components schema, see attributeResults
+func resultChanges(methods []string, schema string, level CriticalityLevel) []Change {
+	var result []Change
+	for _, method := range methods {
+		result = append(result, Change{
+			Path:        []string{"methods", method, "result", "schemas", schema},
+			Type:        Changed,
+			Object:      MethodResultType,
+			Criticality: level,
+		})
+	}
+
+	return result
+}
+
+// decodeObject decodes json object keeping numbers as is
+func decodeObject(data []byte) (map[string]interface{}, error) {
+	v, err := decodeJSON(data)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("schema is not an object")
+	}
+
+	return doc, nil
+}
+
+// mutatedParam returns param j of method i
+func mutatedParam(doc map[string]interface{}, i, j int) map[string]interface{} {
+	method := doc["methods"].([]interface{})[i].(map[string]interface{})
+	return method["params"].([]interface{})[j].(map[string]interface{})
+}
+
+// mutatedSchema returns components schema by name
+func mutatedSchema(doc map[string]interface{}, name string) map[string]interface{} {
+	return doc["components"].(map[string]interface{})["schemas"].(map[string]interface{})[name].(map[string]interface{})
+}
+
+// mutatedProperty returns property of components schema
+func mutatedProperty(doc map[string]interface{}, name, prop string) map[string]interface{} {
+	return mutatedSchema(doc, name)["properties"].(map[string]interface{})[prop].(map[string]interface{})
+}
+
+// mutableType checks that schema has single type which can be changed incompatibly
+func mutableType(schema map[string]interface{}) bool {
+	if _, ok := schema["$ref"]; ok {
+		return false
+	}
+
+	typ, _ := schema["type"].(string)
+	return typ != ""
+}
+
+// changeType replaces type of schema with incompatible one
+func changeType(schema map[string]interface{}) {
+	if schema["type"] == "boolean" {
+		schema["type"] = "string"
+	} else {
+		schema["type"] = "boolean"
+	}
+}
+
+// enumValues returns enum of schema
+func enumValues(schema map[string]interface{}) []interface{} {
+	enum, _ := schema["enum"].([]interface{})
+	return enum
+}
+
+// dropEnumValue removes the last value of enum
+func dropEnumValue(schema map[string]interface{}) {
+	enum := enumValues(schema)
+	schema["enum"] = enum[:len(enum)-1]
+}
@@ -0,0 +1,77 @@
+package rpcdiff
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteMutations(t *testing.T) {
+	dir := t.TempDir()
+	paths, err := WriteMutations("testdata/openrpc_old.json", dir)
+	if err != nil {
+		t.Fatalf("WriteMutations() error: %s", err)
+	}
+
+	names := map[string]bool{}
+	for _, p := range paths {
+		names[filepath.Base(p)] = true
+	}
+
+	for _, name := range []string{"remove-method-check.RemovedMethod", "require-param-check.AddRequiredParam-param1", "change-param-type-check.ChangeTypeParam-param1"} {
+		if !names[name] {
+			t.Errorf("WriteMutations() = %v, want %v", paths, name)
+		}
+	}
+
+	tests, err := RunRuleTests(dir, Options{})
+	if err != nil {
+		t.Fatalf("RunRuleTests() error: %s", err)
+	}
+
+	if len(tests) != len(paths) {
+		t.Fatalf("RunRuleTests() = %v tests, want %v", len(tests), len(paths))
+	}
+
+	for _, rt := range tests {
+		if !rt.Passed() {
+			t.Errorf("mutation %s failed: %s", rt.Name, RuleTestsReport([]RuleTest{rt}))
+		}
+	}
+}
+
+func TestWriteMutations_resultEnum(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "openrpc.json")
+	if err := ioutil.WriteFile(source, []byte(`{"openrpc": "1.2.6", "info": {"title": "api", "version": "1"},
+		"methods": [{"name": "user.Get", "params": [], "result": {"$ref": "#/components/contentDescriptors/User"}}],
+		"components": {
+			"contentDescriptors": {"User": {"name": "User", "schema": {"$ref": "#/components/schemas/User"}}},
+			"schemas": {
+				"User": {"type": "object", "properties": {"status": {"$ref": "#/components/schemas/Status"}}},
+				"Status": {"type": "string", "enum": ["active", "blocked"]}
+			}
+		}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	paths, err := WriteMutations(source, filepath.Join(dir, "mutations"))
+	if err != nil {
+		t.Fatalf("WriteMutations() error: %s", err)
+	}
+
+	tests, err := RunRuleTests(filepath.Join(dir, "mutations"), Options{})
+	if err != nil {
+		t.Fatalf("RunRuleTests() error: %s", err)
+	}
+
+	if len(tests) != len(paths) {
+		t.Fatalf("RunRuleTests() = %v tests, want %v", len(tests), len(paths))
+	}
+
+	for _, rt := range tests {
+		if !rt.Passed() {
+			t.Errorf("mutation %s failed: %s", rt.Name, RuleTestsReport([]RuleTest{rt}))
+		}
+	}
+}
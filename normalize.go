@@ -0,0 +1,118 @@
+package rpcdiff
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+)
+
+// verbatimKeys hold user data which is kept as is
+var verbatimKeys = map[string]bool{
+	"default":  true,
+	"const":    true,
+	"enum":     true,
+	"examples": true,
+	"value":    true,
+	"data":     true,
+}
+
+// mapKeys hold maps of user defined names, their entries are never dropped
+var mapKeys = map[string]bool{
+	"properties":            true,
+	"patternProperties":     true,
+	"definitions":           true,
+	"schemas":               true,
+	"contentDescriptors":    true,
+	"examplePairingObjects": true,
+	"exampleObjects":        true,
+	"errors":                true,
+	"links":                 true,
+	"tags":                  true,
+}
+
+// requiredKeys are required by openrpc spec even if empty
+var requiredKeys = map[string]bool{
+	"methods": true,
+	"params":  true,
+	"result":  true,
+	"schema":  true,
+}
+
+// NormalizeSchema sorts methods by name, drops empty optional fields and formats json with sorted keys.
+// Order of params is kept as it matters for by-position calls.
+func NormalizeSchema(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var doc map[string]interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	if methods, ok := doc["methods"].([]interface{}); ok {
+		sort.SliceStable(methods, func(i, j int) bool {
+			return methodName(methods[i]) < methodName(methods[j])
+		})
+	}
+
+	result, err := json.MarshalIndent(dropEmpty(doc), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(result, '\n'), nil
+}
+
+// methodName returns name of raw method object
+func methodName(v interface{}) string {
+	m, _ := v.(map[string]interface{})
+	name, _ := m["name"].(string)
+
+	return name
+}
+
+// dropEmpty removes null, empty string, empty array and empty object fields recursively
+func dropEmpty(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, item := range val {
+			if verbatimKeys[k] {
+				continue
+			}
+
+			if m, ok := item.(map[string]interface{}); ok && mapKeys[k] {
+				for name, entry := range m {
+					m[name] = dropEmpty(entry)
+				}
+			} else {
+				val[k] = dropEmpty(item)
+			}
+
+			if !requiredKeys[k] && isEmptyValue(val[k]) {
+				delete(val, k)
+			}
+		}
+	case []interface{}:
+		for i := range val {
+			val[i] = dropEmpty(val[i])
+		}
+	}
+
+	return v
+}
+
+// isEmptyValue checks for null, empty string, empty array or empty object
+func isEmptyValue(v interface{}) bool {
+	switch val := v.(type) {
+	case nil:
+		return true
+	case string:
+		return val == ""
+	case []interface{}:
+		return len(val) == 0
+	case map[string]interface{}:
+		return len(val) == 0
+	}
+
+	return false
+}
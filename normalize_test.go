@@ -0,0 +1,69 @@
+package rpcdiff
+
+import "testing"
+
+func Test_normalizeSchema(t *testing.T) {
+	data := []byte(`{
+		"openrpc": "1.2.6",
+		"info": {"version": "1.0.0", "title": "test", "description": ""},
+		"methods": [
+			{"name": "b.Get", "params": [{"name": "z", "schema": {}}, {"name": "a", "schema": {"type": "string", "default": ""}}], "result": {"name": "r", "schema": {"type": "null"}}, "tags": [], "summary": null},
+			{"name": "a.Get", "params": [], "result": {"name": "r", "schema": {"type": "object", "properties": {"any": {}}, "required": []}}}
+		]
+	}`)
+
+	want := `{
+  "info": {
+    "title": "test",
+    "version": "1.0.0"
+  },
+  "methods": [
+    {
+      "name": "a.Get",
+      "params": [],
+      "result": {
+        "name": "r",
+        "schema": {
+          "properties": {
+            "any": {}
+          },
+          "type": "object"
+        }
+      }
+    },
+    {
+      "name": "b.Get",
+      "params": [
+        {
+          "name": "z",
+          "schema": {}
+        },
+        {
+          "name": "a",
+          "schema": {
+            "default": "",
+            "type": "string"
+          }
+        }
+      ],
+      "result": {
+        "name": "r",
+        "schema": {
+          "type": "null"
+        }
+      }
+    }
+  ],
+  "openrpc": "1.2.6"
+}
+`
+
+	got, err := NormalizeSchema(data)
+	if err != nil {
+		t.Fatalf("normalize error: %s", err)
+	}
+
+	if string(got) != want {
+		t.Errorf("NormalizeSchema() = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,194 @@
+package rpcdiff
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+)
+
+// ownerExtensions are extensions of methods and components with responsible team, the first one found is used
+var ownerExtensions = []string{"x-owner", "x-team"}
+
+// noOwner is a title of changes without owner in grouped report
+const noOwner = "(no owner)"
+
+// OwnerRule maps methods or components matching glob pattern to owner, e.g. "billing.* @billing-team"
+type OwnerRule struct {
+	Pattern string
+	Owner   string
+}
+
+// Owners is a CODEOWNERS-style mapping, the last matching rule wins
+type Owners []OwnerRule
+
+// LoadOwners reads mapping file with pattern and owners per line, # starts comment
+func LoadOwners(filename string) (Owners, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("read owners error: %w", err)
+	}
+	defer f.Close()
+
+	var result Owners
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i != -1 {
+			line = line[:i]
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("owners line %d: owner of %q is missing", n, fields[0])
+		}
+
+		if _, err := path.Match(fields[0], ""); err != nil {
+			return nil, fmt.Errorf("owners line %d: %w", n, err)
+		}
+
+		result = append(result, OwnerRule{Pattern: fields[0], Owner: strings.Join(fields[1:], " ")})
+	}
+
+	return result, scanner.Err()
+}
+
+// Set loads owners from mapping file, it implements pflag.Value
+func (o *Owners) Set(filename string) error {
+	owners, err := LoadOwners(filename)
+	if err != nil {
+		return err
+	}
+
+	*o = owners
+	return nil
+}
+
+func (o *Owners) String() string {
+	return ""
+}
+
+func (o *Owners) Type() string {
+	return "path"
+}
+
+// owner returns owner of the last rule matching subject
+func (o Owners) owner(subject string) string {
+	for i := len(o) - 1; i >= 0; i-- {
+		if ok, _ := path.Match(o[i].Pattern, subject); ok {
+			return o[i].Owner
+		}
+	}
+
+	return ""
+}
+
+// schemaOwners returns owners from extensions of methods and components by subject of change
+func schemaOwners(data []byte) map[string]string {
+	result := map[string]string{}
+	doc, err := decodeObject(data)
+	if err != nil {
+		return result
+	}
+
+	methods, _ := doc["methods"].([]interface{})
+	for _, m := range methods {
+		method, _ := m.(map[string]interface{})
+		if name, ok := method["name"].(string); ok {
+			if owner := extensionOwner(method); owner != "" {
+				result[name] = owner
+			}
+		}
+	}
+
+	components, _ := doc["components"].(map[string]interface{})
+	for kind, objects := range components {
+		objects, _ := objects.(map[string]interface{})
+		for name, object := range objects {
+			if owner := extensionOwner(object); owner != "" {
+				result[strings.Join([]string{"components", kind, name}, ".")] = owner
+			}
+		}
+	}
+
+	return result
+}
+
+// extensionOwner returns owner from extensions of object
+func extensionOwner(v interface{}) string {
+	object, _ := v.(map[string]interface{})
+	for _, ext := range ownerExtensions {
+		if owner, ok := object[ext].(string); ok && owner != "" {
+			return owner
+		}
+	}
+
+	return ""
+}
+
+// assignOwners sets owners of changes from extensions of new schema, old schema for removed objects, or mapping
+func assignOwners(changes []Change, oldJSON, newJSON []byte, owners Owners) {
+	oldOwners, newOwners := schemaOwners(oldJSON), schemaOwners(newJSON)
+	for i, c := range changes {
+		subject := changeSubject(c)
+		switch {
+		case newOwners[subject] != "":
+			changes[i].Owner = newOwners[subject]
+		case oldOwners[subject] != "":
+			changes[i].Owner = oldOwners[subject]
+		default:
+			changes[i].Owner = owners.owner(subject)
+		}
+	}
+}
+
+// OwnerReport renders changes grouped by owner, owners are sorted and changes without owner are the last
+func OwnerReport(diff *Diff) string {
+	if len(diff.Changes) == 0 {
+		return "There is no difference between schemas\n"
+	}
+
+	taxonomy := diff.Options.taxonomy()
+	groups := map[string][]Change{}
+	for _, c := range diff.Changes {
+		groups[c.Owner] = append(groups[c.Owner], c)
+	}
+
+	owners := make([]string, 0, len(groups))
+	for owner := range groups {
+		if owner != "" {
+			owners = append(owners, owner)
+		}
+	}
+	sort.Strings(owners)
+	if len(groups[""]) > 0 {
+		owners = append(owners, "")
+	}
+
+	buf := strings.Builder{}
+	fmt.Fprintf(&buf, "New schema has %s change(s), score %d\n", taxonomy.title(diff.Criticality), diff.Score)
+	for _, owner := range owners {
+		changes := groups[owner]
+		sort.SliceStable(changes, func(i, j int) bool {
+			return taxonomy.rank(changes[i].Criticality) < taxonomy.rank(changes[j].Criticality)
+		})
+
+		title := owner
+		if title == "" {
+			title = noOwner
+		}
+
+		fmt.Fprintf(&buf, "%s (%d):\n", title, len(changes))
+		for _, c := range changes {
+			fmt.Fprintf(&buf, "- [%s] %s\n", taxonomy.title(c.Criticality), c.String())
+		}
+	}
+
+	return buf.String()
+}
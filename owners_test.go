@@ -0,0 +1,81 @@
+package rpcdiff
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadOwners(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "OWNERS")
+	data := "# api owners\n\nbilling.* @billing\nbilling.Refund* @payments @billing # refunds\n"
+	if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatalf("write error: %s", err)
+	}
+
+	owners, err := LoadOwners(path)
+	if err != nil {
+		t.Fatalf("LoadOwners() error: %s", err)
+	}
+
+	tests := []struct {
+		subject string
+		want    string
+	}{
+		{subject: "billing.Get", want: "@billing"},
+		{subject: "billing.RefundCreate", want: "@payments @billing"},
+		{subject: "users.Get", want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := owners.owner(tt.subject); got != tt.want {
+			t.Errorf("owner(%v) = %v, want %v", tt.subject, got, tt.want)
+		}
+	}
+
+	if err := ioutil.WriteFile(path, []byte("billing.*\n"), 0644); err != nil {
+		t.Fatalf("write error: %s", err)
+	}
+
+	if _, err := LoadOwners(path); err == nil {
+		t.Errorf("LoadOwners() error is nil for pattern without owner")
+	}
+}
+
+func Test_assignOwners(t *testing.T) {
+	old := []byte(`{"openrpc":"1.2.6","info":{"title":"api","version":"1"},"methods":[
+		{"name":"billing.Get","x-owner":"@billing","params":[],"result":{"name":"r","schema":{"type":"string"}}},
+		{"name":"billing.Old","x-team":"@legacy","params":[],"result":{"name":"r","schema":{"type":"string"}}}],
+		"components":{"schemas":{"Invoice":{"type":"object","x-owner":"@invoices"}}}}`)
+	new := []byte(`{"openrpc":"1.2.6","info":{"title":"api","version":"1"},"methods":[
+		{"name":"billing.Get","x-owner":"@billing","params":[],"result":{"name":"r","schema":{"type":"integer"}}},
+		{"name":"billing.New","params":[],"result":{"name":"r","schema":{"type":"string"}}}],
+		"components":{"schemas":{"Invoice":{"type":"string","x-owner":"@invoices"}}}}`)
+
+	diff, err := NewDiffBytes(old, new, Options{Owners: Owners{{Pattern: "billing.*", Owner: "@api"}}})
+	if err != nil {
+		t.Fatalf("NewDiffBytes() error: %s", err)
+	}
+
+	want := map[string]string{
+		"billing.Get":                "@billing",
+		"billing.Old":                "@legacy",
+		"billing.New":                "@api",
+		"components.schemas.Invoice": "@invoices",
+	}
+
+	for _, c := range diff.Changes {
+		if subject := changeSubject(c); c.Owner != want[subject] {
+			t.Errorf("owner of %v = %v, want %v", c, c.Owner, want[subject])
+		}
+	}
+
+	wantReport := "New schema has breaking change(s), score 100\n" +
+		"@api (1):\n- [non breaking] Added method \"billing.New\"\n" +
+		"@billing (1):\n- [breaking] Changed \"type\" at result of method \"billing.Get\" from \"string\" to \"integer\"\n" +
+		"@invoices (1):\n- [breaking] Changed type of schema \"Invoice\" from \"object\" to \"string\"\n" +
+		"@legacy (1):\n- [breaking] Removed method \"billing.Old\"\n"
+	if report := OwnerReport(diff); report != wantReport {
+		t.Errorf("OwnerReport() = %v, want %v", report, wantReport)
+	}
+}
@@ -0,0 +1,72 @@
+package rpcdiff
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Pin is expected sha256 digests of raw compared schemas, empty digest is not checked
+type Pin struct {
+	Old string
+	New string
+}
+
+// digest returns hex sha256 of data
+func digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// verify checks that schemas match pinned digests, digests may have sha256: prefix
+func (p Pin) verify(oldJSON, newJSON []byte) error {
+	for _, pin := range []struct {
+		name     string
+		expected string
+		data     []byte
+	}{{"old", p.Old, oldJSON}, {"new", p.New, newJSON}} {
+		expected := strings.ToLower(strings.TrimPrefix(pin.expected, "sha256:"))
+		if expected == "" {
+			continue
+		}
+
+		if actual := digest(pin.data); actual != expected {
+			return fmt.Errorf("%s schema sha256 %s doesn't match pinned %s", pin.name, actual, expected)
+		}
+	}
+
+	return nil
+}
+
+// recorded pins old schema to digest recorded in history store, it must match explicitly pinned digest
+func (p *Pin) recorded(sum string) error {
+	if p.Old != "" && strings.ToLower(strings.TrimPrefix(p.Old, "sha256:")) != sum {
+		return fmt.Errorf("pinned old schema sha256 %s differs from recorded %s", p.Old, sum)
+	}
+	p.Old = sum
+
+	return nil
+}
+
+// PinRecordedDigest pins old schema to digest of service version recorded in history store of config
+func PinRecordedDigest(cfg *Config, service, version string, pin *Pin) error {
+	store, err := OpenConfigStore(cfg)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	records, err := store.List(service)
+	if err != nil {
+		return err
+	}
+
+	r, err := sinceRecord(records, time.Time{}, version)
+	if err != nil {
+		return fmt.Errorf("%s: %w", service, err)
+	}
+
+	return pin.recorded(r.Digest)
+}
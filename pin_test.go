@@ -0,0 +1,69 @@
+package rpcdiff
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestNewDiffBytes_pin(t *testing.T) {
+	oldJSON, err := ioutil.ReadFile("testdata/openrpc_old.json")
+	if err != nil {
+		t.Fatalf("read error: %s", err)
+	}
+
+	newJSON, err := ioutil.ReadFile("testdata/openrpc_new.json")
+	if err != nil {
+		t.Fatalf("read error: %s", err)
+	}
+
+	oldDigest, newDigest := digest(oldJSON), digest(newJSON)
+
+	tests := []struct {
+		name    string
+		pin     Pin
+		wantErr string
+	}{
+		{"no pin", Pin{}, ""},
+		{"pinned", Pin{Old: oldDigest, New: "sha256:" + strings.ToUpper(newDigest)}, ""},
+		{"old only", Pin{Old: oldDigest}, ""},
+		{"old mismatch", Pin{Old: newDigest}, "old schema sha256 " + oldDigest + " doesn't match pinned " + newDigest},
+		{"new mismatch", Pin{Old: oldDigest, New: oldDigest}, "new schema sha256"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff, err := NewDiffBytes(oldJSON, newJSON, Options{Pin: tt.pin})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("NewDiffBytes() error = %v, wanted %v", err, tt.wantErr)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("NewDiffBytes() error: %s", err)
+			}
+
+			if diff.Old.Digest != oldDigest || diff.New.Digest != newDigest {
+				t.Errorf("NewDiffBytes() digests = %v %v, want %v %v", diff.Old.Digest, diff.New.Digest, oldDigest, newDigest)
+			}
+		})
+	}
+}
+
+func TestPin_recorded(t *testing.T) {
+	pin := Pin{}
+	if err := pin.recorded("abc"); err != nil || pin.Old != "abc" {
+		t.Errorf("recorded() = %v, pin = %v, want abc", err, pin.Old)
+	}
+
+	pin = Pin{Old: "sha256:ABC"}
+	if err := pin.recorded("abc"); err != nil {
+		t.Errorf("recorded() error: %s", err)
+	}
+
+	if err := pin.recorded("def"); err == nil {
+		t.Errorf("recorded digest must match pinned one")
+	}
+}
@@ -0,0 +1,173 @@
+package rpcdiff
+
+import (
+	"fmt"
+
+	openrpc "github.com/vmkteam/meta-schema/v2"
+)
+
+// Policy is a set of governance rules evaluated over diff
+type Policy struct {
+	DeprecateBeforeRemoval bool       `json:"deprecateBeforeRemoval"` // removed methods must be deprecated in old schema
+	NoNewRequiredParams    bool       `json:"noNewRequiredParams"`    // existing methods can't get new required params
+	ErrorCodes             *CodeRange `json:"errorCodes,omitempty"`   // allowed range of error codes in new schema
+	DeprecationGrace       int        `json:"deprecationGrace"`       // minor versions after x-deprecated-since before removal is allowed
+	DeprecatedReleases     int        `json:"deprecatedReleases"`     // releases of history store including old schema removed methods must be deprecated in
+}
+
+// CodeRange is an inclusive range of error codes
+type CodeRange struct {
+	Min int64 `json:"min"`
+	Max int64 `json:"max"`
+}
+
+// Policy rules
+const (
+	RuleDeprecateBeforeRemoval = "deprecate-before-removal"
+	RuleNoNewRequiredParams    = "no-new-required-params"
+	RuleErrorCodes             = "error-codes"
+	RuleDeprecationGrace       = "deprecation-grace"
+	RuleDeprecatedReleases     = "deprecated-releases"
+)
+
+// Violation is a policy rule violation
+type Violation struct {
+	Rule    string   `json:"rule"`
+	Path    []string `json:"path"`
+	Message string   `json:"message"`
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("[%s] %s", v.Rule, v.Message)
+}
+
+// Evaluate checks diff of documents against policy rules, history is previous releases of service ordered by creation time
+func (p *Policy) Evaluate(changes []Change, oldDoc, newDoc *openrpc.OpenrpcDocument, history []Record, oldDigest string) []Violation {
+	if p == nil {
+		return nil
+	}
+
+	var violations []Violation
+	for _, c := range changes {
+		methodName := after(c.Path, "methods")
+
+		switch {
+		case p.DeprecateBeforeRemoval && c.Object == Method && c.Type == Removed:
+			if method := findMethod(oldDoc, methodName); method != nil && !method.Deprecated {
+				violations = append(violations, Violation{
+					Rule:    RuleDeprecateBeforeRemoval,
+					Path:    c.Path,
+					Message: fmt.Sprintf(`method "%s" is removed without deprecation`, methodName),
+				})
+			}
+		case p.NoNewRequiredParams && c.Object == MethodParam && c.Criticality == Breaking:
+			violations = append(violations, Violation{
+				Rule:    RuleNoNewRequiredParams,
+				Path:    c.Path,
+				Message: fmt.Sprintf(`method "%s" has new required arg "%s"`, methodName, after(c.Path, "params")),
+			})
+		}
+
+		if p.DeprecatedReleases > 0 && c.Object == Method && c.Type == Removed {
+			if n := deprecatedReleases(methodName, oldDoc, history, oldDigest); n < p.DeprecatedReleases {
+				violations = append(violations, Violation{
+					Rule:    RuleDeprecatedReleases,
+					Path:    c.Path,
+					Message: fmt.Sprintf(`method "%s" is removed after %d deprecated release(s), %d required`, methodName, n, p.DeprecatedReleases),
+				})
+			}
+		}
+	}
+
+	if p.ErrorCodes != nil {
+		violations = append(violations, p.evaluateErrorCodes(newDoc)...)
+	}
+
+	return violations
+}
+
+// evaluateErrorCodes checks that every error code of methods is in allowed range
+func (p *Policy) evaluateErrorCodes(doc *openrpc.OpenrpcDocument) []Violation {
+	var violations []Violation
+	for _, method := range doc.Methods {
+		if method.MethodObject == nil {
+			continue
+		}
+
+		for _, e := range method.Errors {
+			if e.ErrorObject == nil || e.Code >= p.ErrorCodes.Min && e.Code <= p.ErrorCodes.Max {
+				continue
+			}
+
+			violations = append(violations, Violation{
+				Rule:    RuleErrorCodes,
+				Path:    []string{"methods", method.Name, "errors", fmt.Sprintf("%d", e.Code)},
+				Message: fmt.Sprintf(`error %d of method "%s" is out of range %d..%d`, e.Code, method.Name, p.ErrorCodes.Min, p.ErrorCodes.Max),
+			})
+		}
+	}
+
+	return violations
+}
+
+// deprecatedReleases returns number of consecutive releases up to old schema where method is deprecated.
+// Releases recorded after old schema are skipped, old schema is the latest release if it isn't recorded.
+func deprecatedReleases(name string, oldDoc *openrpc.OpenrpcDocument, history []Record, oldDigest string) int {
+	if method := findMethod(oldDoc, name); method == nil || !method.Deprecated {
+		return 0
+	}
+
+	last := len(history) - 1
+	for i, r := range history {
+		if r.Digest == oldDigest {
+			last = i - 1
+		}
+	}
+
+	count := 1
+	for i := last; i >= 0; i-- {
+		doc, err := parseDocument(history[i].Schema)
+		if err != nil {
+			break
+		}
+
+		if method := findMethod(doc, name); method == nil || !method.Deprecated {
+			break
+		}
+		count++
+	}
+
+	return count
+}
+
+// LoadHistory sets history of service from store of config if policy counts deprecated releases
+func (o *Options) LoadHistory(cfg *Config, service string) error {
+	if o.Policy == nil || o.Policy.DeprecatedReleases == 0 {
+		return nil
+	}
+
+	if service == "" {
+		return fmt.Errorf("service is required to count deprecated releases in history store")
+	}
+
+	store, err := OpenConfigStore(cfg)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	o.History, err = store.List(service)
+
+	return err
+}
+
+// findMethod returns method by name
+func findMethod(doc *openrpc.OpenrpcDocument, name string) *openrpc.MethodObject {
+	for _, method := range doc.Methods {
+		if method.MethodObject != nil && method.Name == name {
+			return method.MethodObject
+		}
+	}
+
+	return nil
+}
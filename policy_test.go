@@ -0,0 +1,80 @@
+package rpcdiff
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestPolicy_Evaluate(t *testing.T) {
+	policy := &Policy{
+		DeprecateBeforeRemoval: true,
+		NoNewRequiredParams:    true,
+		ErrorCodes:             &CodeRange{Min: -32099, Max: -32000},
+	}
+
+	diff, err := NewDiff("testdata/openrpc_old.json", "testdata/openrpc_new.json", Options{Policy: policy})
+	if err != nil {
+		t.Fatalf("new diff error: %s", err)
+	}
+
+	rules := map[string]int{}
+	for _, v := range diff.Violations {
+		rules[v.Rule]++
+	}
+
+	// check.RemovedMethod
+	if rules[RuleDeprecateBeforeRemoval] != 1 {
+		t.Errorf("%s violations = %v, wanted %v", RuleDeprecateBeforeRemoval, rules[RuleDeprecateBeforeRemoval], 1)
+	}
+
+	// param1 set as required and param2 added to check.AddRequiredParam
+	if rules[RuleNoNewRequiredParams] != 2 {
+		t.Errorf("%s violations = %v, wanted %v", RuleNoNewRequiredParams, rules[RuleNoNewRequiredParams], 2)
+	}
+
+	// 404 and 502 errors
+	if rules[RuleErrorCodes] != 3 {
+		t.Errorf("%s violations = %v, wanted %v", RuleErrorCodes, rules[RuleErrorCodes], 3)
+	}
+}
+
+func TestPolicy_deprecatedReleases(t *testing.T) {
+	schema := func(methods string) []byte {
+		return []byte(`{"openrpc": "1.2.6", "info": {"title": "test", "version": "v0.0.0"}, "methods": [` + methods + `]}`)
+	}
+	deprecated := schema(`{"name": "user.Old", "params": [], "result": {"name": "result", "schema": {"type": "boolean"}}, "deprecated": true}`)
+	active := schema(`{"name": "user.Old", "params": [], "result": {"name": "result", "schema": {"type": "boolean"}}}`)
+	deprecatedBefore := schema(`{"name": "user.Old", "deprecated": true, "params": [], "result": {"name": "result", "schema": {"type": "boolean"}}}`)
+	removed := schema(``)
+
+	tests := []struct {
+		name    string
+		history [][]byte
+		old     []byte
+		want    int
+	}{
+		{name: "should count old schema", old: deprecated, want: 1},
+		{name: "should count recorded releases", history: [][]byte{active, deprecatedBefore}, old: deprecated},
+		{name: "should stop at release without deprecation", history: [][]byte{deprecatedBefore, active}, old: deprecated, want: 1},
+		{name: "should skip releases after old schema", history: [][]byte{deprecatedBefore, deprecated, active}, old: deprecated},
+		{name: "should report removal without deprecation", history: [][]byte{deprecated}, old: active, want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var history []Record
+			for i, data := range tt.history {
+				history = append(history, Record{Service: "user", Version: fmt.Sprintf("v%d", i), Digest: digest(data), Schema: data})
+			}
+
+			diff, err := NewDiffBytes(tt.old, removed, Options{Policy: &Policy{DeprecatedReleases: 2}, History: history})
+			if err != nil {
+				t.Fatalf("new diff error: %s", err)
+			}
+
+			if len(diff.Violations) != tt.want {
+				t.Errorf("violations = %v, wanted %v", diff.Violations, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package rpcdiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+const (
+	// DefaultPrometheusQuery is a template of PromQL query returning calls per day by method label
+	DefaultPrometheusQuery = `sum by ({{.Label}}) (increase(rpc_requests_total[1d]))`
+	DefaultPrometheusLabel = "method"
+)
+
+// Prometheus is a source of calls per day by method
+type Prometheus struct {
+	URL   string `json:"url"`             // base url of prometheus api, e.g. http://prometheus:9090
+	Query string `json:"query,omitempty"` // PromQL template, {{.Label}} is replaced by method label name
+	Label string `json:"label,omitempty"` // name of label with method name, method by default
+}
+
+// query renders PromQL query from template
+func (p Prometheus) query() (string, error) {
+	query, label := p.Query, p.Label
+	if query == "" {
+		query = DefaultPrometheusQuery
+	}
+	if label == "" {
+		label = DefaultPrometheusLabel
+	}
+
+	t, err := template.New("query").Option("missingkey=error").Parse(query)
+	if err != nil {
+		return "", fmt.Errorf("parse prometheus query error: %w", err)
+	}
+
+	buf := strings.Builder{}
+	if err := t.Execute(&buf, struct{ Label string }{label}); err != nil {
+		return "", fmt.Errorf("render prometheus query error: %w", err)
+	}
+
+	return buf.String(), nil
+}
+
+// prometheusResponse is a response of instant query api
+type prometheusResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		ResultType string `json:"resultType"`
+		Result     []struct {
+			Metric map[string]string `json:"metric"`
+			Value  [2]interface{}    `json:"value"` // unix time and value as string
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// FetchUsage runs instant query and returns its value by method label, series without label are skipped
+func (p Prometheus) FetchUsage() (Usage, error) {
+	query, err := p.query()
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := strings.TrimSuffix(p.URL, "/") + "/api/v1/query?query=" + url.QueryEscape(query)
+	body, err := httpDo(func() (*http.Request, error) {
+		return http.NewRequest(http.MethodGet, endpoint, nil)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("prometheus query error: %w", err)
+	}
+
+	var resp prometheusResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("parse prometheus response error: %w", err)
+	}
+
+	switch {
+	case resp.Status != "success":
+		return nil, fmt.Errorf("prometheus query error: %s", resp.Error)
+	case resp.Data.ResultType != "vector":
+		return nil, fmt.Errorf("prometheus query returned %s, wanted vector", resp.Data.ResultType)
+	}
+
+	label := p.Label
+	if label == "" {
+		label = DefaultPrometheusLabel
+	}
+
+	usage := Usage{}
+	for _, sample := range resp.Data.Result {
+		method := sample.Metric[label]
+		if method == "" {
+			continue
+		}
+
+		s, _ := sample.Value[1].(string)
+		calls, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("prometheus value of %s: %w", method, err)
+		}
+
+		usage[method] += calls
+	}
+
+	return usage, nil
+}
+
+// LoadUsage adds calls per day from prometheus to usage, methods from usage manifest take precedence
+func (o *Options) LoadUsage() error {
+	if o.Prometheus.URL == "" {
+		return nil
+	}
+
+	usage, err := o.Prometheus.FetchUsage()
+	if err != nil {
+		return err
+	}
+
+	for method, calls := range o.Usage {
+		usage[method] = calls
+	}
+	o.Usage = usage
+
+	return nil
+}
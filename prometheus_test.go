@@ -0,0 +1,71 @@
+package rpcdiff
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestPrometheusFetchUsage(t *testing.T) {
+	var query string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query().Get("query")
+		w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[
+			{"metric":{"rpc_method":"billing.Get"},"value":[1700000000,"2000000"]},
+			{"metric":{"rpc_method":"users.Get"},"value":[1700000000,"1500.5"]},
+			{"metric":{},"value":[1700000000,"10"]}
+		]}}`))
+	}))
+	defer srv.Close()
+
+	p := Prometheus{URL: srv.URL + "/", Query: `sum by ({{.Label}}) (increase(calls_total[1d]))`, Label: "rpc_method"}
+	got, err := p.FetchUsage()
+	if err != nil {
+		t.Fatalf("FetchUsage() error: %s", err)
+	}
+
+	if want := `sum by (rpc_method) (increase(calls_total[1d]))`; query != want {
+		t.Errorf("FetchUsage() query = %v, want %v", query, want)
+	}
+
+	want := Usage{"billing.Get": 2e6, "users.Get": 1500.5}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FetchUsage() = %v, want %v", got, want)
+	}
+
+	opts := Options{Usage: Usage{"users.Get": 10}, Prometheus: p}
+	if err := opts.LoadUsage(); err != nil {
+		t.Fatalf("LoadUsage() error: %s", err)
+	}
+
+	if want := (Usage{"billing.Get": 2e6, "users.Get": 10}); !reflect.DeepEqual(opts.Usage, want) {
+		t.Errorf("LoadUsage() usage = %v, want %v", opts.Usage, want)
+	}
+}
+
+func TestPrometheusFetchUsageError(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "error", body: `{"status":"error","error":"parse error"}`},
+		{name: "matrix", body: `{"status":"success","data":{"resultType":"matrix","result":[]}}`},
+		{name: "value", body: `{"status":"success","data":{"resultType":"vector","result":[{"metric":{"method":"a.B"},"value":[1,"x"]}]}}`},
+	}
+
+	for _, tt := range tests {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(tt.body))
+		}))
+
+		if _, err := (Prometheus{URL: srv.URL}).FetchUsage(); err == nil {
+			t.Errorf("FetchUsage() %s: error = nil, want error", tt.name)
+		}
+		srv.Close()
+	}
+
+	if _, err := (Prometheus{URL: "http://localhost", Query: "{{.Missing}}"}).FetchUsage(); err == nil {
+		t.Errorf("FetchUsage() with bad template: error = nil, want error")
+	}
+}
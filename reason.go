@@ -0,0 +1,97 @@
+package rpcdiff
+
+import (
+	"strings"
+)
+
+// reasonSubjects name change objects in reasons
+var reasonSubjects = map[ChangeObject]string{
+	OpenRPCVersion:               "openrpc version",
+	SchemaInfo:                   "schema info",
+	SchemaVersion:                "schema version",
+	SchemaServers:                "server",
+	Method:                       "method",
+	MethodParamStructure:         "param structure",
+	MethodParam:                  "input parameter",
+	MethodParamType:              "input type",
+	MethodParamTypeDescription:   "input type description",
+	MethodResult:                 "result",
+	MethodResultType:             "output type",
+	MethodResultName:             "result name",
+	MethodResultLoosened:         "output type",
+	MethodError:                  "error",
+	MethodServers:                "method server",
+	Example:                      "example",
+	ExampleMismatch:              "example",
+	ComponentsSchema:             "schema",
+	ComponentsSchemaType:         "schema type",
+	ComponentsSchemaProperty:     "schema property",
+	ComponentsSchemaPropertyType: "schema property type",
+	ComponentsDescriptor:         "content descriptor",
+	ComponentsDescriptorType:     "content descriptor type",
+}
+
+// typeObjects are change objects of types which criticality depends on compatibility of old and new types
+var typeObjects = map[ChangeObject]bool{
+	MethodParamType:              true,
+	MethodResultType:             true,
+	ComponentsSchemaType:         true,
+	ComponentsSchemaPropertyType: true,
+	ComponentsDescriptorType:     true,
+}
+
+// engineReason explains criticality assigned to change by comparison engine, e.g. "new required input parameter"
+func engineReason(c Change) string {
+	reason := changeReason(c)
+	switch c.Criticality {
+	case PossiblyBreaking:
+		reason += ", compatibility can't be proven"
+	case Info:
+		reason += ", documentation only"
+	}
+
+	return reason
+}
+
+// changeReason describes change for engineReason
+func changeReason(c Change) string {
+	subject, ok := reasonSubjects[c.Object]
+	if !ok {
+		subject = "schema field"
+	}
+
+	switch {
+	case c.Object == ExampleMismatch && c.Reason != "":
+		return c.Reason
+	case c.Object == ExampleMismatch:
+		return "example doesn't match its schema"
+	case c.Object == MethodResultLoosened:
+		return "output type loosened to any"
+	case c.Object == MethodParam && c.Type == Added && c.Criticality == Breaking:
+		return "new required input parameter"
+	case c.Object == MethodParam && c.Type == Added:
+		return "new optional input parameter"
+	case c.Object == ComponentsSchema && contains(c.Path, "required"):
+		return "schema property became " + requiredString(c.Type, c.Old, c.New)
+	case contains(c.Path, "required"):
+		return subject + " became " + requiredString(c.Type, c.Old, c.New)
+	case c.Type == Added:
+		return "new " + subject
+	case c.Type == Removed:
+		return subject + " removed"
+	case typeObjects[c.Object] && c.Criticality == Breaking:
+		return subject + " changed incompatibly"
+	case typeObjects[c.Object] && c.Criticality == Dangerous:
+		return subject + " changed, may break clients"
+	case typeObjects[c.Object]:
+		return subject + " changed compatibly"
+	case c.IsHeuristic():
+		return subject + " changed, guessed by heuristic"
+	}
+
+	if field := last(c.Path); !strings.HasSuffix(subject, field) {
+		return subject + " " + field + " changed"
+	}
+
+	return subject + " changed"
+}
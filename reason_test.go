@@ -0,0 +1,54 @@
+package rpcdiff
+
+import (
+	"testing"
+)
+
+func TestEngineReason(t *testing.T) {
+	tests := []struct {
+		change Change
+		want   string
+	}{
+		{Change{Path: []string{"methods", "user.Get"}, Type: Removed, Object: Method, Criticality: Breaking}, "method removed"},
+		{Change{Path: []string{"methods", "user.Get", "params", "id"}, Type: Added, Object: MethodParam, Criticality: Breaking}, "new required input parameter"},
+		{Change{Path: []string{"methods", "user.Get", "params", "id"}, Type: Added, Object: MethodParam, Criticality: NonBreaking}, "new optional input parameter"},
+		{Change{Path: []string{"methods", "user.Get", "params", "id", "required"}, Type: Changed, Object: MethodParam, Criticality: Breaking, Old: false, New: true}, "input parameter became required"},
+		{Change{Path: []string{"components", "schemas", "User", "required", "name"}, Type: Removed, Object: ComponentsSchema, Criticality: NonBreaking}, "schema property became not required"},
+		{Change{Path: []string{"methods", "user.Get", "result", "$ref"}, Type: Changed, Object: MethodResultType, Criticality: Breaking}, "output type changed incompatibly"},
+		{Change{Path: []string{"methods", "user.Get", "result"}, Type: Changed, Object: MethodResultLoosened, Criticality: Dangerous}, "output type loosened to any"},
+		{Change{Path: []string{"methods", "user.Get", "summary"}, Type: Changed, Object: Method, Criticality: NonBreaking}, "method summary changed"},
+		{Change{Path: []string{"info", "version"}, Type: Changed, Object: SchemaVersion, Criticality: NonBreaking}, "schema version changed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := engineReason(tt.change); got != tt.want {
+				t.Errorf("engineReason() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewDiff_reasons(t *testing.T) {
+	rules := Rules{{Object: Method, Type: Removed, Set: Dangerous, Reason: "method is internal"}, {Object: MethodError, Set: Breaking}}
+	diff, err := NewDiff("testdata/openrpc_old.json", "testdata/openrpc_new.json", Options{Rules: rules})
+	if err != nil {
+		t.Fatalf("new diff error: %s", err)
+	}
+
+	for _, c := range diff.Changes {
+		want := engineReason(c)
+		switch c.Object {
+		case Method:
+			if c.Type == Removed {
+				want = "method is internal"
+			}
+		case MethodError:
+			want = "set to breaking by rule"
+		}
+
+		if c.Reason != want {
+			t.Errorf("reason of %v = %v, want %v", c.String(), c.Reason, want)
+		}
+	}
+}
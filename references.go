@@ -0,0 +1,38 @@
+package rpcdiff
+
+const (
+	openrpcSpecURL    = "https://spec.open-rpc.org/"
+	jsonSchemaSpecURL = "https://json-schema.org/draft/2019-09/json-schema-validation.html"
+)
+
+// specReferences are spec sections explaining change objects
+var specReferences = map[ChangeObject]string{
+	OpenRPCVersion:               openrpcSpecURL + "#openrpc-object",
+	SchemaInfo:                   openrpcSpecURL + "#info-object",
+	SchemaVersion:                openrpcSpecURL + "#info-object",
+	SchemaServers:                openrpcSpecURL + "#server-object",
+	Method:                       openrpcSpecURL + "#method-object",
+	MethodParamStructure:         openrpcSpecURL + "#method-object",
+	MethodParam:                  openrpcSpecURL + "#content-descriptor-object",
+	MethodParamType:              jsonSchemaSpecURL + "#rfc.section.6.1.1",
+	MethodParamTypeDescription:   openrpcSpecURL + "#content-descriptor-object",
+	MethodResult:                 openrpcSpecURL + "#method-object",
+	MethodResultType:             jsonSchemaSpecURL + "#rfc.section.6.1.1",
+	MethodResultName:             openrpcSpecURL + "#content-descriptor-object",
+	MethodResultLoosened:         openrpcSpecURL + "#schema-object",
+	MethodError:                  openrpcSpecURL + "#error-object",
+	MethodServers:                openrpcSpecURL + "#server-object",
+	Example:                      openrpcSpecURL + "#example-pairing-object",
+	ExampleMismatch:              openrpcSpecURL + "#example-object",
+	ComponentsSchema:             openrpcSpecURL + "#components-object",
+	ComponentsSchemaType:         jsonSchemaSpecURL + "#rfc.section.6.1.1",
+	ComponentsSchemaProperty:     jsonSchemaSpecURL + "#rfc.section.6.5",
+	ComponentsSchemaPropertyType: jsonSchemaSpecURL + "#rfc.section.6.1.1",
+	ComponentsDescriptor:         openrpcSpecURL + "#components-object",
+	ComponentsDescriptorType:     jsonSchemaSpecURL + "#rfc.section.6.1.1",
+}
+
+// specReference returns url of spec section explaining change object
+func specReference(object ChangeObject) string {
+	return specReferences[object]
+}
@@ -0,0 +1,43 @@
+package rpcdiff
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewDiff_references(t *testing.T) {
+	diff, err := NewDiff("testdata/openrpc_old.json", "testdata/openrpc_new.json", Options{ShowMeta: true, ShowLinks: true})
+	if err != nil {
+		t.Fatalf("new diff error: %s", err)
+	}
+
+	for _, c := range diff.Changes {
+		if c.Object != Other && c.Reference == "" {
+			t.Errorf("change %s (%s) has no reference", c.String(), c.Object)
+		}
+	}
+
+	if !strings.Contains(diff.String(), "  see https://spec.open-rpc.org/#method-object\n") {
+		t.Errorf("String() doesn't contain method reference")
+	}
+}
+
+func TestChange_Anchor(t *testing.T) {
+	c := Change{Path: []string{"methods", "user.Get"}, Type: Removed, Object: Method, Criticality: Breaking}
+	anchor := c.Anchor()
+
+	if !strings.HasPrefix(anchor, "change-") || len(anchor) != len("change-")+12 {
+		t.Errorf("Anchor() = %v, wanted change- and 12 hex digits", anchor)
+	}
+
+	c.Criticality = NonBreaking
+	c.Old = "x"
+	if got := c.Anchor(); got != anchor {
+		t.Errorf("Anchor() = %v, want %v", got, anchor)
+	}
+
+	c.Path = []string{"methods", "user.Delete"}
+	if got := c.Anchor(); got == anchor {
+		t.Errorf("Anchor() of other change = %v, wanted different", got)
+	}
+}
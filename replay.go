@@ -0,0 +1,145 @@
+package rpcdiff
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Interaction is a recorded json-rpc request and response
+type Interaction struct {
+	Request  json.RawMessage `json:"request"`
+	Response json.RawMessage `json:"response,omitempty"`
+}
+
+// ReplayResult is a validation result of recorded interaction against schema
+type ReplayResult struct {
+	Index  int      `json:"index"` // 1-based line of json lines or entry of har
+	Method string   `json:"method"`
+	Errors []string `json:"errors,omitempty"`
+}
+
+// parseInteractions reads json lines of interactions or har archive
+func parseInteractions(data []byte) ([]Interaction, error) {
+	var har struct {
+		Log *struct {
+			Entries []struct {
+				Request struct {
+					PostData struct {
+						Text string `json:"text"`
+					} `json:"postData"`
+				} `json:"request"`
+				Response struct {
+					Content struct {
+						Text string `json:"text"`
+					} `json:"content"`
+				} `json:"response"`
+			} `json:"entries"`
+		} `json:"log"`
+	}
+	if err := json.Unmarshal(data, &har); err == nil && har.Log != nil {
+		result := make([]Interaction, 0, len(har.Log.Entries))
+		for _, e := range har.Log.Entries {
+			in := Interaction{Request: json.RawMessage(e.Request.PostData.Text)}
+			if e.Response.Content.Text != "" {
+				in.Response = json.RawMessage(e.Response.Content.Text)
+			}
+			result = append(result, in)
+		}
+
+		return result, nil
+	}
+
+	var result []Interaction
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+
+		var in Interaction
+		if err := json.Unmarshal(scanner.Bytes(), &in); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		result = append(result, in)
+	}
+
+	return result, scanner.Err()
+}
+
+// Replay validates recorded interactions against schema
+func Replay(data, schema []byte) ([]ReplayResult, error) {
+	interactions, err := parseInteractions(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse interactions error: %w", err)
+	}
+
+	v, err := NewValidator(schema)
+	if err != nil {
+		return nil, fmt.Errorf("parse schema error: %w", err)
+	}
+
+	result := make([]ReplayResult, 0, len(interactions))
+	for i, in := range interactions {
+		rr := ReplayResult{Index: i + 1}
+
+		var req struct {
+			Method string `json:"method"`
+		}
+		if err := json.Unmarshal(in.Request, &req); err != nil {
+			rr.Errors = []string{fmt.Sprintf("invalid request: %s", err)}
+			result = append(result, rr)
+			continue
+		}
+
+		rr.Method = req.Method
+		rr.Errors = appendValidationErrors(rr.Errors, "request", v.ValidateRequest(in.Request))
+		if len(in.Response) > 0 && findMethod(v.doc, req.Method) != nil {
+			rr.Errors = appendValidationErrors(rr.Errors, "response", v.ValidateResponse(req.Method, in.Response))
+		}
+
+		result = append(result, rr)
+	}
+
+	return result, nil
+}
+
+// appendValidationErrors appends problems of validation error or invalid json error
+func appendValidationErrors(errs []string, kind string, err error) []string {
+	if err == nil {
+		return errs
+	}
+
+	var ve *ValidationError
+	if errors.As(err, &ve) {
+		return append(errs, ve.Errors...)
+	}
+
+	return append(errs, fmt.Sprintf("invalid %s: %s", kind, err))
+}
+
+// ReplayReport renders invalid interactions and totals
+func ReplayReport(results []ReplayResult) string {
+	buf := strings.Builder{}
+	var invalid int
+	for _, rr := range results {
+		if len(rr.Errors) == 0 {
+			continue
+		}
+
+		invalid++
+		fmt.Fprintf(&buf, "#%d %s:\n", rr.Index, rr.Method)
+		for _, e := range rr.Errors {
+			fmt.Fprintf(&buf, "  - %s\n", e)
+		}
+	}
+
+	fmt.Fprintf(&buf, "%d valid, %d invalid interaction(s)\n", len(results)-invalid, invalid)
+
+	return buf.String()
+}
@@ -0,0 +1,62 @@
+package rpcdiff
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestReplay(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/replay.jsonl")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema, err := ioutil.ReadFile("testdata/openrpc_new.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := Replay(data, schema)
+	if err != nil {
+		t.Fatalf("replay error: %s", err)
+	}
+
+	want := []string{
+		`arg "param1": $: expected integer, got string`,
+		`method "check.RemovedMethod" is not found`,
+		`required arg "param2" is missing`,
+		``,
+		`result: $: expected null, got integer`,
+		``,
+	}
+	if len(results) != len(want) {
+		t.Fatalf("len(results) = %v, wanted %v", len(results), len(want))
+	}
+
+	for i, rr := range results {
+		if got := strings.Join(rr.Errors, "; "); got != want[i] {
+			t.Errorf("results[%d].Errors = %v, want %v", i, got, want[i])
+		}
+	}
+
+	if report := ReplayReport(results); !strings.HasSuffix(report, "2 valid, 4 invalid interaction(s)\n") {
+		t.Errorf("unexpected report: %s", report)
+	}
+}
+
+func Test_parseInteractions_har(t *testing.T) {
+	har := `{"log": {"entries": [{
+		"request": {"postData": {"text": "{\"method\": \"check.UntouchedMethod\"}"}},
+		"response": {"content": {"text": "{\"result\": null}"}}
+	}]}}`
+
+	interactions, err := parseInteractions([]byte(har))
+	if err != nil {
+		t.Fatalf("parse interactions error: %s", err)
+	}
+
+	if len(interactions) != 1 || string(interactions[0].Request) != `{"method": "check.UntouchedMethod"}` || string(interactions[0].Response) != `{"result": null}` {
+		t.Errorf("parseInteractions() = %+v", interactions)
+	}
+}
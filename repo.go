@@ -0,0 +1,94 @@
+package rpcdiff
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// ServiceDiff is a diff of one service schema in repository
+type ServiceDiff struct {
+	Service string `json:"service"`
+	Diff    *Diff  `json:"diff,omitempty"`
+	Error   string `json:"error,omitempty"`
+	Status  string `json:"status,omitempty"` // passed, breaking, failed or skipped in batch
+}
+
+// NewRepoDiff compares schemas of every configured service.
+// Old schema is taken from git ref, or from published url if ref is empty; new schema is taken from working tree.
+func NewRepoDiff(cfg *Config, oldRef string, options Options, batch Batch) []ServiceDiff {
+	return batch.run(cfg.Services, func(service ServiceConfig) (*Diff, error) {
+		options := options
+		if err := options.LoadHistory(cfg, service.Name); err != nil {
+			return nil, err
+		}
+
+		return newServiceDiff(cfg.dir, service, oldRef, options)
+	})
+}
+
+func newServiceDiff(dir string, service ServiceConfig, oldRef string, options Options) (*Diff, error) {
+	var (
+		oldBytes []byte
+		err      error
+	)
+
+	switch {
+	case oldRef != "":
+		oldBytes, err = gitShow(dir, oldRef, service.Path)
+	case service.URL != "":
+		oldBytes, err = ReadFileOrURL(service.URL)
+	default:
+		return nil, fmt.Errorf("neither git ref nor url of old schema is set")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read old schema error: %w", err)
+	}
+
+	newBytes, err := ReadFileOrURL(filepath.Join(dir, service.Path))
+	if err != nil {
+		return nil, fmt.Errorf("read new schema error: %w", err)
+	}
+
+	return NewDiffBytes(oldBytes, newBytes, options)
+}
+
+// gitShow returns file contents at git ref, path is relative to dir
+func gitShow(dir, ref, path string) ([]byte, error) {
+	var stderr bytes.Buffer
+
+	cmd := exec.Command("git", "show", ref+":./"+filepath.ToSlash(path))
+	cmd.Dir = dir
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git show error: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return out, nil
+}
+
+// RepoReport renders diffs of services as sections followed by summary table
+func RepoReport(diffs []ServiceDiff) string {
+	buf := strings.Builder{}
+	for _, sd := range diffs {
+		fmt.Fprintf(&buf, "=== %s\n", sd.Service)
+		switch {
+		case sd.Error != "":
+			fmt.Fprintf(&buf, "Error: %s\n", sd.Error)
+		case sd.Diff == nil:
+			buf.WriteString("Skipped\n")
+		default:
+			fmt.Fprintf(&buf, "%s\n", strings.TrimSuffix(sd.Diff.String(), "\n"))
+		}
+
+		buf.WriteString("\n")
+	}
+
+	buf.WriteString(batchSummary(diffs))
+
+	return buf.String()
+}
@@ -0,0 +1,34 @@
+package rpcdiff
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewRepoDiff(t *testing.T) {
+	cfg := &Config{
+		Services: []ServiceConfig{
+			{Name: "check", Path: "openrpc_new.json", URL: "testdata/openrpc_old.json"},
+			{Name: "unknown", Path: "openrpc_new.json"},
+		},
+		dir: "testdata",
+	}
+
+	diffs := NewRepoDiff(cfg, "", Options{}, Batch{MaxScore: 100})
+	if len(diffs) != 2 {
+		t.Fatalf("len(diffs) = %v, wanted %v", len(diffs), 2)
+	}
+
+	if diffs[0].Error != "" || diffs[0].Diff.Criticality != Breaking {
+		t.Errorf("diffs[0] = %+v, wanted breaking diff", diffs[0])
+	}
+
+	if diffs[1].Error == "" {
+		t.Errorf("diffs[1].Error is empty")
+	}
+
+	report := RepoReport(diffs)
+	if !strings.Contains(report, "=== check\n") || !strings.Contains(report, "=== unknown\nError: ") {
+		t.Errorf("unexpected report: %s", report)
+	}
+}
@@ -0,0 +1,67 @@
+package rpcdiff
+
+import (
+	"time"
+)
+
+// Retention limits records kept per service in history store, the latest record of service is always kept
+// as base of the next diff
+type Retention struct {
+	KeepLast int `json:"keepLast,omitempty"` // number of the latest versions kept, every version if 0
+	TTLDays  int `json:"ttlDays,omitempty"`  // records older than ttl are removed, no limit if 0
+}
+
+// Enabled checks that retention removes any records
+func (r Retention) Enabled() bool {
+	return r.KeepLast > 0 || r.TTLDays > 0
+}
+
+// expired returns records removed by retention at now, records are ordered by creation time as by Store.List
+func (r Retention) expired(records []Record, now time.Time) []Record {
+	byService := map[string][]Record{}
+	var services []string
+	for _, rec := range records {
+		if _, ok := byService[rec.Service]; !ok {
+			services = append(services, rec.Service)
+		}
+		byService[rec.Service] = append(byService[rec.Service], rec)
+	}
+
+	deadline := now.AddDate(0, 0, -r.TTLDays)
+
+	var result []Record
+	for _, service := range services {
+		list := byService[service]
+		for i, rec := range list[:len(list)-1] {
+			// position from the end, the latest record is 1
+			fromEnd := len(list) - i
+			if (r.KeepLast > 0 && fromEnd > r.KeepLast) || (r.TTLDays > 0 && rec.CreatedAt.Before(deadline)) {
+				result = append(result, rec)
+			}
+		}
+	}
+
+	return result
+}
+
+// CollectGarbage removes records of service expired by retention at now from store, every service if service is empty,
+// and returns removed records
+func CollectGarbage(store Store, service string, retention Retention, now time.Time) ([]Record, error) {
+	if !retention.Enabled() {
+		return nil, nil
+	}
+
+	records, err := store.List(service)
+	if err != nil {
+		return nil, err
+	}
+
+	expired := retention.expired(records, now)
+	for i, r := range expired {
+		if err := store.Delete(r.Service, r.Digest); err != nil {
+			return expired[:i], err
+		}
+	}
+
+	return expired, nil
+}
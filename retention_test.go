@@ -0,0 +1,65 @@
+package rpcdiff
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCollectGarbage(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		retention Retention
+		service   string
+		want      string // versions left
+	}{
+		{name: "keep last", retention: Retention{KeepLast: 2}, want: "billing@1.0.2,users@1.0.0,billing@1.0.3"},
+		{name: "ttl", retention: Retention{TTLDays: 45}, want: "billing@1.0.2,users@1.0.0,billing@1.0.3"},
+		{name: "latest is kept", retention: Retention{TTLDays: 1}, want: "users@1.0.0,billing@1.0.3"},
+		{name: "service", retention: Retention{KeepLast: 1}, service: "users", want: "billing@1.0.0,billing@1.0.1,billing@1.0.2,users@1.0.0,billing@1.0.3"},
+		{name: "disabled", want: "billing@1.0.0,billing@1.0.1,billing@1.0.2,users@1.0.0,billing@1.0.3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := OpenStore(StorageConfig{}, t.TempDir())
+			if err != nil {
+				t.Fatalf("OpenStore() error: %s", err)
+			}
+			defer store.Close()
+
+			for i, r := range []Record{
+				{Service: "billing", Version: "1.0.0", Digest: "a", CreatedAt: now.AddDate(0, -3, 0)},
+				{Service: "billing", Version: "1.0.1", Digest: "b", CreatedAt: now.AddDate(0, -2, 0)},
+				{Service: "billing", Version: "1.0.2", Digest: "c", CreatedAt: now.AddDate(0, -1, 0)},
+				{Service: "users", Version: "1.0.0", Digest: "d", CreatedAt: now.AddDate(0, -1, 1)},
+				{Service: "billing", Version: "1.0.3", Digest: "e", CreatedAt: now.AddDate(0, 0, -1)},
+			} {
+				r.Schema = []byte(`{}`)
+				if err := store.Put(r); err != nil {
+					t.Fatalf("Put() #%d error: %s", i, err)
+				}
+			}
+
+			if _, err := CollectGarbage(store, tt.service, tt.retention, now); err != nil {
+				t.Fatalf("CollectGarbage() error: %s", err)
+			}
+
+			records, err := store.List("")
+			if err != nil {
+				t.Fatalf("List() error: %s", err)
+			}
+
+			var left []string
+			for _, r := range records {
+				left = append(left, r.Service+"@"+r.Version)
+			}
+
+			if got := strings.Join(left, ","); got != tt.want {
+				t.Errorf("CollectGarbage() left %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package rpcdiff
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/antonmedv/expr"
+	"github.com/antonmedv/expr/vm"
+)
+
+// Rule sets criticality of matching changes, empty conditions match any change
+type Rule struct {
+	Object       ChangeObject     `json:"object,omitempty"`
+	Type         ChangeType       `json:"type,omitempty"`
+	Criticality  CriticalityLevel `json:"criticality,omitempty"`
+	PathContains string           `json:"pathContains,omitempty"` // substring of any path element
+	When         string           `json:"when,omitempty"`         // boolean expression over ruleEnv, e.g. object == 'METHOD_PARAM' && path contains 'internal'
+	Set          CriticalityLevel `json:"set"`
+	Reason       string           `json:"reason,omitempty"` // reason of changes, "set by rule" if empty
+
+	program *vm.Program // compiled when expression
+}
+
+// ruleEnv is a change as seen by when expressions of rules
+type ruleEnv struct {
+	Object      string `expr:"object"`
+	Type        string `expr:"type"`
+	Criticality string `expr:"criticality"`
+	Path        string `expr:"path"`   // path elements joined with dots, e.g. methods.user.Get.params.id
+	Method      string `expr:"method"` // method name, empty for components
+	Schema      string `expr:"schema"` // components schema name, empty for methods
+	Owner       string `expr:"owner"`
+}
+
+// newRuleEnv returns environment of when expressions for change
+func newRuleEnv(c Change) ruleEnv {
+	return ruleEnv{
+		Object:      string(c.Object),
+		Type:        string(c.Type),
+		Criticality: string(c.Criticality),
+		Path:        strings.Join(c.Path, "."),
+		Method:      after(c.Path, "methods"),
+		Schema:      after(c.Path, "schemas"),
+		Owner:       c.Owner,
+	}
+}
+
+// compileWhen compiles when expression of rule as boolean expression over ruleEnv
+func compileWhen(when string) (*vm.Program, error) {
+	return expr.Compile(when, expr.Env(ruleEnv{}), expr.AsBool())
+}
+
+// Rules are evaluated in order, the first matching rule is applied
+type Rules []Rule
+
+// Validate checks that every rule sets criticality and compiles when expressions
+func (r Rules) Validate() error {
+	for i, rule := range r {
+		if rule.Set == "" {
+			return fmt.Errorf("rule #%d: set is empty", i)
+		}
+
+		if rule.When != "" {
+			program, err := compileWhen(rule.When)
+			if err != nil {
+				return fmt.Errorf("rule #%d: when: %w", i, err)
+			}
+			r[i].program = program
+		}
+	}
+
+	return nil
+}
+
+// match checks change against rule conditions
+func (r Rule) match(c Change) bool {
+	if r.Object != "" && r.Object != c.Object ||
+		r.Type != "" && r.Type != c.Type ||
+		r.Criticality != "" && r.Criticality != c.Criticality {
+		return false
+	}
+
+	if r.PathContains != "" && !pathContains(c.Path, r.PathContains) {
+		return false
+	}
+
+	if r.When == "" {
+		return true
+	}
+
+	program := r.program
+	if program == nil {
+		var err error
+		if program, err = compileWhen(r.When); err != nil {
+			return false
+		}
+	}
+
+	result, err := expr.Run(program, newRuleEnv(c))
+	ok, _ := result.(bool)
+
+	return err == nil && ok
+}
+
+// pathContains checks that any path element contains substring
+func pathContains(path []string, substr string) bool {
+	for _, p := range path {
+		if strings.Contains(p, substr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// apply sets criticality of changes by the first matching rule
+func (r Rules) apply(changes []Change) {
+	for i := range changes {
+		for _, rule := range r {
+			if rule.match(changes[i]) {
+				changes[i].Criticality = rule.Set
+				changes[i].Reason = rule.Reason
+				if rule.Reason == "" {
+					changes[i].Reason = fmt.Sprintf("set to %s by rule", rule.Set)
+				}
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package rpcdiff
+
+import "testing"
+
+func TestRules_apply(t *testing.T) {
+	rules := Rules{
+		{Object: MethodParam, PathContains: "Required", Set: NonBreaking},
+		{Type: Removed, Criticality: Breaking, Set: Dangerous},
+		{Type: Removed, Set: Breaking},
+	}
+
+	changes := []Change{
+		{Path: []string{"methods", "check.AddRequiredParam", "params", "param2"}, Type: Added, Object: MethodParam, Criticality: Breaking},
+		{Path: []string{"methods", "check.Removed"}, Type: Removed, Object: Method, Criticality: Breaking},
+		{Path: []string{"methods", "check.Other", "params", "param1"}, Type: Removed, Object: MethodParam, Criticality: NonBreaking},
+		{Path: []string{"methods", "check.Added"}, Type: Added, Object: Method, Criticality: NonBreaking},
+	}
+
+	rules.apply(changes)
+
+	want := []CriticalityLevel{NonBreaking, Dangerous, Breaking, NonBreaking}
+	for i, c := range changes {
+		if c.Criticality != want[i] {
+			t.Errorf("change %v criticality = %v, want %v", c.Path, c.Criticality, want[i])
+		}
+	}
+
+	if err := (Rules{{Object: Method}}).Validate(); err == nil {
+		t.Errorf("rule without set must be invalid")
+	}
+}
+
+func TestRules_when(t *testing.T) {
+	rules := Rules{
+		{When: "object == 'METHOD_PARAM' && path contains 'internal'", Set: NonBreaking},
+		{When: "method startsWith 'billing.' && criticality == 'BREAKING' && owner in ['payments', 'billing']", Set: Dangerous},
+	}
+	if err := rules.Validate(); err != nil {
+		t.Fatalf("validate error: %s", err)
+	}
+
+	changes := []Change{
+		{Path: []string{"methods", "user.Get", "params", "internalId"}, Type: Removed, Object: MethodParam, Criticality: Breaking},
+		{Path: []string{"methods", "user.Get", "params", "id"}, Type: Removed, Object: MethodParam, Criticality: Breaking},
+		{Path: []string{"methods", "billing.Pay"}, Type: Removed, Object: Method, Criticality: Breaking, Owner: "payments"},
+		{Path: []string{"methods", "billing.Refund"}, Type: Removed, Object: Method, Criticality: Breaking, Owner: "support"},
+	}
+
+	rules.apply(changes)
+
+	want := []CriticalityLevel{NonBreaking, Breaking, Dangerous, Breaking}
+	for i, c := range changes {
+		if c.Criticality != want[i] {
+			t.Errorf("change %v criticality = %v, want %v", c.Path, c.Criticality, want[i])
+		}
+	}
+
+	for _, when := range []string{"object ==", "unknown == 'x'", "path"} {
+		if err := (Rules{{When: when, Set: NonBreaking}}).Validate(); err == nil {
+			t.Errorf("rule with when %q must be invalid", when)
+		}
+	}
+}
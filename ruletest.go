@@ -0,0 +1,121 @@
+package rpcdiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+// RuleTest is a result of comparing fixture pair against expected changes
+type RuleTest struct {
+	Name       string   `json:"name"`
+	Missing    []Change `json:"missing,omitempty"`    // expected changes which are not found
+	Unexpected []Change `json:"unexpected,omitempty"` // found changes which are not expected
+	Error      string   `json:"error,omitempty"`
+}
+
+// Passed checks that changes match expected ones
+func (rt RuleTest) Passed() bool {
+	return rt.Error == "" && len(rt.Missing) == 0 && len(rt.Unexpected) == 0
+}
+
+// RunRuleTests runs every fixture directory with old.json, new.json and expected.json in dir.
+// Changes are matched by path, type, object and criticality.
+func RunRuleTests(dir string, options Options) ([]RuleTest, error) {
+	fixtures, err := filepath.Glob(filepath.Join(dir, "*", "expected.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(fixtures) == 0 {
+		return nil, fmt.Errorf("no fixtures found in %s", dir)
+	}
+
+	result := make([]RuleTest, 0, len(fixtures))
+	for _, expected := range fixtures {
+		fixture := filepath.Dir(expected)
+		rt := RuleTest{Name: filepath.Base(fixture)}
+
+		if err := runRuleTest(&rt, fixture, options); err != nil {
+			rt.Error = err.Error()
+		}
+
+		result = append(result, rt)
+	}
+
+	return result, nil
+}
+
+func runRuleTest(rt *RuleTest, fixture string, options Options) error {
+	data, err := ioutil.ReadFile(filepath.Join(fixture, "expected.json"))
+	if err != nil {
+		return err
+	}
+
+	var expected []Change
+	if err := json.Unmarshal(data, &expected); err != nil {
+		return fmt.Errorf("parse expected changes error: %w", err)
+	}
+
+	diff, err := NewDiff(filepath.Join(fixture, "old.json"), filepath.Join(fixture, "new.json"), options)
+	if err != nil {
+		return err
+	}
+
+	found := map[string]int{}
+	for _, c := range diff.Changes {
+		found[changeKey(c)]++
+	}
+
+	for _, c := range expected {
+		key := changeKey(c)
+		if found[key] == 0 {
+			rt.Missing = append(rt.Missing, c)
+			continue
+		}
+		found[key]--
+	}
+
+	for _, c := range diff.Changes {
+		if key := changeKey(c); found[key] > 0 {
+			rt.Unexpected = append(rt.Unexpected, c)
+			found[key]--
+		}
+	}
+
+	return nil
+}
+
+// changeKey identifies change by path, type, object and criticality
+func changeKey(c Change) string {
+	return fmt.Sprintf("%s %s %s %s", strings.Join(c.Path, "."), c.Type, c.Object, string(c.Criticality))
+}
+
+// RuleTestsReport renders failed fixtures and totals
+func RuleTestsReport(tests []RuleTest) string {
+	buf := strings.Builder{}
+	var failed int
+	for _, rt := range tests {
+		if rt.Passed() {
+			continue
+		}
+
+		failed++
+		fmt.Fprintf(&buf, "FAIL %s\n", rt.Name)
+		if rt.Error != "" {
+			fmt.Fprintf(&buf, "  error: %s\n", rt.Error)
+		}
+		for _, c := range rt.Missing {
+			fmt.Fprintf(&buf, "  - missing %s\n", changeKey(c))
+		}
+		for _, c := range rt.Unexpected {
+			fmt.Fprintf(&buf, "  + unexpected %s\n", changeKey(c))
+		}
+	}
+
+	fmt.Fprintf(&buf, "%d passed, %d failed\n", len(tests)-failed, failed)
+
+	return buf.String()
+}
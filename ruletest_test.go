@@ -0,0 +1,34 @@
+package rpcdiff
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRunRuleTests(t *testing.T) {
+	options := Options{Rules: Rules{{PathContains: "internal", Set: NonBreaking}}}
+
+	tests, err := RunRuleTests("testdata/ruletests", options)
+	if err != nil {
+		t.Fatalf("run rule tests error: %s", err)
+	}
+
+	for _, rt := range tests {
+		if !rt.Passed() {
+			t.Errorf("fixture %s failed:\n%s", rt.Name, RuleTestsReport([]RuleTest{rt}))
+		}
+	}
+
+	// without rules internal param is breaking
+	tests, err = RunRuleTests("testdata/ruletests", Options{})
+	if err != nil {
+		t.Fatalf("run rule tests error: %s", err)
+	}
+
+	report := RuleTestsReport(tests)
+	for _, want := range []string{"FAIL internal-param", "- missing methods.internal.Sync.params.force ADDED METHOD_PARAM NON_BREAKING", "+ unexpected methods.internal.Sync.params.force ADDED METHOD_PARAM BREAKING", "1 passed, 1 failed"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("RuleTestsReport() doesn't contain %q:\n%s", want, report)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package rpcdiff
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// semanticSetKeys hold arrays which order has no meaning for comparison
+var semanticSetKeys = map[string]bool{
+	"methods":  true,
+	"required": true,
+	"enum":     true,
+}
+
+// semanticHasher hashes raw json document ignoring formatting, order of methods, required and enum values
+// and whether schemas are referenced or inlined. Referenced values are hashed once, recursive references by their path.
+type semanticHasher struct {
+	doc   interface{}
+	memo  map[string]string // hashes of references
+	stack map[string]bool   // references being hashed
+}
+
+// semanticDigest returns digest of document, semantically identical documents have the same digest
+func semanticDigest(data []byte) (string, error) {
+	doc, err := decodeJSON(data)
+	if err != nil {
+		return "", err
+	}
+
+	h := &semanticHasher{doc: doc, memo: map[string]string{}, stack: map[string]bool{}}
+
+	return h.hash(doc, "", false), nil
+}
+
+// semanticallyEqual checks that documents have the same semantic digest, invalid documents are never equal
+func semanticallyEqual(oldJSON, newJSON []byte) bool {
+	oldDigest, err := semanticDigest(oldJSON)
+	if err != nil {
+		return false
+	}
+
+	newDigest, err := semanticDigest(newJSON)
+	if err != nil {
+		return false
+	}
+
+	return oldDigest == newDigest
+}
+
+// hash returns digest of value of key, verbatim values are user data which is hashed as is
+func (h *semanticHasher) hash(v interface{}, key string, verbatim bool) string {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		if ref, ok := val["$ref"].(string); ok && !verbatim && strings.HasPrefix(ref, "#/") {
+			return h.ref(ref)
+		}
+
+		keys := sortedKeys(val)
+		parts := make([]string, 0, len(keys))
+		for _, k := range keys {
+			parts = append(parts, strconv.Quote(k)+":"+h.hash(val[k], k, verbatim || verbatimKeys[k]))
+		}
+
+		return digest([]byte("{" + strings.Join(parts, ",") + "}"))
+	case []interface{}:
+		set := semanticSetKeys[key] && (!verbatim || key == "enum")
+		if key == "enum" {
+			verbatim = true
+		}
+
+		parts := make([]string, 0, len(val))
+		for _, item := range val {
+			parts = append(parts, h.hash(item, "", verbatim))
+		}
+
+		if set {
+			sort.Strings(parts)
+		}
+
+		return digest([]byte("[" + strings.Join(parts, ",") + "]"))
+	default:
+		data, _ := json.Marshal(val)
+		return string(data)
+	}
+}
+
+// ref returns digest of referenced value, unresolved and recursive references are hashed by their path
+func (h *semanticHasher) ref(ref string) string {
+	if result, ok := h.memo[ref]; ok {
+		return result
+	}
+
+	if h.stack[ref] {
+		return digest([]byte("$ref:" + ref))
+	}
+
+	target, err := resolvePointer(h.doc, strings.TrimPrefix(ref, "#"))
+	if err != nil {
+		return digest([]byte("$ref:" + ref))
+	}
+
+	h.stack[ref] = true
+	result := h.hash(target, "", false)
+	delete(h.stack, ref)
+
+	h.memo[ref] = result
+
+	return result
+}
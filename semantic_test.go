@@ -0,0 +1,77 @@
+package rpcdiff
+
+import (
+	"testing"
+)
+
+func Test_semanticallyEqual(t *testing.T) {
+	base := `{"openrpc":"1.2.6","info":{"title":"api","version":"1.0.0"},"methods":[
+		{"name":"a","params":[{"name":"x","schema":{"$ref":"#/components/schemas/X"}}],"result":{"name":"r","schema":{"type":"string","enum":["on","off"]}}},
+		{"name":"b","params":[],"result":{"name":"r","schema":{"type":"boolean"}},"examples":[{"name":"e","params":[],"result":{"name":"r","value":["a","b"]}}]}],
+		"components":{"schemas":{"X":{"type":"object","required":["id","name"],"properties":{"id":{"type":"integer"},"name":{"type":"string"}}}}}}`
+
+	tests := []struct {
+		name string
+		new  string
+		want bool
+	}{
+		{name: "same", new: base, want: true},
+		{
+			name: "reordered and reformatted",
+			new: `{"methods":[{"name":"b","result":{"schema":{"type":"boolean"},"name":"r"},"params":[],"examples":[{"name":"e","params":[],"result":{"name":"r","value":["a","b"]}}]},
+				{"name":"a","params":[{"name":"x","schema":{"$ref":"#/components/schemas/X"}}],"result":{"name":"r","schema":{"enum":["off","on"],"type":"string"}}}],
+				"openrpc":"1.2.6","info":{"version":"1.0.0","title":"api"},
+				"components":{"schemas":{"X":{"required":["name","id"],"type":"object","properties":{"name":{"type":"string"},"id":{"type":"integer"}}}}}}`,
+			want: true,
+		},
+		{
+			name: "inlined ref",
+			new: `{"openrpc":"1.2.6","info":{"title":"api","version":"1.0.0"},"methods":[
+				{"name":"a","params":[{"name":"x","schema":{"type":"object","required":["id","name"],"properties":{"id":{"type":"integer"},"name":{"type":"string"}}}}],"result":{"name":"r","schema":{"type":"string","enum":["on","off"]}}},
+				{"name":"b","params":[],"result":{"name":"r","schema":{"type":"boolean"}},"examples":[{"name":"e","params":[],"result":{"name":"r","value":["a","b"]}}]}],
+				"components":{"schemas":{"X":{"type":"object","required":["id","name"],"properties":{"id":{"type":"integer"},"name":{"type":"string"}}}}}}`,
+			want: true,
+		},
+		{
+			name: "reordered example value",
+			new:  `{"openrpc":"1.2.6","info":{"title":"api","version":"1.0.0"},"methods":[{"name":"a","params":[{"name":"x","schema":{"$ref":"#/components/schemas/X"}}],"result":{"name":"r","schema":{"type":"string","enum":["on","off"]}}},{"name":"b","params":[],"result":{"name":"r","schema":{"type":"boolean"}},"examples":[{"name":"e","params":[],"result":{"name":"r","value":["b","a"]}}]}],"components":{"schemas":{"X":{"type":"object","required":["id","name"],"properties":{"id":{"type":"integer"},"name":{"type":"string"}}}}}}`,
+		},
+		{
+			name: "changed type",
+			new:  `{"openrpc":"1.2.6","info":{"title":"api","version":"1.0.0"},"methods":[{"name":"a","params":[{"name":"x","schema":{"$ref":"#/components/schemas/X"}}],"result":{"name":"r","schema":{"type":"string","enum":["on","off"]}}},{"name":"b","params":[],"result":{"name":"r","schema":{"type":"boolean"}},"examples":[{"name":"e","params":[],"result":{"name":"r","value":["a","b"]}}]}],"components":{"schemas":{"X":{"type":"object","required":["id","name"],"properties":{"id":{"type":"string"},"name":{"type":"string"}}}}}}`,
+		},
+		{name: "invalid", new: `{`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := semanticallyEqual([]byte(base), []byte(tt.new)); got != tt.want {
+				t.Errorf("semanticallyEqual() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_semanticDigest_recursive(t *testing.T) {
+	schema := []byte(`{"components":{"schemas":{"Node":{"type":"object","properties":{"next":{"$ref":"#/components/schemas/Node"}}}}},
+		"methods":[{"name":"a","params":[],"result":{"name":"r","schema":{"$ref":"#/components/schemas/Node"}}}]}`)
+
+	if _, err := semanticDigest(schema); err != nil {
+		t.Fatalf("semanticDigest() error: %s", err)
+	}
+}
+
+func TestNewDiffBytes_semanticallyEqual(t *testing.T) {
+	old := []byte(`{"openrpc":"1.2.6","info":{"title":"api","version":"1.0.0"},"methods":[{"name":"a","params":[{"name":"x","schema":{"$ref":"#/components/schemas/X"}}],"result":{"name":"r","schema":{"type":"boolean"}}}],
+		"components":{"schemas":{"X":{"type":"string"}}}}`)
+	new := []byte(`{"openrpc":"1.2.6","info":{"title":"api","version":"1.0.0"},"methods":[{"name":"a","params":[{"name":"x","schema":{"type":"string"}}],"result":{"name":"r","schema":{"type":"boolean"}}}],
+		"components":{"schemas":{"X":{"type":"string"}}}}`)
+
+	diff, err := NewDiffBytes(old, new, Options{})
+	if err != nil {
+		t.Fatalf("NewDiffBytes() error: %s", err)
+	}
+
+	if len(diff.Changes) != 0 {
+		t.Errorf("NewDiffBytes() = %v, want no changes", diff.String())
+	}
+}
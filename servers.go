@@ -0,0 +1,208 @@
+package rpcdiff
+
+import (
+	"fmt"
+	"path"
+	"sort"
+
+	openrpc "github.com/vmkteam/meta-schema/v2"
+)
+
+// compareServers compares document or method servers at path matched by url, then by name for changed urls.
+// Removed servers and changed urls or variables are dangerous, meta fields are compared only with ShowMeta.
+func compareServers(options Options, old, new []openrpc.ServerObject, path []string) []Change {
+	old, new = ignoreServers(old, options.IgnoreServers), ignoreServers(new, options.IgnoreServers)
+
+	var changes []Change
+	matched := map[int]bool{} // new servers
+
+	// match by url
+	var unmatched []openrpc.ServerObject
+	for _, o := range old {
+		i := findServer(new, matched, func(s openrpc.ServerObject) bool { return s.Url == o.Url })
+		if i == -1 {
+			unmatched = append(unmatched, o)
+			continue
+		}
+
+		matched[i] = true
+		changes = append(changes, compareServer(options, o, new[i], path)...)
+	}
+
+	// match by name
+	for _, o := range unmatched {
+		i := -1
+		if o.Name != "" {
+			i = findServer(new, matched, func(s openrpc.ServerObject) bool { return s.Name == o.Name })
+		}
+
+		if i == -1 {
+			changes = append(changes, *compare(o, nil, appendPath(path, o.Url), Dangerous))
+			continue
+		}
+
+		matched[i] = true
+		changes = append(changes, *compare(o.Url, new[i].Url, appendPath(path, o.Url, "url"), Dangerous))
+		changes = append(changes, compareServer(options, o, new[i], path)...)
+	}
+
+	for i, n := range new {
+		if !matched[i] {
+			changes = append(changes, *compare(nil, n, appendPath(path, n.Url), NonBreaking))
+		}
+	}
+
+	return changes
+}
+
+// compareServer compares variables and meta fields of matched servers
+func compareServer(options Options, old, new openrpc.ServerObject, path []string) []Change {
+	var changes []Change
+	p := appendPath(path, old.Url)
+
+	if options.ShowMeta {
+		for _, field := range []struct {
+			name     string
+			old, new string
+		}{{"name", old.Name, new.Name}, {"summary", old.Summary, new.Summary}, {"description", old.Description, new.Description}} {
+			if change := compare(field.old, field.new, appendPath(p, field.name), NonBreaking); change != nil {
+				changes = append(changes, *change)
+			}
+		}
+	}
+
+	for _, name := range variableNames(old.Variables) {
+		ov := old.Variables[name]
+		nv, ok := new.Variables[name]
+		if !ok {
+			changes = append(changes, *compare(ov, nil, appendPath(p, "variables", name), Dangerous))
+			continue
+		}
+
+		vp := appendPath(p, "variables", name)
+		if change := compare(ov.Default, nv.Default, appendPath(vp, "default"), Dangerous); change != nil {
+			changes = append(changes, *change)
+		}
+
+		if !stringsSubset(ov.Enum, nv.Enum) || !stringsSubset(nv.Enum, ov.Enum) {
+			level := NonBreaking
+			if !stringsSubset(ov.Enum, nv.Enum) {
+				level = Dangerous
+			}
+			changes = append(changes, *compare(ov.Enum, nv.Enum, appendPath(vp, "enum"), level))
+		}
+
+		if options.ShowMeta {
+			if change := compare(ov.Description, nv.Description, appendPath(vp, "description"), NonBreaking); change != nil {
+				changes = append(changes, *change)
+			}
+		}
+	}
+
+	for _, name := range variableNames(new.Variables) {
+		if _, ok := old.Variables[name]; !ok {
+			changes = append(changes, *compare(nil, new.Variables[name], appendPath(p, "variables", name), NonBreaking))
+		}
+	}
+
+	return changes
+}
+
+// serverMessage renders change of document or method servers
+func serverMessage(c Change, oldJSON, newJSON string) string {
+	server := fmt.Sprintf(`server "%s"`, after(c.Path, "servers"))
+	if method := after(c.Path, "methods"); method != "" {
+		server += fmt.Sprintf(` of method "%s"`, method)
+	}
+
+	// path relative to servers
+	rel := c.Path
+	for i, elem := range c.Path {
+		if elem == "servers" {
+			rel = c.Path[i:]
+			break
+		}
+	}
+
+	varName := after(c.Path, "variables")
+	switch {
+	case len(rel) == 2 && c.Type == Added:
+		return "Added " + server
+	case len(rel) == 2 && c.Type == Removed:
+		return "Removed " + server
+	case last(c.Path) == "url":
+		return fmt.Sprintf(`Changed url of %s from %v to %v`, server, oldJSON, newJSON)
+	case varName != "" && len(rel) == 4 && c.Type == Added:
+		return fmt.Sprintf(`Added variable "%s" to %s`, varName, server)
+	case varName != "" && len(rel) == 4 && c.Type == Removed:
+		return fmt.Sprintf(`Removed variable "%s" from %s`, varName, server)
+	case varName != "":
+		return fmt.Sprintf(`Changed "%s" of variable "%s" of %s from %v to %v`, last(c.Path), varName, server, oldJSON, newJSON)
+	}
+
+	return fmt.Sprintf(`Changed "%s" of %s from %v to %v`, last(c.Path), server, oldJSON, newJSON)
+}
+
+// variableNames returns sorted names of server variables
+func variableNames(variables map[string]openrpc.ServerObjectVariable) []string {
+	result := make([]string, 0, len(variables))
+	for name := range variables {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+
+	return result
+}
+
+// findServer returns index of the first not matched server satisfying fn or -1
+func findServer(servers []openrpc.ServerObject, matched map[int]bool, fn func(openrpc.ServerObject) bool) int {
+	for i, s := range servers {
+		if !matched[i] && fn(s) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// ignoreServers drops servers which url or name matches any of glob patterns
+func ignoreServers(servers []openrpc.ServerObject, patterns []string) []openrpc.ServerObject {
+	if len(patterns) == 0 {
+		return servers
+	}
+
+	var result []openrpc.ServerObject
+	for _, s := range servers {
+		ignored := false
+		for _, pattern := range patterns {
+			byURL, _ := path.Match(pattern, s.Url)
+			byName, _ := path.Match(pattern, s.Name)
+			if byURL || byName {
+				ignored = true
+				break
+			}
+		}
+
+		if !ignored {
+			result = append(result, s)
+		}
+	}
+
+	return result
+}
+
+// stringsSubset checks that every value of a is in b
+func stringsSubset(a, b []string) bool {
+	set := map[string]bool{}
+	for _, s := range b {
+		set[s] = true
+	}
+
+	for _, s := range a {
+		if !set[s] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,124 @@
+package rpcdiff
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	openrpc "github.com/vmkteam/meta-schema/v2"
+)
+
+func TestCompareServers(t *testing.T) {
+	prod := openrpc.ServerObject{
+		Name: "prod",
+		Url:  "https://{region}.example.com/rpc",
+		Variables: map[string]openrpc.ServerObjectVariable{
+			"region": {Default: "eu", Enum: []string{"eu", "us"}},
+		},
+	}
+	staging := openrpc.ServerObject{Name: "staging", Url: "https://staging.example.com/rpc"}
+
+	withVariable := func(s openrpc.ServerObject, v openrpc.ServerObjectVariable) openrpc.ServerObject {
+		s.Variables = map[string]openrpc.ServerObjectVariable{"region": v}
+		return s
+	}
+	moved := prod
+	moved.Url = "https://{region}.example.org/rpc"
+	renamed := prod
+	withoutVariables := prod
+	withoutVariables.Variables = nil
+	renamed.Description = "production"
+
+	tests := []struct {
+		name     string
+		options  Options
+		old, new []openrpc.ServerObject
+		want     []string
+	}{
+		{"equal", Options{}, []openrpc.ServerObject{prod}, []openrpc.ServerObject{prod}, nil},
+		{"removed", Options{}, []openrpc.ServerObject{prod, staging}, []openrpc.ServerObject{prod},
+			[]string{`DANGEROUS: Removed server "https://staging.example.com/rpc"`}},
+		{"added", Options{}, []openrpc.ServerObject{prod}, []openrpc.ServerObject{prod, staging},
+			[]string{`NON_BREAKING: Added server "https://staging.example.com/rpc"`}},
+		{"url changed", Options{}, []openrpc.ServerObject{prod}, []openrpc.ServerObject{moved},
+			[]string{`DANGEROUS: Changed url of server "https://{region}.example.com/rpc" from "https://{region}.example.com/rpc" to "https://{region}.example.org/rpc"`}},
+		{"default changed", Options{}, []openrpc.ServerObject{prod},
+			[]openrpc.ServerObject{withVariable(prod, openrpc.ServerObjectVariable{Default: "us", Enum: []string{"eu", "us"}})},
+			[]string{`DANGEROUS: Changed "default" of variable "region" of server "https://{region}.example.com/rpc" from "eu" to "us"`}},
+		{"enum shrunk", Options{}, []openrpc.ServerObject{prod},
+			[]openrpc.ServerObject{withVariable(prod, openrpc.ServerObjectVariable{Default: "eu", Enum: []string{"eu"}})},
+			[]string{`DANGEROUS: Changed "enum" of variable "region" of server "https://{region}.example.com/rpc" from ["eu","us"] to ["eu"]`}},
+		{"enum grown", Options{}, []openrpc.ServerObject{prod},
+			[]openrpc.ServerObject{withVariable(prod, openrpc.ServerObjectVariable{Default: "eu", Enum: []string{"eu", "us", "asia"}})},
+			[]string{`NON_BREAKING: Changed "enum" of variable "region" of server "https://{region}.example.com/rpc" from ["eu","us"] to ["eu","us","asia"]`}},
+		{"variable removed", Options{}, []openrpc.ServerObject{prod}, []openrpc.ServerObject{withoutVariables},
+			[]string{`DANGEROUS: Removed variable "region" from server "https://{region}.example.com/rpc"`}},
+		{"ignored", Options{IgnoreServers: []string{"staging"}}, []openrpc.ServerObject{prod, staging}, []openrpc.ServerObject{prod}, nil},
+		{"ignored by url", Options{IgnoreServers: []string{"https://staging.example.com/*"}}, []openrpc.ServerObject{prod}, []openrpc.ServerObject{prod, staging}, nil},
+		{"meta hidden", Options{}, []openrpc.ServerObject{prod}, []openrpc.ServerObject{renamed}, nil},
+		{"meta", Options{ShowMeta: true}, []openrpc.ServerObject{prod}, []openrpc.ServerObject{renamed},
+			[]string{`NON_BREAKING: Changed "description" of server "https://{region}.example.com/rpc" from "" to "production"`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes := compareServers(tt.options, tt.old, tt.new, []string{"servers"})
+			var got []string
+			for _, c := range changes {
+				got = append(got, string(c.Criticality)+": "+c.String())
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("compareServers() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("compareServers()[%d] = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestNewDiffBytes_methodServers(t *testing.T) {
+	schema := `{
+		"openrpc": "1.2.6",
+		"info": {"title": "test", "version": "v0.0.0"},
+		"methods": [
+			{"name": "file.Upload", "params": [], "result": {"name": "result", "schema": {"type": "boolean"}}, "servers": %s}
+		]
+	}`
+	old := fmt.Sprintf(schema, `[{"name": "upload", "url": "https://upload.example.com/rpc"}, {"name": "cdn", "url": "https://cdn.example.com/rpc"}]`)
+	new := fmt.Sprintf(schema, `[{"name": "upload", "url": "https://upload.example.com/rpc"}]`)
+
+	tests := []struct {
+		name    string
+		options Options
+		want    []string
+	}{
+		{"compared", Options{}, []string{`DANGEROUS: Removed server "https://cdn.example.com/rpc" of method "file.Upload"`}},
+		{"ignored", Options{IgnoreMethodServers: true}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff, err := NewDiffBytes([]byte(old), []byte(new), tt.options)
+			if err != nil {
+				t.Fatalf("NewDiffBytes() error: %s", err)
+			}
+
+			var got []string
+			for _, c := range diff.Changes {
+				got = append(got, string(c.Criticality)+": "+c.String())
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewDiffBytes() = %v, want %v", got, tt.want)
+			}
+
+			if len(diff.Changes) > 0 && diff.Changes[0].Object != MethodServers {
+				t.Errorf("change object = %v, want %v", diff.Changes[0].Object, MethodServers)
+			}
+		})
+	}
+}
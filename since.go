@@ -0,0 +1,69 @@
+package rpcdiff
+
+import (
+	"fmt"
+	"time"
+)
+
+// sinceRecord returns the latest record of version or the latest record created before since if version is empty
+func sinceRecord(records []Record, since time.Time, version string) (*Record, error) {
+	var found *Record
+	for i, r := range records {
+		switch {
+		case version != "" && r.Version == version:
+			found = &records[i]
+		case version == "" && !r.CreatedAt.After(since):
+			found = &records[i]
+		}
+	}
+
+	switch {
+	case found != nil:
+		return found, nil
+	case version != "":
+		return nil, fmt.Errorf("version %s is not found in history store", version)
+	default:
+		return nil, fmt.Errorf("no version before %s in history store", since.Format("2006-01-02"))
+	}
+}
+
+// newDiffSince compares new schema with version of service from history store, resolved by sinceRecord
+func newDiffSince(store Store, service string, since time.Time, version, new string, timeout time.Duration, options Options) (*Diff, error) {
+	records, err := store.List(service)
+	if err != nil {
+		return nil, err
+	}
+
+	old, err := sinceRecord(records, since, version)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", service, err)
+	}
+
+	var newBytes []byte
+	if timeout > 0 {
+		newBytes, err = waitSchema(new, timeout, time.Second)
+	} else {
+		newBytes, err = ReadFileOrURL(new)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read new schema error: %w", err)
+	}
+
+	return newDiffSources(service+"@"+old.Version, new, old.Schema, newBytes, options)
+}
+
+// DiffSince compares new schema with old one taken from history store of config by date or version
+func DiffSince(cfg *Config, service, since, version, new string, timeout time.Duration, opts Options) (*Diff, error) {
+	sinceTime, err := ParseSince(since)
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := OpenConfigStore(cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer store.Close()
+
+	return newDiffSince(store, service, sinceTime, version, new, timeout, opts)
+}
@@ -0,0 +1,67 @@
+package rpcdiff
+
+import (
+	"io/ioutil"
+	"testing"
+	"time"
+)
+
+func Test_newDiffSince(t *testing.T) {
+	oldJSON, err := ioutil.ReadFile("testdata/openrpc_old.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newJSON, err := ioutil.ReadFile("testdata/openrpc_new.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := OpenStore(StorageConfig{}, t.TempDir())
+	if err != nil {
+		t.Fatalf("OpenStore() error: %s", err)
+	}
+
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, r := range []Record{
+		{Service: "check", Version: "1.0.0", Digest: digest(oldJSON), CreatedAt: created, Schema: oldJSON},
+		{Service: "check", Version: "1.1.0", Digest: digest(newJSON), CreatedAt: created.AddDate(0, 1, 0), Schema: newJSON},
+	} {
+		if err := store.Put(r); err != nil {
+			t.Fatalf("Put() error: %s", err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		since   time.Time
+		version string
+		source  string
+		changes bool
+	}{
+		{name: "should take version current at date", since: created.AddDate(0, 0, 10), source: "check@1.0.0", changes: true},
+		{name: "should take the latest version", since: created.AddDate(1, 0, 0), source: "check@1.1.0"},
+		{name: "should take version", version: "1.0.0", source: "check@1.0.0", changes: true},
+		{name: "should fail without version before date", since: created.AddDate(0, 0, -1)},
+		{name: "should fail on unknown version", version: "0.1.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff, err := newDiffSince(store, "check", tt.since, tt.version, "testdata/openrpc_new.json", 0, Options{})
+			if tt.source == "" {
+				if err == nil {
+					t.Fatalf("error is expected")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("newDiffSince() error: %s", err)
+			}
+
+			if diff.Old.Source != tt.source || (len(diff.Changes) > 0) != tt.changes {
+				t.Errorf("old = %v, changes = %v, want %v, %v", diff.Old.Source, len(diff.Changes), tt.source, tt.changes)
+			}
+		})
+	}
+}
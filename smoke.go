@@ -0,0 +1,100 @@
+package rpcdiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	openrpc "github.com/vmkteam/meta-schema/v2"
+)
+
+// smokeRequest is a json-rpc request
+type smokeRequest struct {
+	JSONRPC string      `json:"jsonrpc"`
+	ID      int         `json:"id"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params"`
+}
+
+// touchedMethods returns sorted names of methods with changes
+func touchedMethods(diff *Diff) []string {
+	seen := map[string]bool{}
+	for _, c := range diff.Changes {
+		if name := after(c.Path, "methods"); name != "" {
+			seen[name] = true
+		}
+	}
+
+	result := make([]string, 0, len(seen))
+	for name := range seen {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+
+	return result
+}
+
+// newSmokeRequest synthesizes request of method, params are passed by position only if method requires it
+func newSmokeRequest(method *openrpc.MethodObject, doc *openrpc.OpenrpcDocument) smokeRequest {
+	byName := map[string]interface{}{}
+	var byPosition []interface{}
+
+	for _, param := range method.Params {
+		cd := param.ContentDescriptorObject
+		if param.ReferenceObject != nil {
+			cd = resolveContentDescriptor(param.ReferenceObject.Ref, doc)
+		}
+
+		if cd == nil {
+			continue
+		}
+
+		value := synthesizeValue(getSchemaObject(cd.Schema), doc)
+		byName[cd.Name] = value
+		byPosition = append(byPosition, value)
+	}
+
+	req := smokeRequest{JSONRPC: "2.0", ID: 1, Method: method.Name, Params: byName}
+	if method.ParamStructure == openrpc.MethodObjectParamStructureEnum0 {
+		req.Params = byPosition
+	}
+
+	return req
+}
+
+// WriteSmokeRequests writes request of every method touched by diff and present in new schema to dir, paths of files are returned
+func WriteSmokeRequests(dir string, newJSON []byte, diff *Diff) ([]string, error) {
+	doc, err := parseDocument(newJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	var result []string
+	for _, name := range touchedMethods(diff) {
+		method := findMethod(doc, name)
+		if method == nil {
+			continue
+		}
+
+		data, err := json.MarshalIndent(newSmokeRequest(method, doc), "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("method %s: %w", name, err)
+		}
+
+		p := filepath.Join(dir, name+".json")
+		if err := ioutil.WriteFile(p, append(data, '\n'), 0644); err != nil {
+			return nil, err
+		}
+
+		result = append(result, p)
+	}
+
+	return result, nil
+}
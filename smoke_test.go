@@ -0,0 +1,50 @@
+package rpcdiff
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteSmokeRequests(t *testing.T) {
+	diff, err := NewDiff("testdata/openrpc_old.json", "testdata/openrpc_new.json", Options{})
+	if err != nil {
+		t.Fatalf("new diff error: %s", err)
+	}
+
+	newJSON, err := ioutil.ReadFile("testdata/openrpc_new.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	files, err := WriteSmokeRequests(dir, newJSON, diff)
+	if err != nil {
+		t.Fatalf("write smoke requests error: %s", err)
+	}
+
+	for _, f := range files {
+		if filepath.Base(f) == "check.RemovedMethod.json" || filepath.Base(f) == "check.UntouchedMethod.json" {
+			t.Errorf("unexpected request %s", f)
+		}
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, "check.AddRequiredParam.json"))
+	if err != nil {
+		t.Fatalf("read request error: %s", err)
+	}
+
+	want := `{
+  "jsonrpc": "2.0",
+  "id": 1,
+  "method": "check.AddRequiredParam",
+  "params": {
+    "param1": 1,
+    "param2": 1
+  }
+}
+`
+	if string(data) != want {
+		t.Errorf("request = %s, want %s", data, want)
+	}
+}
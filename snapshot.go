@@ -0,0 +1,95 @@
+package rpcdiff
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// snapshotPath returns path of schema snapshot in dir, name defaults to base name of schema path or url
+func snapshotPath(dir, schema, name string) string {
+	if name == "" {
+		name = filepath.Base(schema)
+		if u, err := url.ParseRequestURI(schema); err == nil && u.Host != "" {
+			name = path.Base(u.Path)
+		}
+	}
+
+	if filepath.Ext(name) != ".json" {
+		name += ".json"
+	}
+
+	return filepath.Join(dir, name)
+}
+
+// decodeJSON decodes json keeping numbers as is
+func decodeJSON(data []byte) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// CanonicalJSON re-encodes json with sorted keys and indentation
+func CanonicalJSON(data []byte) ([]byte, error) {
+	v, err := decodeJSON(data)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append(result, '\n'), nil
+}
+
+// SaveSnapshot stores canonical snapshot of schema and returns its path
+func SaveSnapshot(dir, schema, name string) (string, error) {
+	data, err := ReadFileOrURL(schema)
+	if err != nil {
+		return "", fmt.Errorf("read schema error: %w", err)
+	}
+
+	if _, err := parseDocument(data); err != nil {
+		return "", fmt.Errorf("parse schema error: %w", err)
+	}
+
+	if data, err = CanonicalJSON(data); err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+
+	p := snapshotPath(dir, schema, name)
+
+	return p, ioutil.WriteFile(p, data, 0644)
+}
+
+// CheckSnapshot compares schema with its saved snapshot
+func CheckSnapshot(dir, schema, name string, options Options) (*Diff, error) {
+	snapshot, err := ioutil.ReadFile(snapshotPath(dir, schema, name))
+	if err != nil {
+		return nil, fmt.Errorf("read snapshot error: %w", err)
+	}
+
+	data, err := ReadFileOrURL(schema)
+	if err != nil {
+		return nil, fmt.Errorf("read schema error: %w", err)
+	}
+
+	return NewDiffBytes(snapshot, data, options)
+}
@@ -0,0 +1,56 @@
+package rpcdiff
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestSnapshot(t *testing.T) {
+	dir := t.TempDir()
+
+	p, err := SaveSnapshot(dir, "testdata/openrpc_old.json", "service")
+	if err != nil {
+		t.Fatalf("save snapshot error: %s", err)
+	}
+
+	if p != filepath.Join(dir, "service.json") {
+		t.Errorf("SaveSnapshot() = %v, want %v", p, filepath.Join(dir, "service.json"))
+	}
+
+	diff, err := CheckSnapshot(dir, "testdata/openrpc_old.json", "service", Options{})
+	if err != nil {
+		t.Fatalf("check snapshot error: %s", err)
+	}
+
+	if len(diff.Changes) != 0 {
+		t.Errorf("changes of the same schema = %v, wanted 0", len(diff.Changes))
+	}
+
+	diff, err = CheckSnapshot(dir, "testdata/openrpc_new.json", "service", Options{})
+	if err != nil {
+		t.Fatalf("check snapshot error: %s", err)
+	}
+
+	if diff.Criticality != Breaking {
+		t.Errorf("Criticality = %v, want %v", diff.Criticality, Breaking)
+	}
+}
+
+func Test_snapshotPath(t *testing.T) {
+	tests := []struct {
+		schema string
+		name   string
+		want   string
+	}{
+		{schema: "api/openrpc.json", want: "snapshots/openrpc.json"},
+		{schema: "https://api.example.com/rpc/doc?schema", want: "snapshots/doc.json"},
+		{schema: "api/openrpc.json", name: "users", want: "snapshots/users.json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := snapshotPath("snapshots", tt.schema, tt.name); got != tt.want {
+				t.Errorf("snapshotPath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package rpcdiff
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// httpClient is shared by schema fetching and uploads
+var httpClient = &http.Client{Timeout: time.Minute}
+
+// httpAttempts is a number of attempts of http request, httpRetryBackoff is a pause before the second one, doubled for next ones
+var (
+	httpAttempts     = 3
+	httpRetryBackoff = 500 * time.Millisecond
+)
+
+// httpDo sends request built by newRequest and returns response body.
+// Network errors and 5xx responses are retried, other 4xx-5xx responses are errors.
+func httpDo(newRequest func() (*http.Request, error)) ([]byte, error) {
+	backoff := httpRetryBackoff
+	for attempt := 1; ; attempt++ {
+		body, retry, err := httpAttempt(newRequest)
+		if err == nil || !retry || attempt == httpAttempts {
+			return body, err
+		}
+
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// httpAttempt sends request once, retry is true for network errors and 5xx responses
+func httpAttempt(newRequest func() (*http.Request, error)) (body []byte, retry bool, err error) {
+	req, err := newRequest()
+	if err != nil {
+		return nil, false, err
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, true, err
+	}
+	defer resp.Body.Close()
+
+	body, err = ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, true, err
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, resp.StatusCode >= http.StatusInternalServerError, fmt.Errorf("%s %s: unexpected status %s", req.Method, req.URL, resp.Status)
+	}
+
+	return body, false, nil
+}
+
+// execScheme is a prefix of schema source which is a command printing schema to stdout
+const execScheme = "exec:"
+
+// readExec runs command, e.g. "./fetch-schema.sh prod", and returns its stdout
+func readExec(command string) ([]byte, error) {
+	return runExec("", command)
+}
+
+// runExec runs command in dir, current dir if empty, and returns its stdout
+func runExec(dir, command string) ([]byte, error) {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return nil, fmt.Errorf("exec source: command is empty")
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Dir = dir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("exec source error: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// maxWaitBackoff limits interval between attempts of waitSchema
+const maxWaitBackoff = 10 * time.Second
+
+// waitSchema reads source until it is a valid openrpc document or timeout expires.
+// Interval between attempts starts from backoff and doubles up to maxWaitBackoff.
+func waitSchema(source string, timeout, backoff time.Duration) ([]byte, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		data, err := ReadFileOrURL(source)
+		if err == nil {
+			_, err = checkDocument(data)
+		}
+		if err == nil {
+			return data, nil
+		}
+
+		if time.Now().Add(backoff).After(deadline) {
+			return nil, fmt.Errorf("schema is not available in %v: %w", timeout, err)
+		}
+
+		time.Sleep(backoff)
+		if backoff *= 2; backoff > maxWaitBackoff {
+			backoff = maxWaitBackoff
+		}
+	}
+}
+
+// NewDiffWait is NewDiff which waits up to timeout for new schema, e.g. freshly deployed service
+func NewDiffWait(old, new string, timeout time.Duration, options Options) (*Diff, error) {
+	newBytes, err := waitSchema(new, timeout, time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("read new schema error: %w", err)
+	}
+
+	oldBytes, err := ReadFileOrURL(old)
+	if err != nil {
+		return nil, fmt.Errorf("read old schema error: %w", err)
+	}
+
+	return newDiffSources(old, new, oldBytes, newBytes, options)
+}
@@ -0,0 +1,161 @@
+package rpcdiff
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestReadFileOrUrl_exec(t *testing.T) {
+	want, err := ioutil.ReadFile("testdata/openrpc_old.json")
+	if err != nil {
+		t.Fatalf("read error: %s", err)
+	}
+
+	got, err := ReadFileOrURL("exec:cat testdata/openrpc_old.json")
+	if err != nil {
+		t.Fatalf("ReadFileOrURL() error: %s", err)
+	}
+
+	if string(got) != string(want) {
+		t.Errorf("ReadFileOrURL() returned %d bytes, want %d", len(got), len(want))
+	}
+
+	for _, source := range []string{"exec:", "exec:cat testdata/missing.json"} {
+		if _, err := ReadFileOrURL(source); err == nil {
+			t.Errorf("ReadFileOrURL(%q) wanted error", source)
+		}
+	}
+}
+
+func TestNewDiff_exec(t *testing.T) {
+	diff, err := NewDiff("exec:cat testdata/openrpc_old.json", "testdata/openrpc_new.json", Options{})
+	if err != nil {
+		t.Fatalf("new diff error: %s", err)
+	}
+
+	if len(diff.Changes) == 0 {
+		t.Errorf("NewDiff() with exec source has no changes")
+	}
+}
+
+// fastRetries shortens http retry backoff until test ends
+func fastRetries(t *testing.T) {
+	backoff := httpRetryBackoff
+	httpRetryBackoff = time.Millisecond
+	t.Cleanup(func() { httpRetryBackoff = backoff })
+}
+
+func TestWaitSchema(t *testing.T) {
+	fastRetries(t)
+
+	data, err := ioutil.ReadFile("testdata/openrpc_new.json")
+	if err != nil {
+		t.Fatalf("read error: %s", err)
+	}
+
+	attempts := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		switch attempts {
+		case 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte("starting"))
+		case 2:
+			w.Write([]byte(`{"error":"not ready"}`))
+		default:
+			w.Write(data)
+		}
+	}))
+	defer srv.Close()
+
+	got, err := waitSchema(srv.URL, time.Second, time.Millisecond)
+	if err != nil {
+		t.Fatalf("waitSchema() error: %s", err)
+	}
+
+	if string(got) != string(data) || attempts != 3 {
+		t.Errorf("waitSchema() returned %d bytes in %d attempts, want %d bytes in 3 attempts", len(got), attempts, len(data))
+	}
+
+	if _, err := waitSchema("testdata/missing.json", 10*time.Millisecond, time.Millisecond); err == nil {
+		t.Errorf("waitSchema() of missing schema wanted error")
+	}
+}
+
+func TestHttpDo(t *testing.T) {
+	fastRetries(t)
+
+	tests := []struct {
+		name         string
+		statuses     []int
+		wantErr      bool
+		wantAttempts int
+	}{
+		{"ok", []int{200}, false, 1},
+		{"retried", []int{503, 502, 200}, false, 3},
+		{"attempts exceeded", []int{503, 503, 503, 200}, true, 3},
+		{"not found", []int{404, 200}, true, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.statuses[attempts])
+				attempts++
+				w.Write([]byte("body"))
+			}))
+			defer srv.Close()
+
+			body, err := ReadFileOrURL(srv.URL)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReadFileOrURL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if attempts != tt.wantAttempts {
+				t.Errorf("ReadFileOrURL() attempts = %v, want %v", attempts, tt.wantAttempts)
+			}
+
+			if !tt.wantErr && string(body) != "body" {
+				t.Errorf("ReadFileOrURL() = %s, want body", body)
+			}
+		})
+	}
+}
+
+func TestNewDiff_concurrentFetch(t *testing.T) {
+	// every schema is served only when both requests are in flight
+	var arrived sync.WaitGroup
+	arrived.Add(2)
+	serve := func(path string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			arrived.Done()
+			done := make(chan struct{})
+			go func() { arrived.Wait(); close(done) }()
+
+			select {
+			case <-done:
+				http.ServeFile(w, r, path)
+			case <-time.After(5 * time.Second):
+				w.WriteHeader(http.StatusBadRequest)
+			}
+		}))
+	}
+
+	oldSrv, newSrv := serve("testdata/openrpc_old.json"), serve("testdata/openrpc_new.json")
+	defer oldSrv.Close()
+	defer newSrv.Close()
+
+	diff, err := NewDiff(oldSrv.URL, newSrv.URL, Options{})
+	if err != nil {
+		t.Fatalf("new diff error: %s", err)
+	}
+
+	if len(diff.Changes) == 0 {
+		t.Errorf("NewDiff() of remote schemas has no changes")
+	}
+}
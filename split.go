@@ -0,0 +1,109 @@
+package rpcdiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// splitDir is a directory of extracted schemas relative to root schema
+const splitDir = "schemas"
+
+// SplitSchema extracts components.schemas of schema into separate files of dir/schemas and writes root schema to dir.
+// Root components.schemas become refs to files, refs in extracted schemas are rewritten to relative ones,
+// so bundle of root schema restores the original one. Returns written paths.
+func SplitSchema(source, dir string) ([]string, error) {
+	data, err := ReadFileOrURL(source)
+	if err != nil {
+		return nil, fmt.Errorf("read schema error: %w", err)
+	}
+
+	doc, err := decodeJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse schema error: %w", err)
+	}
+
+	root, ok := doc.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("schema is not a json object")
+	}
+
+	// keep name of local schema file
+	rootName := "openrpc.json"
+	if local := !strings.Contains(source, "://") && !strings.HasPrefix(source, execScheme); local && filepath.Ext(source) == ".json" {
+		rootName = filepath.Base(source)
+	}
+
+	components, _ := root["components"].(map[string]interface{})
+	schemas, _ := components["schemas"].(map[string]interface{})
+
+	names := make([]string, 0, len(schemas))
+	for name := range schemas {
+		if name == "" || strings.ContainsAny(name, `/\`) || name == "." || name == ".." {
+			return nil, fmt.Errorf("schema name %q can't be used as file name", name)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	files := map[string]interface{}{rootName: root}
+	for _, name := range names {
+		file := splitDir + "/" + name + ".json"
+		files[filepath.FromSlash(file)] = splitRefs(schemas[name], "../"+rootName)
+		schemas[name] = map[string]interface{}{"$ref": file}
+	}
+
+	paths := make([]string, 0, len(files))
+	for name, v := range files {
+		out, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return nil, err
+		}
+
+		if err := ioutil.WriteFile(path, append(out, '\n'), 0644); err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	return paths, nil
+}
+
+// splitRefs rewrites local refs of extracted schema: refs to schemas point to sibling files, other refs to root schema.
+// Refs inside schemas keep the rest of pointer, e.g. #/components/schemas/User/properties/id is User.json#/properties/id.
+func splitRefs(v interface{}, root string) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		if ref, ok := val["$ref"].(string); ok {
+			if kind, _, ok := splitComponentRef(ref); ok && kind == "schemas" {
+				name, pointer := strings.TrimPrefix(ref, "#/components/schemas/"), ""
+				if i := strings.Index(name, "/"); i != -1 {
+					name, pointer = name[:i], "#"+name[i:]
+				}
+				val["$ref"] = strings.NewReplacer("~1", "/", "~0", "~").Replace(name) + ".json" + pointer
+			} else if strings.HasPrefix(ref, "#") {
+				val["$ref"] = root + ref
+			}
+		}
+
+		for key, item := range val {
+			val[key] = splitRefs(item, root)
+		}
+	case []interface{}:
+		for i, item := range val {
+			val[i] = splitRefs(item, root)
+		}
+	}
+
+	return v
+}
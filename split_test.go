@@ -0,0 +1,119 @@
+package rpcdiff
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSplitSchema(t *testing.T) {
+	dir := t.TempDir()
+	paths, err := SplitSchema("testdata/openrpc_new.json", dir)
+	if err != nil {
+		t.Fatalf("SplitSchema() error: %s", err)
+	}
+
+	root := filepath.Join(dir, "openrpc_new.json")
+	if len(paths) < 2 || paths[0] != root {
+		t.Fatalf("SplitSchema() = %v, wanted root schema and schemas", paths)
+	}
+
+	data, err := ioutil.ReadFile(root)
+	if err != nil {
+		t.Fatalf("read error: %s", err)
+	}
+
+	ref, err := resolvePointer(mustDecodeJSON(t, data), "/components/schemas/ChangePropType/$ref")
+	if err != nil || ref != "schemas/ChangePropType.json" {
+		t.Errorf("root schema ref = %v, want schemas/ChangePropType.json", ref)
+	}
+
+	// bundle restores original schema
+	bundled, err := BundleSchema(root)
+	if err != nil {
+		t.Fatalf("BundleSchema() error: %s", err)
+	}
+
+	original, err := ioutil.ReadFile("testdata/openrpc_new.json")
+	if err != nil {
+		t.Fatalf("read error: %s", err)
+	}
+
+	diff, err := NewDiffBytes(original, bundled, Options{ShowMeta: true})
+	if err != nil {
+		t.Fatalf("new diff error: %s", err)
+	}
+
+	if len(diff.Changes) != 0 {
+		t.Errorf("bundle of split schema differs: %v", diff.String())
+	}
+}
+
+func TestSplitSchema_refs(t *testing.T) {
+	bundled, err := BundleSchema("testdata/bundle/root.json")
+	if err != nil {
+		t.Fatalf("BundleSchema() error: %s", err)
+	}
+
+	dir := t.TempDir()
+	source := filepath.Join(dir, "bundled.json")
+	if err := ioutil.WriteFile(source, bundled, 0644); err != nil {
+		t.Fatalf("write error: %s", err)
+	}
+
+	out := filepath.Join(dir, "out")
+	if _, err := SplitSchema(source, out); err != nil {
+		t.Fatalf("SplitSchema() error: %s", err)
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(out, "schemas", "Node.json"))
+	if err != nil {
+		t.Fatalf("read error: %s", err)
+	}
+
+	if ref, _ := resolvePointer(mustDecodeJSON(t, data), "/properties/next/$ref"); ref != "Node_2.json" {
+		t.Errorf("Node.json next ref = %v, want Node_2.json", ref)
+	}
+
+	rebundled, err := BundleSchema(filepath.Join(out, "bundled.json"))
+	if err != nil {
+		t.Fatalf("BundleSchema() error: %s", err)
+	}
+
+	if !reflect.DeepEqual(mustDecodeJSON(t, rebundled), mustDecodeJSON(t, bundled)) {
+		t.Errorf("bundle of split schema = %s, want %s", rebundled, bundled)
+	}
+}
+
+func Test_splitRefs(t *testing.T) {
+	schema := mustDecodeJSON(t, []byte(`{"properties": {
+		"user": {"$ref": "#/components/schemas/User"},
+		"id": {"$ref": "#/components/schemas/User/properties/id"},
+		"escaped": {"$ref": "#/components/schemas/a~0b/items"},
+		"error": {"$ref": "#/components/errors/NotFound"}
+	}}`))
+
+	want := map[string]string{
+		"user":    "User.json",
+		"id":      "User.json#/properties/id",
+		"escaped": "a~b.json#/items",
+		"error":   "../openrpc.json#/components/errors/NotFound",
+	}
+
+	got := splitRefs(schema, "../openrpc.json")
+	for name, ref := range want {
+		if v, _ := resolvePointer(got, "/properties/"+name+"/$ref"); v != ref {
+			t.Errorf("%s ref = %v, want %v", name, v, ref)
+		}
+	}
+}
+
+func mustDecodeJSON(t *testing.T, data []byte) interface{} {
+	v, err := decodeJSON(data)
+	if err != nil {
+		t.Fatalf("decode error: %s", err)
+	}
+
+	return v
+}
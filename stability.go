@@ -0,0 +1,72 @@
+package rpcdiff
+
+import (
+	"fmt"
+	"path"
+)
+
+// stabilityExtension marks stability of method, e.g. "x-stability": "experimental"
+const (
+	stabilityExtension    = "x-stability"
+	experimentalStability = "experimental"
+)
+
+// experimentalMethods returns names of methods marked with x-stability: experimental
+func experimentalMethods(data []byte) map[string]bool {
+	result := map[string]bool{}
+	doc, err := decodeObject(data)
+	if err != nil {
+		return result
+	}
+
+	methods, _ := doc["methods"].([]interface{})
+	for _, m := range methods {
+		method, _ := m.(map[string]interface{})
+		if name, ok := method["name"].(string); ok && method[stabilityExtension] == experimentalStability {
+			result[name] = true
+		}
+	}
+
+	return result
+}
+
+// downgradeExperimental lowers changes of methods experimental in old schema or matching any of glob patterns:
+// changes more critical than dangerous become dangerous and non breaking ones become info
+func downgradeExperimental(changes []Change, taxonomy Taxonomy, experimental map[string]bool, patterns []string) {
+	for i, c := range changes {
+		method := after(c.Path, "methods")
+		if method == "" || !isExperimental(method, experimental, patterns) {
+			continue
+		}
+
+		level := c.Criticality
+		switch {
+		case taxonomy.rank(level) < taxonomy.rank(Dangerous):
+			level = Dangerous
+		case level == PossiblyBreaking:
+			level = Dangerous
+		case level == NonBreaking:
+			level = Info
+		}
+
+		if level != c.Criticality {
+			changes[i].Criticality = level
+			changes[i].Reason = fmt.Sprintf("%s, moved to %s for experimental method", c.Reason, taxonomy.title(level))
+		}
+	}
+}
+
+// isExperimental checks method against x-stability markers and glob patterns
+func isExperimental(method string, experimental map[string]bool, patterns []string) bool {
+	if experimental[method] {
+		return true
+	}
+
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, method); ok {
+			return true
+		}
+	}
+
+	return false
+}
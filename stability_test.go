@@ -0,0 +1,33 @@
+package rpcdiff
+
+import "testing"
+
+func TestDowngradeExperimental(t *testing.T) {
+	old := []byte(`{"openrpc":"1.2.6","info":{"title":"api","version":"1.0.0"},"methods":[
+		{"name":"beta.Get","x-stability":"experimental","params":[],"result":{"name":"r","schema":{"type":"string"}}},
+		{"name":"labs.Find","params":[],"result":{"name":"r","schema":{"type":"string"}}},
+		{"name":"user.Get","params":[],"result":{"name":"r","schema":{"type":"string"}}}
+	]}`)
+	new := []byte(`{"openrpc":"1.2.6","info":{"title":"api","version":"1.0.0"},"methods":[
+		{"name":"user.Get","params":[],"result":{"name":"r","schema":{"type":"integer"}}}
+	]}`)
+
+	diff, err := NewDiffBytes(old, new, Options{Experimental: []string{"labs.*"}})
+	if err != nil {
+		t.Fatalf("NewDiffBytes() error: %s", err)
+	}
+
+	want := map[string]CriticalityLevel{"beta.Get": Dangerous, "labs.Find": Dangerous, "user.Get": Breaking}
+	for _, c := range diff.Changes {
+		method := after(c.Path, "methods")
+		if c.Criticality != want[method] {
+			t.Errorf("%s: criticality = %v, want %v", c.String(), c.Criticality, want[method])
+		}
+	}
+
+	changes := []Change{{Path: []string{"methods", "labs.Find", "summary"}, Criticality: NonBreaking}}
+	downgradeExperimental(changes, DefaultTaxonomy, nil, []string{"labs.*"})
+	if changes[0].Criticality != Info {
+		t.Errorf("downgradeExperimental() criticality = %v, want %v", changes[0].Criticality, Info)
+	}
+}
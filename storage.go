@@ -0,0 +1,282 @@
+package rpcdiff
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// storage types
+const (
+	StorageFilesystem = "filesystem"
+	StorageSQLite     = "sqlite"
+	StoragePostgres   = "postgres"
+)
+
+// StorageConfig selects backend of history store
+type StorageConfig struct {
+	Type      string    `json:"type"`      // filesystem, sqlite or postgres
+	DSN       string    `json:"dsn"`       // directory, database file or postgres connection string, paths are relative to config
+	Retention Retention `json:"retention"` // records removed by history gc
+}
+
+// Record is a schema version of service with its diff against previous version
+type Record struct {
+	Service   string          `json:"service"`
+	Version   string          `json:"version"` // schema version or git ref
+	Digest    string          `json:"digest"`  // sha256 of schema
+	CreatedAt time.Time       `json:"createdAt"`
+	Schema    json.RawMessage `json:"schema"`
+	Diff      *Diff           `json:"diff,omitempty"` // empty for the first version
+}
+
+// Store keeps schema versions of services
+type Store interface {
+	// Put saves record, record of service with the same digest is replaced
+	Put(r Record) error
+	// List returns records of service ordered by creation time, records of every service if service is empty
+	List(service string) ([]Record, error)
+	// Delete removes record of service with digest, missing record is not an error
+	Delete(service, digest string) error
+	Close() error
+}
+
+// OpenStore opens store of configured type, relative paths are resolved against dir
+func OpenStore(cfg StorageConfig, dir string) (Store, error) {
+	dsn := cfg.DSN
+	if cfg.Type != StoragePostgres && dsn != "" && !filepath.IsAbs(dsn) {
+		dsn = filepath.Join(dir, dsn)
+	}
+
+	switch cfg.Type {
+	case StorageFilesystem, "":
+		if cfg.DSN == "" {
+			dsn = filepath.Join(dir, ".rpcdiff", "history")
+		}
+		return &fsStore{dir: dsn}, nil
+	case StorageSQLite:
+		return openSQLStore("sqlite3", dsn)
+	case StoragePostgres:
+		return openSQLStore("postgres", dsn)
+	}
+
+	return nil, fmt.Errorf("unknown storage type %q, supported: %s, %s, %s", cfg.Type, StorageFilesystem, StorageSQLite, StoragePostgres)
+}
+
+// fsStore keeps records as json files in directory per service
+type fsStore struct {
+	dir string
+}
+
+func (s *fsStore) Put(r Record) error {
+	dir := filepath.Join(s.dir, r.Service)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	// file names keep records ordered, old file of the same digest is replaced
+	existing, err := filepath.Glob(filepath.Join(dir, "*-"+r.Digest+".json"))
+	if err != nil {
+		return err
+	}
+	for _, path := range existing {
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+	}
+
+	name := fmt.Sprintf("%020d-%s.json", r.CreatedAt.UnixNano(), r.Digest)
+
+	return ioutil.WriteFile(filepath.Join(dir, name), data, 0644)
+}
+
+func (s *fsStore) List(service string) ([]Record, error) {
+	pattern := filepath.Join(s.dir, "*", "*.json")
+	if service != "" {
+		pattern = filepath.Join(s.dir, service, "*.json")
+	}
+
+	paths, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Record, 0, len(paths))
+	for _, path := range paths {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		var r Record
+		if err := json.Unmarshal(data, &r); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		result = append(result, r)
+	}
+
+	sortRecords(result)
+
+	return result, nil
+}
+
+func (s *fsStore) Delete(service, digest string) error {
+	paths, err := filepath.Glob(filepath.Join(s.dir, service, "*-"+digest+".json"))
+	if err != nil {
+		return err
+	}
+
+	for _, path := range paths {
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *fsStore) Close() error {
+	return nil
+}
+
+// sqlStore keeps records in table of sqlite or postgres database
+type sqlStore struct {
+	db *sql.DB
+}
+
+// recordsTable is a schema of records table valid for sqlite and postgres, creation time is unix nanoseconds
+const recordsTable = `CREATE TABLE IF NOT EXISTS rpcdiff_records (
+	service    TEXT NOT NULL,
+	version    TEXT NOT NULL,
+	digest     TEXT NOT NULL,
+	created_at BIGINT NOT NULL,
+	schema     TEXT NOT NULL,
+	diff       TEXT,
+	PRIMARY KEY (service, digest)
+)`
+
+func openSQLStore(driver, dsn string) (*sqlStore, error) {
+	if dsn == "" {
+		return nil, fmt.Errorf("%s storage: dsn is empty", driver)
+	}
+
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := db.Exec(recordsTable); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("%s storage: create table error: %w", driver, err)
+	}
+
+	return &sqlStore{db: db}, nil
+}
+
+func (s *sqlStore) Put(r Record) error {
+	var diff sql.NullString
+	if r.Diff != nil {
+		data, err := json.Marshal(r.Diff)
+		if err != nil {
+			return err
+		}
+		diff = sql.NullString{String: string(data), Valid: true}
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// delete and insert instead of upsert as its syntax differs
+	if _, err := tx.Exec(`DELETE FROM rpcdiff_records WHERE service = $1 AND digest = $2`, r.Service, r.Digest); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`INSERT INTO rpcdiff_records (service, version, digest, created_at, schema, diff) VALUES ($1, $2, $3, $4, $5, $6)`,
+		r.Service, r.Version, r.Digest, r.CreatedAt.UnixNano(), string(r.Schema), diff); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *sqlStore) List(service string) ([]Record, error) {
+	query, args := `SELECT service, version, digest, created_at, schema, diff FROM rpcdiff_records`, []interface{}{}
+	if service != "" {
+		query, args = query+` WHERE service = $1`, append(args, service)
+	}
+
+	rows, err := s.db.Query(query+` ORDER BY created_at, service`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []Record
+	for rows.Next() {
+		var (
+			r         Record
+			createdAt int64
+			schema    string
+			diff      sql.NullString
+		)
+		if err := rows.Scan(&r.Service, &r.Version, &r.Digest, &createdAt, &schema, &diff); err != nil {
+			return nil, err
+		}
+
+		r.CreatedAt, r.Schema = time.Unix(0, createdAt).UTC(), json.RawMessage(schema)
+		if diff.Valid {
+			if err := json.Unmarshal([]byte(diff.String), &r.Diff); err != nil {
+				return nil, fmt.Errorf("record %s/%s: %w", r.Service, r.Digest, err)
+			}
+		}
+
+		result = append(result, r)
+	}
+
+	return result, rows.Err()
+}
+
+func (s *sqlStore) Delete(service, digest string) error {
+	_, err := s.db.Exec(`DELETE FROM rpcdiff_records WHERE service = $1 AND digest = $2`, service, digest)
+	return err
+}
+
+func (s *sqlStore) Close() error {
+	return s.db.Close()
+}
+
+// sortRecords orders records by creation time and service
+func sortRecords(records []Record) {
+	sort.SliceStable(records, func(i, j int) bool {
+		if !records[i].CreatedAt.Equal(records[j].CreatedAt) {
+			return records[i].CreatedAt.Before(records[j].CreatedAt)
+		}
+		return strings.Compare(records[i].Service, records[j].Service) < 0
+	})
+}
+
+// OpenConfigStore opens history store of config, filesystem store in current dir if config is nil
+func OpenConfigStore(cfg *Config) (Store, error) {
+	if cfg == nil {
+		return OpenStore(StorageConfig{}, ".")
+	}
+
+	return OpenStore(cfg.Storage, cfg.dir)
+}
@@ -0,0 +1,88 @@
+package rpcdiff
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStore(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  StorageConfig
+	}{
+		{name: "filesystem", cfg: StorageConfig{Type: StorageFilesystem, DSN: "history"}},
+		{name: "sqlite", cfg: StorageConfig{Type: StorageSQLite, DSN: "history.db"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := OpenStore(tt.cfg, t.TempDir())
+			if err != nil {
+				t.Fatalf("OpenStore() error: %s", err)
+			}
+			defer store.Close()
+
+			created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+			records := []Record{
+				{Service: "billing", Version: "1.0.1", Digest: "b", CreatedAt: created.Add(time.Hour), Schema: []byte(`{"v":2}`), Diff: &Diff{Criticality: Breaking, Score: 100}},
+				{Service: "billing", Version: "1.0.0", Digest: "a", CreatedAt: created, Schema: []byte(`{"v":1}`)},
+				{Service: "users", Version: "2.0.0", Digest: "c", CreatedAt: created, Schema: []byte(`{}`)},
+				{Service: "billing", Version: "1.0.2", Digest: "b", CreatedAt: created.Add(2 * time.Hour), Schema: []byte(`{"v":2}`), Diff: &Diff{Criticality: Breaking, Score: 100}},
+			}
+			for _, r := range records {
+				if err := store.Put(r); err != nil {
+					t.Fatalf("Put() error: %s", err)
+				}
+			}
+
+			got, err := store.List("billing")
+			if err != nil {
+				t.Fatalf("List() error: %s", err)
+			}
+
+			if len(got) != 2 || got[0].Version != "1.0.0" || got[1].Version != "1.0.2" {
+				t.Fatalf("List() = %+v, want 1.0.0 and replaced 1.0.2", got)
+			}
+
+			if !got[1].CreatedAt.Equal(created.Add(2*time.Hour)) || string(got[1].Schema) != `{"v":2}` || got[1].Diff == nil || got[1].Diff.Score != 100 {
+				t.Errorf("List()[1] = %+v, want saved record", got[1])
+			}
+
+			if got[0].Diff != nil {
+				t.Errorf("List()[0].Diff = %v, want nil", got[0].Diff)
+			}
+
+			if all, err := store.List(""); err != nil || len(all) != 3 || all[0].Service != "billing" || all[1].Service != "users" {
+				t.Errorf("List() of every service = %+v, %v, want 3 records", all, err)
+			}
+
+			if err := store.Delete("billing", "a"); err != nil {
+				t.Fatalf("Delete() error: %s", err)
+			}
+
+			if got, err := store.List("billing"); err != nil || len(got) != 1 || got[0].Digest != "b" {
+				t.Errorf("List() after Delete() = %+v, %v, want record b", got, err)
+			}
+		})
+	}
+}
+
+func TestOpenStore(t *testing.T) {
+	store, err := OpenStore(StorageConfig{}, "config")
+	if err != nil {
+		t.Fatalf("OpenStore() error: %s", err)
+	}
+
+	if fs, ok := store.(*fsStore); !ok || fs.dir != filepath.Join("config", ".rpcdiff", "history") {
+		t.Errorf("OpenStore() = %#v, want filesystem store in config dir", store)
+	}
+
+	if _, err := OpenStore(StorageConfig{Type: "mysql"}, ""); err == nil {
+		t.Errorf("OpenStore() error is nil for unknown type")
+	}
+
+	if _, err := OpenStore(StorageConfig{Type: StoragePostgres}, ""); err == nil {
+		t.Errorf("OpenStore() error is nil for empty dsn")
+	}
+}
@@ -0,0 +1,85 @@
+package rpcdiff
+
+import (
+	"encoding/json"
+	"io/ioutil"
+)
+
+// Bump is a recommended semantic version bump of schema
+type Bump string
+
+const (
+	BumpNone  Bump = "none"
+	BumpPatch Bump = "patch"
+	BumpMinor Bump = "minor"
+	BumpMajor Bump = "major"
+)
+
+// recommendedBump returns major for breaking changes, minor for additions and patch for other changes
+func recommendedBump(changes []Change) Bump {
+	bump := BumpNone
+	for _, c := range changes {
+		switch {
+		case c.Criticality == Breaking:
+			return BumpMajor
+		case c.Criticality == Info:
+			if bump == BumpNone {
+				bump = BumpPatch
+			}
+		case c.Type == Added:
+			bump = BumpMinor
+		case bump == BumpNone:
+			bump = BumpPatch
+		}
+	}
+
+	return bump
+}
+
+// Summary is a compact result of diff for other pipeline steps
+type Summary struct {
+	Criticality  CriticalityLevel         `json:"criticality"`
+	Score        int                      `json:"score"`
+	Bump         Bump                     `json:"bump"`
+	Total        int                      `json:"total"`
+	Levels       map[CriticalityLevel]int `json:"levels"`  // changes per level, every level of taxonomy is present
+	Objects      map[ChangeObject]int     `json:"objects"` // changes per object
+	Violations   int                      `json:"violations"`
+	Fingerprints []string                 `json:"fingerprints"`
+}
+
+// NewSummary counts changes of diff
+func NewSummary(diff *Diff) Summary {
+	s := Summary{
+		Criticality:  diff.Criticality,
+		Score:        diff.Score,
+		Bump:         recommendedBump(diff.Changes),
+		Total:        len(diff.Changes),
+		Levels:       map[CriticalityLevel]int{},
+		Objects:      map[ChangeObject]int{},
+		Violations:   len(diff.Violations),
+		Fingerprints: make([]string, 0, len(diff.Changes)),
+	}
+
+	for _, l := range diff.Options.taxonomy() {
+		s.Levels[l.Level] = 0
+	}
+
+	for _, c := range diff.Changes {
+		s.Levels[c.Criticality]++
+		s.Objects[c.Object]++
+		s.Fingerprints = append(s.Fingerprints, c.Fingerprint)
+	}
+
+	return s
+}
+
+// WriteSummary writes summary json of diff to path
+func WriteSummary(path string, diff *Diff) error {
+	data, err := json.MarshalIndent(NewSummary(diff), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, append(data, '\n'), 0644)
+}
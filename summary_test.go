@@ -0,0 +1,75 @@
+package rpcdiff
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func TestRecommendedBump(t *testing.T) {
+	removed := Change{Type: Removed, Criticality: Breaking}
+	added := Change{Type: Added, Criticality: NonBreaking}
+	changed := Change{Type: Changed, Criticality: Dangerous}
+
+	tests := []struct {
+		name    string
+		changes []Change
+		want    Bump
+	}{
+		{"no changes", nil, BumpNone},
+		{"changed", []Change{changed}, BumpPatch},
+		{"added", []Change{changed, added}, BumpMinor},
+		{"breaking", []Change{added, removed, changed}, BumpMajor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := recommendedBump(tt.changes); got != tt.want {
+				t.Errorf("recommendedBump() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteSummary(t *testing.T) {
+	diff, err := NewDiff("testdata/openrpc_old.json", "testdata/openrpc_new.json", Options{})
+	if err != nil {
+		t.Fatalf("new diff error: %s", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "summary.json")
+	if err := WriteSummary(path, diff); err != nil {
+		t.Fatalf("WriteSummary() error: %s", err)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read error: %s", err)
+	}
+
+	var got Summary
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("unmarshal error: %s", err)
+	}
+
+	if got.Criticality != Breaking || got.Bump != BumpMajor || got.Total != len(diff.Changes) {
+		t.Errorf("summary = %v %v %v, want BREAKING major %v", got.Criticality, got.Bump, got.Total, len(diff.Changes))
+	}
+
+	levels, objects := 0, 0
+	for _, n := range got.Levels {
+		levels += n
+	}
+	for _, n := range got.Objects {
+		objects += n
+	}
+
+	if levels != got.Total || objects != got.Total || len(got.Fingerprints) != got.Total {
+		t.Errorf("summary counts = %v %v %v, want %v", levels, objects, len(got.Fingerprints), got.Total)
+	}
+
+	if _, ok := got.Levels[Dangerous]; !ok {
+		t.Errorf("summary levels = %v, wanted every level", got.Levels)
+	}
+}
@@ -0,0 +1,147 @@
+package rpcdiff
+
+import (
+	"math"
+
+	openrpc "github.com/vmkteam/meta-schema/v2"
+)
+
+// synthesizeValue returns small value valid against json schema, const, examples, default and enum are preferred
+func synthesizeValue(schema *openrpc.JSONSchemaObject, doc *openrpc.OpenrpcDocument) interface{} {
+	return synthesize(schema, doc, 0)
+}
+
+func synthesize(schema *openrpc.JSONSchemaObject, doc *openrpc.OpenrpcDocument, depth int) interface{} {
+	if schema == nil || depth > maxValidateDepth {
+		return nil
+	}
+
+	if schema.Ref != "" {
+		return synthesize(resolveSchema(schema.Ref, doc), doc, depth+1)
+	}
+
+	switch {
+	case schema.Const != nil:
+		return *schema.Const
+	case len(schema.Examples) > 0:
+		return schema.Examples[0]
+	case schema.Default != nil:
+		return *schema.Default
+	case len(schema.Enum) > 0:
+		return schema.Enum[0]
+	case len(schema.AllOf) > 0:
+		return synthesizeAllOf(schema.AllOf, doc, depth)
+	case len(schema.AnyOf) > 0:
+		return synthesize(schema.AnyOf[0].JSONSchemaObject, doc, depth+1)
+	case len(schema.OneOf) > 0:
+		return synthesize(schema.OneOf[0].JSONSchemaObject, doc, depth+1)
+	}
+
+	var typ openrpc.SimpleType
+	for _, t := range simpleTypes(schema.Type) {
+		if typ = normalizeType(t); typ != openrpc.SimpleTypeNull {
+			break
+		}
+	}
+
+	if typ == "" {
+		switch {
+		case schema.Properties != nil:
+			typ = openrpc.SimpleTypeObject
+		case schema.Items != nil:
+			typ = openrpc.SimpleTypeArray
+		}
+	}
+
+	switch typ {
+	case openrpc.SimpleTypeObject:
+		result := map[string]interface{}{}
+		for _, name := range schema.Required {
+			var prop *openrpc.JSONSchemaObject
+			if schema.Properties != nil {
+				if p, ok := schema.Properties.Get(name); ok {
+					prop = p.JSONSchemaObject
+				}
+			}
+
+			result[name] = synthesize(prop, doc, depth+1)
+		}
+
+		return result
+	case openrpc.SimpleTypeArray:
+		result := []interface{}{}
+		items := getSchemaObject(schema.Items)
+		for i := int64(0); i < schema.MinItems || i < 1 && items != nil; i++ {
+			result = append(result, synthesize(items, doc, depth+1))
+		}
+
+		return result
+	case openrpc.SimpleTypeString:
+		return synthesizeString(schema.Format)
+	case openrpc.SimpleTypeInteger:
+		if schema.Minimum != 0 {
+			return math.Ceil(schema.Minimum)
+		}
+		return float64(1)
+	case openrpc.SimpleTypeNumber:
+		if schema.Minimum != 0 {
+			return schema.Minimum
+		}
+		return 1.5
+	case openrpc.SimpleTypeBoolean:
+		return true
+	}
+
+	return nil
+}
+
+// synthesizeAllOf merges object values of all schemas, the first value is returned for other types
+func synthesizeAllOf(schemas []openrpc.JSONSchema, doc *openrpc.OpenrpcDocument, depth int) interface{} {
+	var result interface{}
+	for _, s := range schemas {
+		value := synthesize(s.JSONSchemaObject, doc, depth+1)
+
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			if result == nil {
+				result = value
+			}
+			continue
+		}
+
+		merged, ok := result.(map[string]interface{})
+		if !ok {
+			if result != nil {
+				continue
+			}
+			merged = map[string]interface{}{}
+			result = merged
+		}
+
+		for k, v := range m {
+			merged[k] = v
+		}
+	}
+
+	return result
+}
+
+// synthesizeString returns sample string of format
+func synthesizeString(format string) string {
+	switch format {
+	case "date-time":
+		return "2021-01-01T00:00:00Z"
+	case "date":
+		return "2021-01-01"
+	case "time":
+		return "00:00:00"
+	case "email":
+		return "user@example.com"
+	case "uri":
+		return "https://example.com"
+	case "uuid":
+		return "00000000-0000-0000-0000-000000000000"
+	}
+
+	return "string"
+}
@@ -0,0 +1,53 @@
+package rpcdiff
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	openrpc "github.com/vmkteam/meta-schema/v2"
+)
+
+func Test_synthesizeValue(t *testing.T) {
+	doc := &openrpc.OpenrpcDocument{}
+	if err := json.Unmarshal([]byte(`{"components": {"schemas": {
+		"User": {"type": "object", "required": ["id", "email", "tags"], "properties": {
+			"id": {"type": "integer", "minimum": 10},
+			"email": {"type": "string", "format": "email"},
+			"tags": {"type": "array", "items": {"type": "string", "enum": ["a", "b"]}},
+			"name": {"type": "string"}
+		}}
+	}}}`), doc); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		schema string
+		want   interface{}
+	}{
+		{name: "ref", schema: `{"$ref": "#/components/schemas/User"}`, want: map[string]interface{}{"id": 10.0, "email": "user@example.com", "tags": []interface{}{"a"}}},
+		{name: "example", schema: `{"type": "string", "examples": ["x"]}`, want: "x"},
+		{name: "nullable", schema: `{"type": ["null", "boolean"]}`, want: true},
+		{name: "number", schema: `{"type": "number"}`, want: 1.5},
+		{name: "allOf", schema: `{"allOf": [{"type": "object", "required": ["a"]}, {"type": "object", "required": ["b"], "properties": {"b": {"type": "integer"}}}]}`, want: map[string]interface{}{"a": nil, "b": 1.0}},
+		{name: "any", schema: `{}`, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var schema openrpc.JSONSchemaObject
+			if err := json.Unmarshal([]byte(tt.schema), &schema); err != nil {
+				t.Fatal(err)
+			}
+
+			got := synthesizeValue(&schema, doc)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("synthesizeValue() = %v, want %v", toJSON(got), toJSON(tt.want))
+			}
+
+			if err := validateValue(&schema, got, doc); err != nil {
+				t.Errorf("synthesized value is invalid: %s", err)
+			}
+		})
+	}
+}
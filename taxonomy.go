@@ -0,0 +1,134 @@
+package rpcdiff
+
+import (
+	"fmt"
+)
+
+// Level is a criticality level of taxonomy
+type Level struct {
+	Level   CriticalityLevel `json:"level"`
+	Title   string           `json:"title,omitempty"`   // name in reports
+	Score   int              `json:"score"`             // base score of changes
+	Objects []ChangeObject   `json:"objects,omitempty"` // change objects moved to this level
+}
+
+// Taxonomy is an ordered list of criticality levels, the most critical first
+type Taxonomy []Level
+
+// DefaultTaxonomy is used when no taxonomy is configured
+var DefaultTaxonomy = Taxonomy{
+	{Level: Breaking, Score: 70},
+	{Level: Dangerous, Score: 40},
+	{Level: PossiblyBreaking, Score: 20},
+	{Level: NonBreaking, Score: 10},
+	{Level: Info, Score: 0},
+}
+
+// requiredLevels must be present in every taxonomy, missing PossiblyBreaking and Info are added by complete
+var requiredLevels = []CriticalityLevel{Breaking, Dangerous, NonBreaking}
+
+// Validate checks that levels are unique and built-in levels are present
+func (t Taxonomy) Validate() error {
+	seen := map[CriticalityLevel]bool{}
+	for i, l := range t {
+		if l.Level == "" {
+			return fmt.Errorf("level #%d: name is empty", i)
+		}
+		if seen[l.Level] {
+			return fmt.Errorf("level %s: duplicate level", l.Level)
+		}
+		seen[l.Level] = true
+	}
+
+	for _, level := range requiredLevels {
+		if !seen[level] {
+			return fmt.Errorf("level %s is missing", level)
+		}
+	}
+
+	return nil
+}
+
+// complete adds PossiblyBreaking level before NonBreaking and Info level to the end if taxonomy has no such levels
+func (t Taxonomy) complete() Taxonomy {
+	result := t
+	if t.rank(PossiblyBreaking) == len(t) {
+		i := t.rank(NonBreaking)
+		result = make(Taxonomy, 0, len(t)+2)
+		result = append(result, t[:i]...)
+		result = append(result, DefaultTaxonomy[DefaultTaxonomy.rank(PossiblyBreaking)])
+		result = append(result, t[i:]...)
+	}
+
+	if result.rank(Info) == len(result) {
+		result = append(result[:len(result):len(result)], DefaultTaxonomy[DefaultTaxonomy.rank(Info)])
+	}
+
+	return result
+}
+
+// rank returns position of level, unknown levels are the least critical
+func (t Taxonomy) rank(level CriticalityLevel) int {
+	for i, l := range t {
+		if l.Level == level {
+			return i
+		}
+	}
+
+	return len(t)
+}
+
+// title returns report name of level
+func (t Taxonomy) title(level CriticalityLevel) string {
+	for _, l := range t {
+		if l.Level == level && l.Title != "" {
+			return l.Title
+		}
+	}
+
+	if s := level.String(); s != "" {
+		return s
+	}
+
+	return string(level)
+}
+
+// Score returns base Score of level
+func (t Taxonomy) Score(level CriticalityLevel) int {
+	for _, l := range t {
+		if l.Level == level {
+			return l.Score
+		}
+	}
+
+	return 0
+}
+
+// apply moves changes of configured objects to their levels
+func (t Taxonomy) apply(changes []Change) {
+	levels := map[ChangeObject]CriticalityLevel{}
+	for _, l := range t {
+		for _, object := range l.Objects {
+			levels[object] = l.Level
+		}
+	}
+
+	for i := range changes {
+		if level, ok := levels[changes[i].Object]; ok && changes[i].Criticality != level {
+			changes[i].Criticality = level
+			changes[i].Reason = fmt.Sprintf("%s, moved to %s by taxonomy", changes[i].Reason, t.title(level))
+		}
+	}
+}
+
+// criticality returns the most critical level of changes, NonBreaking for no changes, Info changes are ignored
+func (t Taxonomy) criticality(changes []Change) CriticalityLevel {
+	result := NonBreaking
+	for _, c := range changes {
+		if c.Criticality != Info && t.rank(c.Criticality) < t.rank(result) {
+			result = c.Criticality
+		}
+	}
+
+	return result
+}
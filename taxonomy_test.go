@@ -0,0 +1,92 @@
+package rpcdiff
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewDiff_taxonomy(t *testing.T) {
+	taxonomy := Taxonomy{
+		{Level: Dangerous, Title: "risky", Score: 80},
+		{Level: Breaking, Score: 70},
+		{Level: NonBreaking, Score: 10},
+		{Level: "INFO", Title: "info", Score: 0, Objects: []ChangeObject{MethodError}},
+	}
+
+	if err := taxonomy.Validate(); err != nil {
+		t.Fatalf("validate error: %s", err)
+	}
+
+	diff, err := NewDiff("testdata/openrpc_old.json", "testdata/openrpc_new.json", Options{Taxonomy: taxonomy})
+	if err != nil {
+		t.Fatalf("new diff error: %s", err)
+	}
+
+	if diff.Criticality != Dangerous {
+		t.Errorf("Criticality = %v, want %v", diff.Criticality, Dangerous)
+	}
+
+	var info int
+	for _, c := range diff.Changes {
+		if c.Object == MethodError {
+			if c.Criticality != "INFO" || c.Score != 0 {
+				t.Errorf("%s criticality = %v, score = %v, wanted INFO, 0", c.String(), c.Criticality, c.Score)
+			}
+			info++
+		}
+	}
+
+	if info == 0 {
+		t.Fatalf("wanted method error changes")
+	}
+
+	s := diff.String()
+	for _, want := range []string{"New schema has risky change(s)", "Risky changes", "Info changes"} {
+		if !strings.Contains(s, want) {
+			t.Errorf("String() doesn't contain %q", want)
+		}
+	}
+
+	if strings.Index(s, "Risky changes") > strings.Index(s, "Breaking changes") {
+		t.Errorf("String() must list risky changes before breaking")
+	}
+}
+
+func TestTaxonomy_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		taxonomy Taxonomy
+		wantErr  bool
+	}{
+		{name: "default", taxonomy: DefaultTaxonomy},
+		{name: "missing level", taxonomy: Taxonomy{{Level: Breaking}, {Level: NonBreaking}}, wantErr: true},
+		{name: "duplicate level", taxonomy: append(Taxonomy{{Level: Breaking}}, DefaultTaxonomy...), wantErr: true},
+		{name: "empty level", taxonomy: append(Taxonomy{{Title: "info"}}, DefaultTaxonomy...), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.taxonomy.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTaxonomy_complete(t *testing.T) {
+	custom := Taxonomy{{Level: Breaking}, {Level: "CRITICAL"}, {Level: Dangerous}, {Level: NonBreaking}}
+	got := custom.complete()
+
+	want := []CriticalityLevel{Breaking, "CRITICAL", Dangerous, PossiblyBreaking, NonBreaking, Info}
+	if len(got) != len(want) {
+		t.Fatalf("complete() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i].Level != want[i] {
+			t.Errorf("complete()[%d] = %v, want %v", i, got[i].Level, want[i])
+		}
+	}
+
+	if got := DefaultTaxonomy.complete(); len(got) != len(DefaultTaxonomy) {
+		t.Errorf("complete() of default taxonomy = %v, want %v", got, DefaultTaxonomy)
+	}
+}
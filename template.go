@@ -0,0 +1,92 @@
+package rpcdiff
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// ChangeGroup is a named group of changes in custom templates
+type ChangeGroup struct {
+	Name    string
+	Changes []Change
+}
+
+// templateFuncs returns helpers of custom templates, levels are ordered by taxonomy of diff
+func templateFuncs(diff *Diff) template.FuncMap {
+	taxonomy := diff.Options.taxonomy()
+
+	return template.FuncMap{
+		// byLevel groups changes by criticality, the most critical first
+		"byLevel": func(changes []Change) []ChangeGroup {
+			var result []ChangeGroup
+			for _, l := range taxonomy {
+				group := ChangeGroup{Name: taxonomy.title(l.Level)}
+				for _, c := range changes {
+					if c.Criticality == l.Level {
+						group.Changes = append(group.Changes, c)
+					}
+				}
+
+				if len(group.Changes) > 0 {
+					result = append(result, group)
+				}
+			}
+
+			return result
+		},
+		// bySubject groups changes by method or component in order of appearance
+		"bySubject": func(changes []Change) []ChangeGroup {
+			var result []ChangeGroup
+			index := map[string]int{}
+			for _, c := range changes {
+				subject := changeSubject(c)
+				i, ok := index[subject]
+				if !ok {
+					i = len(result)
+					index[subject] = i
+					result = append(result, ChangeGroup{Name: subject})
+				}
+				result[i].Changes = append(result[i].Changes, c)
+			}
+
+			return result
+		},
+		// counts returns number of changes by criticality level, e.g. index (counts) "BREAKING"
+		"counts": func() map[string]int {
+			result := map[string]int{}
+			for _, l := range taxonomy {
+				result[string(l.Level)] = 0
+			}
+			for _, c := range diff.Changes {
+				result[string(c.Criticality)]++
+			}
+
+			return result
+		},
+		"title": func(level CriticalityLevel) string { return taxonomy.title(level) },
+		"join":  strings.Join,
+		"upper": strings.ToUpper,
+	}
+}
+
+// RenderTemplate renders diff with go text/template from file
+func RenderTemplate(diff *Diff, path string) (string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	tmpl, err := template.New(filepath.Base(path)).Funcs(templateFuncs(diff)).Parse(string(data))
+	if err != nil {
+		return "", err
+	}
+
+	buf := strings.Builder{}
+	if err := tmpl.Execute(&buf, diff); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
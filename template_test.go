@@ -0,0 +1,58 @@
+package rpcdiff
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+func Test_renderTemplate(t *testing.T) {
+	diff := &Diff{
+		Criticality: Breaking,
+		Changes: []Change{
+			{Path: []string{"methods", "user.Get"}, Type: Removed, Object: Method, Criticality: Breaking},
+			{Path: []string{"methods", "user.List", "params", "limit"}, Type: Added, Object: MethodParam, Criticality: NonBreaking, New: map[string]interface{}{"name": "limit"}},
+			{Path: []string{"methods", "user.List", "summary"}, Type: Changed, Object: Method, Criticality: Info, Old: "a", New: "b"},
+		},
+	}
+
+	tests := []struct {
+		name string
+		tmpl string
+		want string
+	}{
+		{
+			name: "by level",
+			tmpl: `{{range byLevel .Changes}}{{.Name}}:{{range .Changes}} {{join .Path "."}}{{end}};{{end}}`,
+			want: "breaking: methods.user.Get;non breaking: methods.user.List.params.limit;info: methods.user.List.summary;",
+		},
+		{
+			name: "by subject",
+			tmpl: `{{range bySubject .Changes}}{{.Name}}={{len .Changes}} {{end}}`,
+			want: "user.Get=1 user.List=2 ",
+		},
+		{
+			name: "counts",
+			tmpl: `{{upper (title .Criticality)}} {{index counts "BREAKING"}}/{{index counts "DANGEROUS"}}/{{index counts "INFO"}}`,
+			want: "BREAKING 1/0/1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "report.tmpl")
+			if err := ioutil.WriteFile(path, []byte(tt.tmpl), 0644); err != nil {
+				t.Fatalf("write error: %s", err)
+			}
+
+			got, err := RenderTemplate(diff, path)
+			if err != nil {
+				t.Fatalf("RenderTemplate() error: %s", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("RenderTemplate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,12 @@
+package client
+
+const (
+	methodRemoved   = "check.removedMethod"
+	methodUntouched = "check.UntouchedMethod"
+)
+
+func call(method string) {}
+
+func Remove() {
+	call(methodRemoved)
+}
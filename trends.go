@@ -0,0 +1,245 @@
+package rpcdiff
+
+import (
+	"fmt"
+	"html/template"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxChurnedMethods limits list of the most churned methods of trend
+const maxChurnedMethods = 10
+
+// Trend is a stability report of service built from its history
+type Trend struct {
+	Service  string         `json:"service"`
+	Since    time.Time      `json:"since"`
+	Releases []TrendRelease `json:"releases"`
+	Churn    []MethodChurn  `json:"churn"` // the most changed methods
+}
+
+// TrendRelease is a stored version of schema with its size and changes against previous version
+type TrendRelease struct {
+	Version   string    `json:"version"`
+	CreatedAt time.Time `json:"createdAt"`
+	Methods   int       `json:"methods"`
+	Schemas   int       `json:"schemas"`
+	Changes   int       `json:"changes"`
+	Breaking  int       `json:"breaking"`
+}
+
+// MethodChurn is a number of changes of method over releases
+type MethodChurn struct {
+	Method   string `json:"method"`
+	Changes  int    `json:"changes"`
+	Releases int    `json:"releases"` // releases which changed method
+}
+
+// NewTrend builds trend of service from its records created since time, zero time means all records
+func NewTrend(service string, records []Record, since time.Time) *Trend {
+	t := &Trend{Service: service, Since: since}
+
+	churn := map[string]*MethodChurn{}
+	for _, r := range records {
+		if r.Service != service || r.CreatedAt.Before(since) {
+			continue
+		}
+
+		release := TrendRelease{Version: r.Version, CreatedAt: r.CreatedAt}
+		if doc, err := parseDocument(r.Schema); err == nil {
+			release.Methods = len(doc.Methods)
+			if doc.Components != nil && doc.Components.Schemas != nil {
+				release.Schemas = len(*doc.Components.Schemas)
+			}
+		}
+
+		if r.Diff != nil {
+			release.Changes = len(r.Diff.Changes)
+
+			changed := map[string]bool{}
+			for _, c := range r.Diff.Changes {
+				if c.Criticality == Breaking {
+					release.Breaking++
+				}
+
+				method := after(c.Path, "methods")
+				if method == "" {
+					continue
+				}
+
+				mc, ok := churn[method]
+				if !ok {
+					mc = &MethodChurn{Method: method}
+					churn[method] = mc
+				}
+
+				mc.Changes++
+				if !changed[method] {
+					mc.Releases++
+					changed[method] = true
+				}
+			}
+		}
+
+		t.Releases = append(t.Releases, release)
+	}
+
+	for _, mc := range churn {
+		t.Churn = append(t.Churn, *mc)
+	}
+
+	sort.Slice(t.Churn, func(i, j int) bool {
+		if t.Churn[i].Changes != t.Churn[j].Changes {
+			return t.Churn[i].Changes > t.Churn[j].Changes
+		}
+		return t.Churn[i].Method < t.Churn[j].Method
+	})
+
+	if len(t.Churn) > maxChurnedMethods {
+		t.Churn = t.Churn[:maxChurnedMethods]
+	}
+
+	return t
+}
+
+// ParseSince parses date like 2024-01-31, 2024-01 or 2024
+func ParseSince(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+
+	for _, layout := range []string{"2006-01-02", "2006-01", "2006"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid date %q, use YYYY-MM-DD, YYYY-MM or YYYY", s)
+}
+
+// trendBar renders value as bar of block characters scaled to max
+func trendBar(value, max int) string {
+	const width = 20
+	if max == 0 || value == 0 {
+		return ""
+	}
+
+	n := value * width / max
+	if n == 0 {
+		n = 1
+	}
+
+	return strings.Repeat("█", n)
+}
+
+// maxOf returns max value of releases
+func (t *Trend) maxOf(value func(TrendRelease) int) int {
+	max := 0
+	for _, r := range t.Releases {
+		if v := value(r); v > max {
+			max = v
+		}
+	}
+
+	return max
+}
+
+// TrendMarkdown renders trend as markdown with tables and bar charts
+func TrendMarkdown(t *Trend) string {
+	buf := strings.Builder{}
+	fmt.Fprintf(&buf, "# Stability report of `%s`\n\n", t.Service)
+	if !t.Since.IsZero() {
+		fmt.Fprintf(&buf, "Since %s, ", t.Since.Format("2006-01-02"))
+	}
+	fmt.Fprintf(&buf, "%d release(s).\n", len(t.Releases))
+
+	if len(t.Releases) == 0 {
+		return buf.String()
+	}
+
+	maxBreaking := t.maxOf(func(r TrendRelease) int { return r.Breaking })
+	buf.WriteString("\n## Breaking changes per release\n\n| Release | Date | Breaking | Changes | |\n|---|---|---|---|---|\n")
+	for _, r := range t.Releases {
+		fmt.Fprintf(&buf, "| `%s` | %s | %d | %d | %s |\n", r.Version, r.CreatedAt.Format("2006-01-02"), r.Breaking, r.Changes, trendBar(r.Breaking, maxBreaking))
+	}
+
+	maxMethods := t.maxOf(func(r TrendRelease) int { return r.Methods })
+	buf.WriteString("\n## API size\n\n| Release | Methods | Schemas | |\n|---|---|---|---|\n")
+	for _, r := range t.Releases {
+		fmt.Fprintf(&buf, "| `%s` | %d | %d | %s |\n", r.Version, r.Methods, r.Schemas, trendBar(r.Methods, maxMethods))
+	}
+
+	if len(t.Churn) > 0 {
+		buf.WriteString("\n## Most churned methods\n\n| Method | Changes | Releases |\n|---|---|---|\n")
+		for _, mc := range t.Churn {
+			fmt.Fprintf(&buf, "| `%s` | %d | %d |\n", markdownCell(mc.Method), mc.Changes, mc.Releases)
+		}
+	}
+
+	return buf.String()
+}
+
+// trendTemplate is a standalone html trend report, bars are scaled to max value of column
+var trendTemplate = template.Must(template.New("trend").Funcs(template.FuncMap{
+	"percent": func(value, max int) int {
+		if max == 0 {
+			return 0
+		}
+		return value * 100 / max
+	},
+	"date": func(t time.Time) string { return t.Format("2006-01-02") },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Stability report of {{.Service}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #24292f; }
+table { border-collapse: collapse; margin: .5em 0 1.5em; }
+th, td { border-bottom: 1px solid #d0d7de; padding: .3em .8em; text-align: left; }
+.bar { height: .9em; min-width: 1px; }
+.breaking { background: red; }
+.size { background: steelblue; }
+</style>
+</head>
+<body>
+<h1>Stability report of <code>{{.Service}}</code></h1>
+<p>{{if not .Since.IsZero}}Since {{date .Since}}, {{end}}{{len .Releases}} release(s).</p>
+{{if .Releases}}<h2>Breaking changes per release</h2>
+<table>
+<tr><th>Release</th><th>Date</th><th>Breaking</th><th>Changes</th><th style="width: 200px"></th></tr>
+{{range .Releases}}<tr><td><code>{{.Version}}</code></td><td>{{date .CreatedAt}}</td><td>{{.Breaking}}</td><td>{{.Changes}}</td><td>{{if .Breaking}}<div class="bar breaking" style="width: {{percent .Breaking $.MaxBreaking}}%"></div>{{end}}</td></tr>
+{{end}}</table>
+<h2>API size</h2>
+<table>
+<tr><th>Release</th><th>Methods</th><th>Schemas</th><th style="width: 200px"></th></tr>
+{{range .Releases}}<tr><td><code>{{.Version}}</code></td><td>{{.Methods}}</td><td>{{.Schemas}}</td><td><div class="bar size" style="width: {{percent .Methods $.MaxMethods}}%"></div></td></tr>
+{{end}}</table>
+{{end}}{{if .Churn}}<h2>Most churned methods</h2>
+<table>
+<tr><th>Method</th><th>Changes</th><th>Releases</th></tr>
+{{range .Churn}}<tr><td><code>{{.Method}}</code></td><td>{{.Changes}}</td><td>{{.Releases}}</td></tr>
+{{end}}</table>
+{{end}}</body>
+</html>
+`))
+
+// TrendHTML renders trend as standalone html page with bar charts
+func TrendHTML(t *Trend) (string, error) {
+	data := struct {
+		*Trend
+		MaxBreaking, MaxMethods int
+	}{
+		Trend:       t,
+		MaxBreaking: t.maxOf(func(r TrendRelease) int { return r.Breaking }),
+		MaxMethods:  t.maxOf(func(r TrendRelease) int { return r.Methods }),
+	}
+
+	buf := strings.Builder{}
+	if err := trendTemplate.Execute(&buf, data); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
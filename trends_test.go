@@ -0,0 +1,69 @@
+package rpcdiff
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewTrend(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+	schema := []byte(`{"openrpc":"1.2.6","info":{"title":"api","version":"1"},"methods":[{"name":"a","params":[]},{"name":"b","params":[]}],
+		"components":{"schemas":{"User":{"type":"object"}}}}`)
+	change := func(method string, level CriticalityLevel) Change {
+		return Change{Path: []string{"methods", method, "params", "p"}, Criticality: level}
+	}
+
+	records := []Record{
+		{Service: "billing", Version: "v0", CreatedAt: day(1), Schema: schema},
+		{Service: "billing", Version: "v1", CreatedAt: day(10), Schema: schema, Diff: &Diff{Changes: []Change{change("a", Breaking), change("a", NonBreaking), change("b", NonBreaking)}}},
+		{Service: "users", Version: "v1", CreatedAt: day(11), Schema: schema, Diff: &Diff{Changes: []Change{change("c", Breaking)}}},
+		{Service: "billing", Version: "v2", CreatedAt: day(20), Schema: schema, Diff: &Diff{Changes: []Change{change("a", Breaking), {Path: []string{"components", "schemas", "User"}}}}},
+	}
+
+	trend := NewTrend("billing", records, day(5))
+
+	if len(trend.Releases) != 2 {
+		t.Fatalf("len(Releases) = %v, want %v", len(trend.Releases), 2)
+	}
+
+	want := TrendRelease{Version: "v1", CreatedAt: day(10), Methods: 2, Schemas: 1, Changes: 3, Breaking: 1}
+	if trend.Releases[0] != want {
+		t.Errorf("Releases[0] = %+v, want %+v", trend.Releases[0], want)
+	}
+
+	wantChurn := []MethodChurn{{Method: "a", Changes: 3, Releases: 2}, {Method: "b", Changes: 1, Releases: 1}}
+	if len(trend.Churn) != len(wantChurn) || trend.Churn[0] != wantChurn[0] || trend.Churn[1] != wantChurn[1] {
+		t.Errorf("Churn = %+v, want %+v", trend.Churn, wantChurn)
+	}
+
+	report := TrendMarkdown(trend)
+	if !strings.Contains(report, "| `v1` | 2024-01-10 | 1 | 3 | ████████████████████ |\n") || !strings.Contains(report, "| `a` | 3 | 2 |\n") {
+		t.Errorf("TrendMarkdown() = %v", report)
+	}
+
+	if _, err := TrendHTML(trend); err != nil {
+		t.Errorf("TrendHTML() error: %s", err)
+	}
+}
+
+func Test_parseSince(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Time
+		wantErr bool
+	}{
+		{in: "", want: time.Time{}},
+		{in: "2024-01", want: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{in: "2024-03-15", want: time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)},
+		{in: "2024", want: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{in: "last year", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseSince(tt.in)
+		if (err != nil) != tt.wantErr || !got.Equal(tt.want) {
+			t.Errorf("ParseSince(%v) = %v, %v, want %v", tt.in, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package rpcdiff
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// upload content types
+const (
+	contentTypeJSON = "application/json"
+	contentTypeHTML = "text/html; charset=utf-8"
+)
+
+// UploadDiff writes diff json and html report to destination directory, http endpoint, s3 or gcs bucket
+// under content-addressed names and returns their locations
+func UploadDiff(dest string, diff *Diff) ([]string, error) {
+	data, err := json.Marshal(diff)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := htmlReport(diff)
+	if err != nil {
+		return nil, err
+	}
+
+	put, err := newUploader(dest)
+	if err != nil {
+		return nil, err
+	}
+
+	name := digest(data)
+
+	var locations []string
+	for _, file := range []struct {
+		ext         string
+		data        []byte
+		contentType string
+	}{
+		{ext: ".json", data: data, contentType: contentTypeJSON},
+		{ext: ".html", data: []byte(report), contentType: contentTypeHTML},
+	} {
+		location, err := put(name+file.ext, file.data, file.contentType)
+		if err != nil {
+			return nil, err
+		}
+		locations = append(locations, location)
+	}
+
+	return locations, nil
+}
+
+// uploader writes file to destination and returns its location
+type uploader func(name string, data []byte, contentType string) (string, error)
+
+// newUploader returns uploader of destination directory, http(s) url prefix, s3://bucket/prefix or gs://bucket/prefix
+func newUploader(dest string) (uploader, error) {
+	switch {
+	case strings.HasPrefix(dest, "http://"), strings.HasPrefix(dest, "https://"):
+		return func(name string, data []byte, contentType string) (string, error) {
+			location := strings.TrimSuffix(dest, "/") + "/" + name
+			return location, httpPut(location, data, contentType)
+		}, nil
+	case strings.HasPrefix(dest, s3Scheme):
+		bucket, prefix := splitBucket(strings.TrimPrefix(dest, s3Scheme))
+		return func(name string, data []byte, contentType string) (string, error) {
+			return s3Put(bucket, prefix+name, data, contentType)
+		}, nil
+	case strings.HasPrefix(dest, gcsScheme):
+		bucket, prefix := splitBucket(strings.TrimPrefix(dest, gcsScheme))
+		return func(name string, data []byte, contentType string) (string, error) {
+			return gcsPut(bucket, prefix+name, data, contentType)
+		}, nil
+	case strings.Contains(dest, "://"):
+		return nil, fmt.Errorf("upload to %s is not supported, use s3://, gs://, http(s) endpoint or local directory", dest)
+	}
+
+	return func(name string, data []byte, contentType string) (string, error) {
+		if err := os.MkdirAll(dest, 0755); err != nil {
+			return "", err
+		}
+
+		location := filepath.Join(dest, name)
+
+		return location, ioutil.WriteFile(location, data, 0644)
+	}, nil
+}
+
+func httpPut(url string, data []byte, contentType string) error {
+	_, err := httpDo(func() (*http.Request, error) {
+		req, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", contentType)
+
+		return req, nil
+	})
+	if err != nil {
+		return fmt.Errorf("upload error: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package rpcdiff
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_uploadDiff(t *testing.T) {
+	diff := &Diff{Criticality: NonBreaking}
+
+	dir := t.TempDir()
+	locations, err := UploadDiff(dir, diff)
+	if err != nil {
+		t.Fatalf("upload to dir error: %s", err)
+	}
+
+	if len(locations) != 2 || filepath.Dir(locations[0]) != dir || !strings.HasSuffix(locations[0], ".json") || !strings.HasSuffix(locations[1], ".html") {
+		t.Errorf("locations = %v, wanted json and html in %v", locations, dir)
+	}
+
+	var uploaded []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		uploaded = append(uploaded, r.Method+" "+r.URL.Path+" "+r.Header.Get("Content-Type")+" "+string(b))
+	}))
+	defer srv.Close()
+
+	locations, err = UploadDiff(srv.URL+"/reports/", diff)
+	if err != nil {
+		t.Fatalf("upload to http error: %s", err)
+	}
+
+	if len(uploaded) != 2 || !strings.HasPrefix(uploaded[0], "PUT /reports/"+filepath.Base(locations[0])+" "+contentTypeJSON+` {"criticality":"NON_BREAKING"`) {
+		t.Errorf("uploaded = %v", uploaded)
+	}
+
+	if len(uploaded) == 2 && !strings.HasPrefix(uploaded[1], "PUT /reports/"+filepath.Base(locations[1])+" "+contentTypeHTML+" <!DOCTYPE html>") {
+		t.Errorf("uploaded report = %.80v", uploaded[1])
+	}
+
+	if _, err := UploadDiff("ftp://host/prefix/", diff); err == nil {
+		t.Errorf("upload to ftp must fail")
+	}
+}
@@ -0,0 +1,118 @@
+package rpcdiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Usage is a number of calls per day by method
+type Usage map[string]float64
+
+// LoadUsage reads usage manifest, json object with calls per day by method, e.g. {"billing.Get": 2000000}
+func LoadUsage(filename string) (Usage, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("read usage error: %w", err)
+	}
+
+	var u Usage
+	if err := json.Unmarshal(data, &u); err != nil {
+		return nil, fmt.Errorf("parse usage error: %w", err)
+	}
+
+	return u, nil
+}
+
+// Set loads usage from manifest, it implements pflag.Value
+func (u *Usage) Set(filename string) error {
+	usage, err := LoadUsage(filename)
+	if err != nil {
+		return err
+	}
+
+	*u = usage
+	return nil
+}
+
+func (u *Usage) String() string {
+	return ""
+}
+
+func (u *Usage) Type() string {
+	return "path"
+}
+
+// Risk is a method with breaking or dangerous changes weighted by its traffic
+type Risk struct {
+	Method  string  `json:"method"`
+	Calls   float64 `json:"calls"`   // per day
+	Changes int     `json:"changes"` // number of breaking and dangerous changes
+	Score   int     `json:"score"`   // max score of changes
+}
+
+func (r Risk) String() string {
+	calls := "no calls"
+	if r.Calls > 0 {
+		calls = formatCalls(r.Calls) + " calls/day"
+	}
+
+	return fmt.Sprintf(`method "%s": %d breaking or dangerous change(s), score %d, %s`, r.Method, r.Changes, r.Score, calls)
+}
+
+// riskSummary ranks methods with changes at least as critical as dangerous by calls per day, then by score
+func riskSummary(changes []Change, taxonomy Taxonomy, usage Usage) []Risk {
+	byMethod := map[string]*Risk{}
+	for _, c := range changes {
+		method := after(c.Path, "methods")
+		if method == "" || taxonomy.rank(c.Criticality) > taxonomy.rank(Dangerous) {
+			continue
+		}
+
+		r, ok := byMethod[method]
+		if !ok {
+			r = &Risk{Method: method, Calls: usage[method]}
+			byMethod[method] = r
+		}
+
+		r.Changes++
+		if c.Score > r.Score {
+			r.Score = c.Score
+		}
+	}
+
+	result := make([]Risk, 0, len(byMethod))
+	for _, r := range byMethod {
+		result = append(result, *r)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		switch {
+		case a.Calls != b.Calls:
+			return a.Calls > b.Calls
+		case a.Score != b.Score:
+			return a.Score > b.Score
+		}
+		return a.Method < b.Method
+	})
+
+	return result
+}
+
+// formatCalls renders number of calls with k, M or B suffix, e.g. 2M or 1.5k
+func formatCalls(n float64) string {
+	for _, unit := range []struct {
+		size   float64
+		suffix string
+	}{{1e9, "B"}, {1e6, "M"}, {1e3, "k"}} {
+		if n >= unit.size {
+			return strings.TrimSuffix(strconv.FormatFloat(n/unit.size, 'f', 1, 64), ".0") + unit.suffix
+		}
+	}
+
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
@@ -0,0 +1,74 @@
+package rpcdiff
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestRiskSummary(t *testing.T) {
+	changes := []Change{
+		{Path: []string{"methods", "billing.Get"}, Criticality: Breaking, Score: 90},
+		{Path: []string{"methods", "billing.Get", "params"}, Criticality: Dangerous, Score: 40},
+		{Path: []string{"methods", "users.Get"}, Criticality: Breaking, Score: 100},
+		{Path: []string{"methods", "users.List"}, Criticality: Breaking, Score: 70},
+		{Path: []string{"methods", "users.Create"}, Criticality: NonBreaking, Score: 10},
+		{Path: []string{"components", "schemas", "User"}, Criticality: Breaking, Score: 80},
+	}
+	usage := Usage{"billing.Get": 2e6, "users.Get": 1500}
+
+	got := riskSummary(changes, Options{}.taxonomy(), usage)
+	want := []Risk{
+		{Method: "billing.Get", Calls: 2e6, Changes: 2, Score: 90},
+		{Method: "users.Get", Calls: 1500, Changes: 1, Score: 100},
+		{Method: "users.List", Changes: 1, Score: 70},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("riskSummary() = %v, want %v", got, want)
+	}
+}
+
+func TestFormatCalls(t *testing.T) {
+	tests := []struct {
+		calls float64
+		want  string
+	}{
+		{calls: 12, want: "12"},
+		{calls: 1500, want: "1.5k"},
+		{calls: 2e6, want: "2M"},
+		{calls: 2345678, want: "2.3M"},
+		{calls: 3e9, want: "3B"},
+	}
+
+	for _, tt := range tests {
+		if got := formatCalls(tt.calls); got != tt.want {
+			t.Errorf("formatCalls(%v) = %v, want %v", tt.calls, got, tt.want)
+		}
+	}
+}
+
+func TestLoadUsage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "usage.json")
+	if err := ioutil.WriteFile(path, []byte(`{"billing.Get": 2000000}`), 0644); err != nil {
+		t.Fatalf("write error: %s", err)
+	}
+
+	var usage Usage
+	if err := usage.Set(path); err != nil {
+		t.Fatalf("Set() error: %s", err)
+	}
+
+	if got := usage["billing.Get"]; got != 2e6 {
+		t.Errorf("usage[billing.Get] = %v, want %v", got, 2e6)
+	}
+
+	if err := ioutil.WriteFile(path, []byte(`["billing.Get"]`), 0644); err != nil {
+		t.Fatalf("write error: %s", err)
+	}
+
+	if _, err := LoadUsage(path); err == nil {
+		t.Errorf("LoadUsage() error = nil, want error")
+	}
+}
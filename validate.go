@@ -0,0 +1,188 @@
+package rpcdiff
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+
+	openrpc "github.com/vmkteam/meta-schema/v2"
+)
+
+// maxValidateDepth limits reference resolving for recursive schemas
+const maxValidateDepth = 32
+
+// validateValue validates decoded json value against json schema, references are resolved in doc components
+func validateValue(schema *openrpc.JSONSchemaObject, value interface{}, doc *openrpc.OpenrpcDocument) error {
+	return validate(schema, value, doc, "$", 0)
+}
+
+func validate(schema *openrpc.JSONSchemaObject, value interface{}, doc *openrpc.OpenrpcDocument, path string, depth int) error {
+	if schema == nil {
+		return nil
+	}
+
+	if depth > maxValidateDepth {
+		return fmt.Errorf("%s: schema is too deep", path)
+	}
+
+	// reference
+	if schema.Ref != "" {
+		ref := resolveSchema(schema.Ref, doc)
+		if ref == nil {
+			return fmt.Errorf("%s: unresolved reference %s", path, schema.Ref)
+		}
+
+		return validate(ref, value, doc, path, depth+1)
+	}
+
+	// type
+	if types := simpleTypes(schema.Type); len(types) > 0 && !acceptsType(types, valueType(value)) {
+		return fmt.Errorf("%s: expected %v, got %s", path, typeValue(schema.Type), valueType(value))
+	}
+
+	// enum
+	if len(schema.Enum) > 0 && !acceptsValues(schema.Enum, []interface{}{value}) {
+		return fmt.Errorf("%s: value %v is not in enum %v", path, toJSON(value), toJSON(schema.Enum))
+	}
+
+	// combinations
+	for _, s := range schema.AllOf {
+		if err := validate(s.JSONSchemaObject, value, doc, path, depth+1); err != nil {
+			return err
+		}
+	}
+
+	if err := validateAny(schema.AnyOf, value, doc, path, depth); err != nil {
+		return err
+	}
+
+	if err := validateOne(schema.OneOf, value, doc, path, depth); err != nil {
+		return err
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, name := range schema.Required {
+			if _, ok := v[name]; !ok {
+				return fmt.Errorf("%s: required property %s is missing", path, name)
+			}
+		}
+
+		if schema.Properties == nil {
+			return nil
+		}
+
+		for name, propValue := range v {
+			if prop, ok := schema.Properties.Get(name); ok {
+				if err := validate(prop.JSONSchemaObject, propValue, doc, path+"."+name, depth+1); err != nil {
+					return err
+				}
+			}
+		}
+	case []interface{}:
+		items := getSchemaObject(schema.Items)
+		if items == nil {
+			return nil
+		}
+
+		for i, item := range v {
+			if err := validate(items, item, doc, fmt.Sprintf("%s[%d]", path, i), depth+1); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateAny checks that value matches at least one of schemas
+func validateAny(schemas []openrpc.JSONSchema, value interface{}, doc *openrpc.OpenrpcDocument, path string, depth int) error {
+	if len(schemas) == 0 {
+		return nil
+	}
+
+	var errs []string
+	for _, s := range schemas {
+		err := validate(s.JSONSchemaObject, value, doc, path, depth+1)
+		if err == nil {
+			return nil
+		}
+
+		errs = append(errs, err.Error())
+	}
+
+	return fmt.Errorf("%s: value doesn't match any schema: %s", path, strings.Join(errs, "; "))
+}
+
+// validateOne checks that value matches exactly one of schemas
+func validateOne(schemas []openrpc.JSONSchema, value interface{}, doc *openrpc.OpenrpcDocument, path string, depth int) error {
+	if len(schemas) == 0 {
+		return nil
+	}
+
+	var (
+		errs    []string
+		matched []string
+	)
+	for i, s := range schemas {
+		if err := validate(s.JSONSchemaObject, value, doc, path, depth+1); err != nil {
+			errs = append(errs, err.Error())
+		} else {
+			matched = append(matched, fmt.Sprintf("%d", i))
+		}
+	}
+
+	switch len(matched) {
+	case 0:
+		return fmt.Errorf("%s: value doesn't match any schema: %s", path, strings.Join(errs, "; "))
+	case 1:
+		return nil
+	}
+
+	return fmt.Errorf("%s: value matches more than one of oneOf schemas: %s", path, strings.Join(matched, ", "))
+}
+
+// valueType returns json schema type of decoded json value
+func valueType(value interface{}) openrpc.SimpleType {
+	switch v := value.(type) {
+	case nil:
+		return openrpc.SimpleTypeNull
+	case bool:
+		return openrpc.SimpleTypeBoolean
+	case string:
+		return openrpc.SimpleTypeString
+	case float64:
+		if v == math.Trunc(v) {
+			return openrpc.SimpleTypeInteger
+		}
+		return openrpc.SimpleTypeNumber
+	case map[string]interface{}:
+		return openrpc.SimpleTypeObject
+	case []interface{}:
+		return openrpc.SimpleTypeArray
+	}
+
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return openrpc.SimpleTypeInteger
+	case reflect.Float32:
+		return openrpc.SimpleTypeNumber
+	}
+
+	return ""
+}
+
+// resolveSchema finds schema in components by reference
+func resolveSchema(ref string, doc *openrpc.OpenrpcDocument) *openrpc.JSONSchemaObject {
+	const prefix = "#/components/schemas/"
+	if !strings.HasPrefix(ref, prefix) || doc == nil || doc.Components == nil || doc.Components.Schemas == nil {
+		return nil
+	}
+
+	if schema, ok := doc.Components.Schemas.Get(strings.TrimPrefix(ref, prefix)); ok {
+		return schema.JSONSchemaObject
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package rpcdiff
+
+import (
+	"encoding/json"
+	"testing"
+
+	openrpc "github.com/vmkteam/meta-schema/v2"
+)
+
+func Test_validateValue(t *testing.T) {
+	doc := &openrpc.OpenrpcDocument{
+		Components: &openrpc.Components{
+			Schemas: &openrpc.SchemaMap{},
+		},
+	}
+	doc.Components.Schemas.Add("User", openrpc.JSONSchema{JSONSchemaObject: &openrpc.JSONSchemaObject{
+		Type:     &openrpc.Type{SimpleType: "object"},
+		Required: []string{"id"},
+		Properties: &openrpc.SchemaMap{
+			{JSONSchemaObject: &openrpc.JSONSchemaObject{Id: "id", Type: &openrpc.Type{SimpleType: "integer"}}},
+			{JSONSchemaObject: &openrpc.JSONSchemaObject{Id: "status", Enum: []interface{}{"active", "blocked"}}},
+		},
+	}})
+
+	tests := []struct {
+		name    string
+		schema  string
+		value   string
+		wantErr bool
+	}{
+		{name: "valid integer", schema: `{"type": "integer"}`, value: `1`},
+		{name: "integer is a number", schema: `{"type": "number"}`, value: `1`},
+		{name: "number is not an integer", schema: `{"type": "integer"}`, value: `1.5`, wantErr: true},
+		{name: "nullable string", schema: `{"type": ["string", "null"]}`, value: `null`},
+		{name: "valid reference", schema: `{"$ref": "#/components/schemas/User"}`, value: `{"id": 1, "status": "active"}`},
+		{name: "missing required property", schema: `{"$ref": "#/components/schemas/User"}`, value: `{"status": "active"}`, wantErr: true},
+		{name: "value not in enum", schema: `{"$ref": "#/components/schemas/User"}`, value: `{"id": 1, "status": "deleted"}`, wantErr: true},
+		{name: "invalid array item", schema: `{"type": "array", "items": {"type": "string"}}`, value: `["a", 1]`, wantErr: true},
+		{name: "one of schemas", schema: `{"oneOf": [{"type": "string"}, {"type": "integer"}]}`, value: `1`},
+		{name: "none of oneOf schemas", schema: `{"oneOf": [{"type": "string"}, {"type": "boolean"}]}`, value: `1`, wantErr: true},
+		{name: "several of oneOf schemas", schema: `{"oneOf": [{"type": "number"}, {"type": "integer"}]}`, value: `1`, wantErr: true},
+		{name: "several of anyOf schemas", schema: `{"anyOf": [{"type": "number"}, {"type": "integer"}]}`, value: `1`},
+		{name: "unresolved reference", schema: `{"$ref": "#/components/schemas/Unknown"}`, value: `1`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var schema openrpc.JSONSchemaObject
+			if err := json.Unmarshal([]byte(tt.schema), &schema); err != nil {
+				t.Fatalf("unmarshal schema error: %s", err)
+			}
+
+			var value interface{}
+			if err := json.Unmarshal([]byte(tt.value), &value); err != nil {
+				t.Fatalf("unmarshal value error: %s", err)
+			}
+
+			if err := validateValue(&schema, value, doc); (err != nil) != tt.wantErr {
+				t.Errorf("validateValue() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
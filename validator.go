@@ -0,0 +1,129 @@
+package rpcdiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	openrpc "github.com/vmkteam/meta-schema/v2"
+)
+
+// Validator validates json-rpc requests and responses against openrpc document
+type Validator struct {
+	doc *openrpc.OpenrpcDocument
+}
+
+// ValidationError lists problems of request or response
+type ValidationError struct {
+	Method string
+	Errors []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Method, strings.Join(e.Errors, "; "))
+}
+
+// NewValidator parses openrpc schema
+func NewValidator(schema []byte) (*Validator, error) {
+	doc, err := parseDocument(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Validator{doc: doc}, nil
+}
+
+// ValidateRequest validates method and params of json-rpc request, *ValidationError is returned for invalid request
+func (v *Validator) ValidateRequest(data []byte) error {
+	var req struct {
+		Method string      `json:"method"`
+		Params interface{} `json:"params"`
+	}
+	if err := json.Unmarshal(data, &req); err != nil {
+		return err
+	}
+
+	method := findMethod(v.doc, req.Method)
+	if method == nil {
+		return &ValidationError{Method: req.Method, Errors: []string{fmt.Sprintf(`method "%s" is not found`, req.Method)}}
+	}
+
+	var errs []string
+	byName, _ := req.Params.(map[string]interface{})
+	byPosition, _ := req.Params.([]interface{})
+	for i, param := range method.Params {
+		cd := v.contentDescriptor(param.ContentDescriptorObject, param.ReferenceObject)
+		if cd == nil {
+			continue
+		}
+
+		value, ok := byName[cd.Name]
+		if byPosition != nil {
+			ok = i < len(byPosition)
+			if ok {
+				value = byPosition[i]
+			}
+		}
+
+		if !ok {
+			if cd.Required {
+				errs = append(errs, fmt.Sprintf(`required arg "%s" is missing`, cd.Name))
+			}
+			continue
+		}
+
+		if err := validateValue(getSchemaObject(cd.Schema), value, v.doc); err != nil {
+			errs = append(errs, fmt.Sprintf(`arg "%s": %s`, cd.Name, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return &ValidationError{Method: req.Method, Errors: errs}
+	}
+
+	return nil
+}
+
+// ValidateResponse validates result of json-rpc response to method, error responses are valid
+func (v *Validator) ValidateResponse(methodName string, data []byte) error {
+	method := findMethod(v.doc, methodName)
+	if method == nil {
+		return &ValidationError{Method: methodName, Errors: []string{fmt.Sprintf(`method "%s" is not found`, methodName)}}
+	}
+
+	var resp struct {
+		Result *json.RawMessage `json:"result"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return err
+	}
+
+	if resp.Result == nil || method.Result == nil {
+		return nil
+	}
+
+	cd := v.contentDescriptor(method.Result.ContentDescriptorObject, method.Result.ReferenceObject)
+	if cd == nil {
+		return nil
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(*resp.Result, &value); err != nil {
+		return err
+	}
+
+	if err := validateValue(getSchemaObject(cd.Schema), value, v.doc); err != nil {
+		return &ValidationError{Method: methodName, Errors: []string{fmt.Sprintf("result: %s", err)}}
+	}
+
+	return nil
+}
+
+// contentDescriptor returns descriptor or resolves its reference
+func (v *Validator) contentDescriptor(cd *openrpc.ContentDescriptorObject, ref *openrpc.ReferenceObject) *openrpc.ContentDescriptorObject {
+	if ref != nil {
+		return resolveContentDescriptor(ref.Ref, v.doc)
+	}
+
+	return cd
+}
@@ -0,0 +1,54 @@
+package rpcdiff
+
+import (
+	"errors"
+	"io/ioutil"
+	"testing"
+)
+
+func TestValidator(t *testing.T) {
+	schema, err := ioutil.ReadFile("testdata/openrpc_new.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v, err := NewValidator(schema)
+	if err != nil {
+		t.Fatalf("new validator error: %s", err)
+	}
+
+	tests := []struct {
+		name    string
+		method  string
+		req     string
+		resp    string
+		wantErr string
+	}{
+		{name: "valid", method: "check.ChangeTypeParam", req: `{"method": "check.ChangeTypeParam", "params": {"param1": 1}}`, resp: `{"result": null}`},
+		{name: "positional", method: "check.AddRequiredParam", req: `{"method": "check.AddRequiredParam", "params": [1, 2]}`, resp: `{"error": {"code": 1}}`},
+		{name: "invalid param", method: "check.ChangeTypeParam", req: `{"method": "check.ChangeTypeParam", "params": {"param1": "1"}}`, wantErr: `check.ChangeTypeParam: arg "param1": $: expected integer, got string`},
+		{name: "invalid result", method: "check.RemoveParam", req: `{"method": "check.RemoveParam"}`, resp: `{"result": true}`, wantErr: `check.RemoveParam: result: $: expected null, got boolean`},
+		{name: "unknown method", method: "check.Unknown", req: `{"method": "check.Unknown"}`, wantErr: `check.Unknown: method "check.Unknown" is not found`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.ValidateRequest([]byte(tt.req))
+			if err == nil && tt.resp != "" {
+				err = v.ValidateResponse(tt.method, []byte(tt.resp))
+			}
+
+			var got string
+			if err != nil {
+				var ve *ValidationError
+				if !errors.As(err, &ve) {
+					t.Fatalf("error %v is not ValidationError", err)
+				}
+				got = err.Error()
+			}
+
+			if got != tt.wantErr {
+				t.Errorf("validation error = %v, want %v", got, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package rpcdiff
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// warningsNGIssue is an issue of Jenkins Warnings NG native json format
+type warningsNGIssue struct {
+	FileName    string `json:"fileName"`
+	Severity    string `json:"severity"`
+	Message     string `json:"message"`
+	Category    string `json:"category"`
+	Type        string `json:"type"`
+	PackageName string `json:"packageName"`
+	Fingerprint string `json:"fingerprint"`
+	Description string `json:"description,omitempty"`
+}
+
+// warningsNGSeverity maps criticality to severity: breaking and above are HIGH, non breaking and below LOW, others NORMAL
+func warningsNGSeverity(taxonomy Taxonomy, level CriticalityLevel) string {
+	switch rank := taxonomy.rank(level); {
+	case rank <= taxonomy.rank(Breaking):
+		return "HIGH"
+	case rank < taxonomy.rank(NonBreaking):
+		return "NORMAL"
+	default:
+		return "LOW"
+	}
+}
+
+// warningsNGDescription returns reason of change with spec reference
+func warningsNGDescription(c Change) string {
+	if c.Reference == "" {
+		return c.Reason
+	}
+
+	return strings.TrimPrefix(c.Reason+", see "+c.Reference, ", ")
+}
+
+// warningsNGReport renders changes, policy violations and budget warnings as Warnings NG issues of schema file
+func warningsNGReport(diff *Diff, file string) (string, error) {
+	taxonomy := diff.Options.taxonomy()
+	issues := make([]warningsNGIssue, 0, len(diff.Changes)+len(diff.Violations))
+	for _, c := range diff.Changes {
+		issues = append(issues, warningsNGIssue{
+			FileName:    file,
+			Severity:    warningsNGSeverity(taxonomy, c.Criticality),
+			Message:     c.String(),
+			Category:    string(c.Object),
+			Type:        string(c.Type),
+			PackageName: changeSubject(c),
+			Fingerprint: c.Fingerprint,
+			Description: warningsNGDescription(c),
+		})
+	}
+
+	for _, v := range diff.Violations {
+		issues = append(issues, warningsNGIssue{
+			FileName: file,
+			Severity: "ERROR",
+			Message:  v.String(),
+			Category: "Policy",
+			Type:     v.Rule,
+		})
+	}
+
+	for _, v := range diff.Budget {
+		issues = append(issues, warningsNGIssue{
+			FileName: file,
+			Severity: "NORMAL",
+			Message:  v.String(),
+			Category: "Budget",
+			Type:     v.Rule,
+		})
+	}
+
+	data, err := json.MarshalIndent(struct {
+		Metadata Metadata          `json:"metadata"`
+		Old      Document          `json:"old"`
+		New      Document          `json:"new"`
+		Issues   []warningsNGIssue `json:"issues"`
+	}{diff.Metadata, diff.Old, diff.New, issues}, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(data) + "\n", nil
+}
@@ -0,0 +1,49 @@
+package rpcdiff
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestWarningsNGReport(t *testing.T) {
+	diff, err := NewDiff("testdata/openrpc_old.json", "testdata/openrpc_new.json", Options{})
+	if err != nil {
+		t.Fatalf("new diff error: %s", err)
+	}
+
+	out, err := RenderDiff(diff, FormatWarningsNG, "openrpc.json")
+	if err != nil {
+		t.Fatalf("RenderDiff() error: %s", err)
+	}
+
+	var report struct {
+		Issues []warningsNGIssue `json:"issues"`
+	}
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatalf("unmarshal error: %s", err)
+	}
+
+	if len(report.Issues) != len(diff.Changes) {
+		t.Fatalf("warningsNGReport() issues = %v, want %v", len(report.Issues), len(diff.Changes))
+	}
+
+	severities := map[CriticalityLevel]string{Breaking: "HIGH", Dangerous: "NORMAL", PossiblyBreaking: "NORMAL", NonBreaking: "LOW", Info: "LOW"}
+	for i, issue := range report.Issues {
+		c := diff.Changes[i]
+		if issue.Severity != severities[c.Criticality] || issue.Message != c.String() || issue.Fingerprint != c.Fingerprint || issue.FileName != "openrpc.json" {
+			t.Errorf("issue #%d = %+v, wanted change %v", i, issue, c.String())
+		}
+	}
+}
+
+func TestFormat_Validate(t *testing.T) {
+	for _, format := range formats {
+		if err := format.Validate(); err != nil {
+			t.Errorf("Validate(%v) error: %s", format, err)
+		}
+	}
+
+	if err := Format("xml").Validate(); err == nil {
+		t.Errorf("Validate(xml) wanted error")
+	}
+}